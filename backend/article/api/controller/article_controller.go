@@ -4,22 +4,31 @@ import (
 	"net/http"
 	"strconv"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
 	"github.com/yuhari7/backend_supervision/article/internal/usecase"
+	"github.com/yuhari7/backend_supervision/shared/auth"
 )
 
+// ArticleController handles the article endpoints, request bodies and parameters are validated
+// against the OpenAPI document by middleware before they reach the handlers
 type ArticleController struct {
-	ArticleUsecase usecase.ArticleUsecase
-	Validator      *validator.Validate
+	ArticleUsecase     usecase.ArticleUsecase
+	TranslationUsecase usecase.TranslationUsecase
+	RelatedUsecase     usecase.RelatedArticleUsecase
+	DuplicateDetector  usecase.DuplicateDetector
+	ViewRecorder       usecase.ViewRecorder
 }
 
 // NewArticleController creates a new instance of ArticleController
-func NewArticleController(articleUsecase usecase.ArticleUsecase) *ArticleController {
+func NewArticleController(articleUsecase usecase.ArticleUsecase, translationUsecase usecase.TranslationUsecase, relatedUsecase usecase.RelatedArticleUsecase, duplicateDetector usecase.DuplicateDetector, viewRecorder usecase.ViewRecorder) *ArticleController {
 	return &ArticleController{
-		ArticleUsecase: articleUsecase,
-		Validator:      validator.New(),
+		ArticleUsecase:     articleUsecase,
+		TranslationUsecase: translationUsecase,
+		RelatedUsecase:     relatedUsecase,
+		DuplicateDetector:  duplicateDetector,
+		ViewRecorder:       viewRecorder,
 	}
 }
 
@@ -29,31 +38,20 @@ func (c *ArticleController) Create(ctx echo.Context) error {
 
 	// Bind the incoming request body to the DTO
 	if err := ctx.Bind(&request); err != nil {
-		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request"})
-	}
-
-	// Validate the request data
-	if err := c.Validator.Struct(&request); err != nil {
-		errorMessages := make(map[string]string)
-		for _, err := range err.(validator.ValidationErrors) {
-			switch err.Field() {
-			case "Title":
-				errorMessages["title"] = "Title Kurang dari 20 Character"
-			case "Content":
-				errorMessages["content"] = "Description Minimal 200 Character"
-			case "Category":
-				errorMessages["category"] = "Category minimal 3 Character"
-			default:
-				errorMessages[err.Field()] = err.Error()
-			}
-		}
-		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": errorMessages})
+		return errInvalidRequest
+	}
+	if err := requirePublish(ctx, request.Status); err != nil {
+		return err
+	}
+	// The author is whoever is logged in, never taken from the body
+	if principal, ok := auth.PrincipalFrom(ctx.Request().Context()); ok {
+		request.AuthorID = &principal.UserID
 	}
 
 	// Call the usecase to create the article
 	article, err := c.ArticleUsecase.CreateArticle(request)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusCreated, article)
@@ -76,10 +74,10 @@ func (c *ArticleController) GetAll(ctx echo.Context) error {
 		offset = 0
 	}
 
-	// Execute the find all articles usecase with pagination
-	articles, err := c.ArticleUsecase.FindAllArticles(limit, offset)
+	// Execute the find all articles usecase with pagination, optionally filtered by locale
+	articles, err := c.ArticleUsecase.FindAllArticles(limit, offset, ctx.QueryParam("locale"))
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+		return err
 	}
 
 	// Return the paginated list of articles
@@ -93,51 +91,73 @@ func (c *ArticleController) FindByID(ctx echo.Context) error {
 	// Convert the string ID to uint
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": "invalid article ID"})
+		return errInvalidArticleID
 	}
 
-	// Execute the find article usecase
-	article, err := c.ArticleUsecase.FindByID(uint(id))
+	// Execute the find article usecase, resolving the translation when a locale is requested
+	var article dto.ArticleResponse
+	if locale := ctx.QueryParam("locale"); locale != "" {
+		article, err = c.TranslationUsecase.FindLocalized(uint(id), locale)
+	} else {
+		article, err = c.ArticleUsecase.FindByID(uint(id))
+	}
 	if err != nil {
-		return ctx.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+		return err
 	}
 
+	// Record the view in the background buffer, identifying the client by IP and user agent
+	c.ViewRecorder.Record(article.ID, ctx.RealIP()+"|"+ctx.Request().UserAgent())
+
 	// Return the article details
 	return ctx.JSON(http.StatusOK, article)
 }
 
+// Related handles retrieving published articles similar to the given article
+func (c *ArticleController) Related(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		return errInvalidArticleID
+	}
+
+	limit, _ := strconv.Atoi(ctx.QueryParam("limit"))
+
+	related, err := c.RelatedUsecase.GetRelated(uint(id), limit)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, related)
+}
+
+// Duplicates handles retrieving the articles whose content is nearly identical to the given article
+func (c *ArticleController) Duplicates(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		return errInvalidArticleID
+	}
+
+	duplicates, err := c.DuplicateDetector.GetDuplicates(uint(id))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, duplicates)
+}
+
 func (c *ArticleController) Update(ctx echo.Context) error {
 	var request dto.UpdateArticleRequest
 
 	if err := ctx.Bind(&request); err != nil {
-		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request"})
-	}
-
-	// Validate the request data
-	if err := c.Validator.Struct(&request); err != nil {
-		errorMessages := make(map[string]string)
-
-		for _, err := range err.(validator.ValidationErrors) {
-			switch err.Field() {
-			case "Title":
-				errorMessages["title"] = "Title Kurang dari 20 Character"
-			case "Content":
-				errorMessages["content"] = "Description Minimal 200 Character"
-			case "Category":
-				errorMessages["category"] = "Category minimal 3 Character"
-			default:
-				errorMessages[err.Field()] = err.Error()
-			}
-		}
-
-		// Return validation errors
-		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": errorMessages})
+		return errInvalidRequest
+	}
+	if err := requirePublish(ctx, request.Status); err != nil {
+		return err
 	}
 
 	// Execute the update article usecase
 	article, err := c.ArticleUsecase.UpdateArticle(request)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, article) // Return the updated article
@@ -149,7 +169,7 @@ func (c *ArticleController) SoftDelete(ctx echo.Context) error {
 	// Convert the string ID to uint
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": "invalid article ID"})
+		return errInvalidArticleID
 	}
 
 	// Set the ID for the soft delete request
@@ -160,7 +180,7 @@ func (c *ArticleController) SoftDelete(ctx echo.Context) error {
 	// Execute the soft delete article usecase
 	article, err := c.ArticleUsecase.SoftDeleteArticle(dto)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, article) // Return the updated article
@@ -174,21 +194,21 @@ func (c *ArticleController) Delete(ctx echo.Context) error {
 	// Convert the string ID to uint
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, echo.Map{"error": "invalid article ID"})
+		return errInvalidArticleID
 	}
 
 	// Set the ID for the delete request
-	dto := dto.SoftDeleteArticleDTO{
+	request := dto.SoftDeleteArticleDTO{
 		ID: uint(id),
 	}
 
 	// Execute the permanent delete article usecase
-	err = c.ArticleUsecase.DeleteArticle(dto)
+	err = c.ArticleUsecase.DeleteArticle(request)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+		return err
 	}
 
-	return ctx.JSON(http.StatusOK, echo.Map{"message": "Article deleted permanently"})
+	return ctx.JSON(http.StatusOK, dto.MessageResponse{Message: "Article deleted permanently"})
 }
 
 func (c *ArticleController) Search(ctx echo.Context) error {
@@ -210,14 +230,22 @@ func (c *ArticleController) Search(ctx echo.Context) error {
 		}
 	}
 
-	if query == "" {
-		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Query parameter 'q' is required"})
-	}
-
 	articles, err := c.ArticleUsecase.SearchArticles(query, limit, offset)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, articles)
 }
+
+// requirePublish refuses to publish for callers without the articles:publish permission
+func requirePublish(ctx echo.Context, status string) error {
+	if status != entity.StatusPublish {
+		return nil
+	}
+	principal, ok := auth.PrincipalFrom(ctx.Request().Context())
+	if !ok || !principal.Can("articles:publish") {
+		return auth.ErrInsufficientPrivileges
+	}
+	return nil
+}
@@ -2,23 +2,31 @@ package controller
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/article/api/middleware"
+	"github.com/yuhari7/backend_supervision/shared/auth"
 )
 
-// RegisterArticleRoutes sets up the routes for article-related endpoints
-func RegisterArticleRoutes(e *echo.Group, controller *ArticleController) {
-	// e.GET("/articles", controller.GetAll)
-
+// RegisterArticleRoutes sets up the routes for article-related endpoints.
+// Reads are public, writes require the articles permission matching the action.
+func RegisterArticleRoutes(e *echo.Group, controller *ArticleController, authenticator auth.Authenticator) {
 	articleGroup := e.Group("/articles")
 
 	articleGroup.GET("", controller.GetAll)
 
 	articleGroup.GET("/search", controller.Search)
 	articleGroup.GET("/:id", controller.FindByID)
+	articleGroup.GET("/:id/related", controller.Related)
+	articleGroup.GET("/:id/duplicates", controller.Duplicates)
+	articleGroup.GET("/:id/translations", controller.TranslationStatus)
 
-	articleGroup.POST("", controller.Create)
+	// Publishing additionally requires articles:publish, checked by the handlers against the requested status
+	create := middleware.AuthMiddleware(authenticator, "articles:create")
+	articleGroup.POST("", controller.Create, create)
+	articleGroup.POST("/:id/translations", controller.CreateTranslation, create)
 
-	articleGroup.PUT("/:id", controller.Update)
-	articleGroup.PUT("/:id/trash", controller.SoftDelete)
+	articleGroup.PUT("/:id", controller.Update, middleware.AuthMiddleware(authenticator, "articles:update"))
 
-	articleGroup.DELETE("/:id", controller.Delete)
+	remove := middleware.AuthMiddleware(authenticator, "articles:delete")
+	articleGroup.PUT("/:id/trash", controller.SoftDelete, remove)
+	articleGroup.DELETE("/:id", controller.Delete, remove)
 }
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/usecase"
+	"github.com/yuhari7/backend_supervision/shared/auth"
+	"github.com/yuhari7/backend_supervision/shared/i18n"
+	"github.com/yuhari7/backend_supervision/shared/problem"
+)
+
+// createOnlyUsecase accepts every article, the other methods are not reached by the tests
+type createOnlyUsecase struct {
+	usecase.ArticleUsecase
+}
+
+func (createOnlyUsecase) CreateArticle(request dto.CreateArticleRequest) (dto.ArticleWriteResponse, error) {
+	return dto.ArticleWriteResponse{}, nil
+}
+
+// tokenAuthenticator maps the tokens of the test to claims
+type tokenAuthenticator map[string]auth.Claims
+
+func (a tokenAuthenticator) Authenticate(token string) (*auth.Claims, error) {
+	claims, ok := a[token]
+	if !ok {
+		return nil, auth.ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+func TestArticleWritesRequirePermissions(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = problem.NewHTTPErrorHandler(i18n.MustNew("id"))
+	RegisterArticleRoutes(e.Group("/api"), &ArticleController{ArticleUsecase: createOnlyUsecase{}}, tokenAuthenticator{
+		"contributor": {UserID: 2, Role: "contributor", Permissions: []string{"articles:create", "articles:update"}},
+		"reader":      {UserID: 3, Role: "reader"},
+	})
+
+	draft := `{"title":"A title long enough to pass","status":"Draft"}`
+	published := `{"title":"A title long enough to pass","status":"Publish"}`
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   string
+		want   int
+	}{
+		{"create without token", http.MethodPost, "/api/articles", "", draft, http.StatusUnauthorized},
+		{"create with forged token", http.MethodPost, "/api/articles", "forged", draft, http.StatusUnauthorized},
+		{"create without permission", http.MethodPost, "/api/articles", "reader", draft, http.StatusForbidden},
+		{"create a draft", http.MethodPost, "/api/articles", "contributor", draft, http.StatusCreated},
+		{"publish without permission", http.MethodPost, "/api/articles", "contributor", published, http.StatusForbidden},
+		{"update without token", http.MethodPut, "/api/articles/1", "", draft, http.StatusUnauthorized},
+		{"publish an update without permission", http.MethodPut, "/api/articles/1", "contributor", published, http.StatusForbidden},
+		{"translate without token", http.MethodPost, "/api/articles/1/translations", "", draft, http.StatusUnauthorized},
+		{"trash without permission", http.MethodPut, "/api/articles/1/trash", "contributor", "", http.StatusForbidden},
+		{"delete without token", http.MethodDelete, "/api/articles/1", "", "", http.StatusUnauthorized},
+		{"delete without permission", http.MethodDelete, "/api/articles/1", "contributor", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if tt.token != "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s: got status %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
+
+// recordingUsecase keeps the last article it was asked to create
+type recordingUsecase struct {
+	usecase.ArticleUsecase
+	created dto.CreateArticleRequest
+}
+
+func (u *recordingUsecase) CreateArticle(request dto.CreateArticleRequest) (dto.ArticleWriteResponse, error) {
+	u.created = request
+	return dto.ArticleWriteResponse{}, nil
+}
+
+func TestCreateTakesAuthorFromToken(t *testing.T) {
+	articles := &recordingUsecase{}
+	e := echo.New()
+	RegisterArticleRoutes(e.Group("/api"), &ArticleController{ArticleUsecase: articles}, tokenAuthenticator{
+		"contributor": {UserID: 2, Role: "contributor", Permissions: []string{"articles:create"}},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/articles", strings.NewReader(`{"title":"A title long enough to pass","status":"Draft","author_id":1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer contributor")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated || articles.created.AuthorID == nil || *articles.created.AuthorID != 2 {
+		t.Errorf("got status %d and author %v, want the article created by user 2 rather than the author in the body", rec.Code, articles.created.AuthorID)
+	}
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/article/internal/usecase"
+)
+
+type ArticleStatsController struct {
+	StatsUsecase usecase.ArticleStatsUsecase
+}
+
+// NewArticleStatsController creates a new instance of ArticleStatsController
+func NewArticleStatsController(statsUsecase usecase.ArticleStatsUsecase) *ArticleStatsController {
+	return &ArticleStatsController{StatsUsecase: statsUsecase}
+}
+
+// ArticleStats handles retrieving the daily view time series of an article
+func (c *ArticleStatsController) ArticleStats(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		return errInvalidArticleID
+	}
+
+	// Number of days to include, defaults to 30 in the usecase
+	days, _ := strconv.Atoi(ctx.QueryParam("days"))
+
+	stats, err := c.StatsUsecase.GetArticleStats(uint(id), days)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, stats)
+}
+
+// TopArticles handles retrieving the most viewed articles per category
+func (c *ArticleStatsController) TopArticles(ctx echo.Context) error {
+	period := ctx.QueryParam("period")
+	if period == "" {
+		period = "7d"
+	}
+
+	limit, _ := strconv.Atoi(ctx.QueryParam("limit"))
+
+	top, err := c.StatsUsecase.GetTopArticles(period, limit)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, top)
+}
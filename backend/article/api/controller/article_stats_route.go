@@ -0,0 +1,11 @@
+package controller
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// RegisterStatsRoutes sets up the routes for article view statistics
+func RegisterStatsRoutes(e *echo.Group, controller *ArticleStatsController) {
+	e.GET("/articles/:id/stats", controller.ArticleStats)
+	e.GET("/stats/top", controller.TopArticles)
+}
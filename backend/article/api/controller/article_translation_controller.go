@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+)
+
+// CreateTranslation handles translating an article into another locale
+func (c *ArticleController) CreateTranslation(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		return errInvalidArticleID
+	}
+
+	var request dto.CreateTranslationRequest
+	if err := ctx.Bind(&request); err != nil {
+		return errInvalidRequest
+	}
+	if err := requirePublish(ctx, request.Status); err != nil {
+		return err
+	}
+
+	article, err := c.TranslationUsecase.CreateTranslation(uint(id), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, article)
+}
+
+// TranslationStatus handles listing which translations of an article are missing or out of date
+func (c *ArticleController) TranslationStatus(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		return errInvalidArticleID
+	}
+
+	status, err := c.TranslationUsecase.GetTranslationStatus(uint(id))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,11 @@
+package controller
+
+import "github.com/yuhari7/backend_supervision/shared/apperror"
+
+// Errors returned by handlers for malformed requests, rendered by the problem error handler
+var (
+	errInvalidRequest   = apperror.Validation("invalid_request", "invalid request", nil)
+	errInvalidArticleID = apperror.Validation("invalid_article_id", "invalid article ID", nil)
+	errInvalidWebhookID = apperror.Validation("invalid_webhook_id", "invalid webhook ID", nil)
+	errInvalidDelivery  = apperror.Validation("invalid_delivery_id", "invalid delivery ID", nil)
+)
@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/shared/openapi"
+)
+
+// Spec documents every route registered by RegisterArticleRoutes, RegisterStatsRoutes and RegisterWebhookRoutes under /api
+// and the GraphQL endpoint registered by graphqlapi.RegisterRoutes.
+// Request and response schemas are generated from the DTO structs the handlers use.
+func Spec() *openapi.Document {
+	doc := openapi.New("Article Service", "1.0.0")
+	doc.PathParam("id", "Article ID", openapi.Integer(1))
+
+	limit := openapi.QueryParam("limit", "Maximum number of results", openapi.Integer(1))
+	offset := openapi.QueryParam("offset", "Number of results to skip", openapi.Integer(0))
+	locale := openapi.QueryParam("locale", "Language code, e.g. id or en", openapi.String())
+
+	// Articles
+	articles := []string{"articles"}
+	doc.Add(http.MethodGet, "/api/articles", openapi.Route{
+		Summary:  "List articles",
+		Tags:     articles,
+		Query:    []openapi.Parameter{limit, offset, locale},
+		Response: []dto.ArticleResponse{},
+	})
+	doc.Add(http.MethodGet, "/api/articles/search", openapi.Route{
+		Summary:  "Search articles by title, content, status or category",
+		Tags:     articles,
+		Query:    []openapi.Parameter{openapi.RequiredQueryParam("q", "Search text", openapi.String()), limit, offset},
+		Response: []dto.ArticleResponse{},
+		Errors:   []int{http.StatusBadRequest},
+	})
+	doc.Add(http.MethodGet, "/api/articles/:id", openapi.Route{
+		Summary:  "Get an article, optionally in another locale",
+		Tags:     articles,
+		Query:    []openapi.Parameter{locale},
+		Response: dto.ArticleResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+	doc.Add(http.MethodPost, "/api/articles", openapi.Route{
+		Summary:  "Create an article, publishing it requires the articles:publish permission",
+		Tags:     articles,
+		Body:     dto.CreateArticleRequest{},
+		Response: dto.ArticleWriteResponse{},
+		Status:   http.StatusCreated,
+		Errors:   []int{http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPut, "/api/articles/:id", openapi.Route{
+		Summary:  "Update an article",
+		Tags:     articles,
+		Body:     dto.UpdateArticleRequest{},
+		Response: dto.ArticleWriteResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPut, "/api/articles/:id/trash", openapi.Route{
+		Summary:  "Move an article to the trash",
+		Tags:     articles,
+		Response: entity.Article{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+	doc.Add(http.MethodDelete, "/api/articles/:id", openapi.Route{
+		Summary:  "Permanently delete an article from the trash",
+		Tags:     articles,
+		Response: dto.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		Secured:  true,
+	})
+	doc.Add(http.MethodGet, "/api/articles/:id/related", openapi.Route{
+		Summary:  "List published articles related to an article",
+		Tags:     articles,
+		Query:    []openapi.Parameter{limit},
+		Response: []dto.RelatedArticleResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+	doc.Add(http.MethodGet, "/api/articles/:id/duplicates", openapi.Route{
+		Summary:  "List articles with nearly identical content",
+		Tags:     articles,
+		Response: []dto.DuplicateMatch{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+
+	// Translations
+	translations := []string{"translations"}
+	doc.Add(http.MethodPost, "/api/articles/:id/translations", openapi.Route{
+		Summary:  "Translate an article into another locale",
+		Tags:     translations,
+		Body:     dto.CreateTranslationRequest{},
+		Response: dto.ArticleWriteResponse{},
+		Status:   http.StatusCreated,
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+		Secured:  true,
+	})
+	doc.Add(http.MethodGet, "/api/articles/:id/translations", openapi.Route{
+		Summary:  "List missing and outdated translations of an article",
+		Tags:     translations,
+		Response: dto.TranslationStatusResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+
+	// Statistics
+	stats := []string{"stats"}
+	doc.Add(http.MethodGet, "/api/articles/:id/stats", openapi.Route{
+		Summary:  "Daily views of an article",
+		Tags:     stats,
+		Query:    []openapi.Parameter{openapi.QueryParam("days", "Number of days, defaults to 30", openapi.Integer(1))},
+		Response: dto.ArticleStatsResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+	doc.Add(http.MethodGet, "/api/stats/top", openapi.Route{
+		Summary: "Most viewed articles per category",
+		Tags:    stats,
+		Query: []openapi.Parameter{
+			openapi.QueryParam("period", "Period in days such as 7d, defaults to 7d", &openapi.Schema{Type: "string", Pattern: `^\d+d$`}),
+			limit,
+		},
+		Response: dto.TopArticlesResponse{},
+		Errors:   []int{http.StatusBadRequest},
+	})
+
+	// Webhooks, requires the webhooks:manage permission
+	webhooks := []string{"webhooks"}
+	doc.PathParam("webhook_id", "Webhook subscription ID", openapi.Integer(1))
+	doc.PathParam("delivery_id", "Webhook delivery ID", openapi.Integer(1))
+	doc.Add(http.MethodGet, "/api/webhooks", openapi.Route{
+		Summary:  "List webhook subscriptions",
+		Tags:     webhooks,
+		Response: []entity.WebhookSubscription{},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPost, "/api/webhooks", openapi.Route{
+		Summary:  "Subscribe an endpoint to article events, the response holds the signing secret",
+		Tags:     webhooks,
+		Body:     dto.CreateWebhookRequest{},
+		Response: dto.WebhookResponse{},
+		Status:   http.StatusCreated,
+		Errors:   []int{http.StatusBadRequest},
+		Secured:  true,
+	})
+	doc.Add(http.MethodGet, "/api/webhooks/:webhook_id", openapi.Route{
+		Summary:  "Get a webhook subscription",
+		Tags:     webhooks,
+		Response: entity.WebhookSubscription{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPut, "/api/webhooks/:webhook_id", openapi.Route{
+		Summary:  "Update a webhook subscription, activating it again resets its failures",
+		Tags:     webhooks,
+		Body:     dto.UpdateWebhookRequest{},
+		Response: entity.WebhookSubscription{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+	doc.Add(http.MethodDelete, "/api/webhooks/:webhook_id", openapi.Route{
+		Summary:  "Delete a webhook subscription and its delivery log",
+		Tags:     webhooks,
+		Response: dto.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+	doc.Add(http.MethodGet, "/api/webhooks/:webhook_id/deliveries", openapi.Route{
+		Summary:  "Delivery log of a webhook subscription, newest first",
+		Tags:     webhooks,
+		Query:    []openapi.Parameter{limit, offset},
+		Response: []entity.WebhookDelivery{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPost, "/api/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", openapi.Route{
+		Summary:  "Send a delivery again",
+		Tags:     webhooks,
+		Response: entity.WebhookDelivery{},
+		Status:   http.StatusAccepted,
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+
+	// GraphQL
+	graphql := []string{"graphql"}
+	doc.Add(http.MethodGet, "/graphql", openapi.Route{
+		Summary: "Run a GraphQL query passed in the query string",
+		Tags:    graphql,
+		Query: []openapi.Parameter{
+			openapi.RequiredQueryParam("query", "GraphQL query", openapi.String()),
+			openapi.QueryParam("operationName", "Operation to run when the query has several", openapi.String()),
+			openapi.QueryParam("variables", "Variables encoded as a JSON object", openapi.String()),
+		},
+		Response: dto.GraphQLResponse{},
+		Errors:   []int{http.StatusBadRequest},
+	})
+	doc.Add(http.MethodPost, "/graphql", openapi.Route{
+		Summary:  "Run a GraphQL query",
+		Tags:     graphql,
+		Body:     dto.GraphQLRequest{},
+		Response: dto.GraphQLResponse{},
+		Errors:   []int{http.StatusBadRequest},
+	})
+
+	return doc
+}
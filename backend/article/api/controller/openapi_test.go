@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	graphqlapi "github.com/yuhari7/backend_supervision/article/api/graphql"
+)
+
+func TestSpecCoversRoutes(t *testing.T) {
+	e := echo.New()
+	api := e.Group("/api")
+	RegisterArticleRoutes(api, &ArticleController{}, nil)
+	RegisterStatsRoutes(api, &ArticleStatsController{})
+	RegisterWebhookRoutes(api, &WebhookController{}, nil)
+	graphqlapi.RegisterRoutes(e, &graphqlapi.Handler{})
+
+	doc := Spec()
+	for _, route := range doc.Undocumented(e.Routes()) {
+		t.Errorf("route %s is registered without an OpenAPI operation", route)
+	}
+	for _, operation := range doc.Unregistered(e.Routes()) {
+		t.Errorf("operation %s is documented but no route is registered", operation)
+	}
+
+	if _, err := doc.JSON(); err != nil {
+		t.Fatalf("encoding spec: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/usecase"
+)
+
+// WebhookController handles the admin endpoints managing webhook subscriptions
+type WebhookController struct {
+	WebhookUsecase usecase.WebhookUsecase
+}
+
+// NewWebhookController creates a new instance of WebhookController
+func NewWebhookController(webhookUsecase usecase.WebhookUsecase) *WebhookController {
+	return &WebhookController{WebhookUsecase: webhookUsecase}
+}
+
+// List handles retrieving every webhook subscription
+func (c *WebhookController) List(ctx echo.Context) error {
+	webhooks, err := c.WebhookUsecase.ListWebhooks()
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, webhooks)
+}
+
+// Create handles subscribing an endpoint, the response holds the signing secret
+func (c *WebhookController) Create(ctx echo.Context) error {
+	var request dto.CreateWebhookRequest
+	if err := ctx.Bind(&request); err != nil {
+		return errInvalidRequest
+	}
+
+	webhook, err := c.WebhookUsecase.CreateWebhook(request)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusCreated, webhook)
+}
+
+// Get handles retrieving one webhook subscription
+func (c *WebhookController) Get(ctx echo.Context) error {
+	id, err := webhookID(ctx)
+	if err != nil {
+		return err
+	}
+
+	webhook, err := c.WebhookUsecase.GetWebhook(id)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, webhook)
+}
+
+// Update handles changing the URL, events or active state of a subscription
+func (c *WebhookController) Update(ctx echo.Context) error {
+	id, err := webhookID(ctx)
+	if err != nil {
+		return err
+	}
+	var request dto.UpdateWebhookRequest
+	if err := ctx.Bind(&request); err != nil {
+		return errInvalidRequest
+	}
+	request.ID = id
+
+	webhook, err := c.WebhookUsecase.UpdateWebhook(request)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, webhook)
+}
+
+// Delete handles removing a subscription and its delivery log
+func (c *WebhookController) Delete(ctx echo.Context) error {
+	id, err := webhookID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.WebhookUsecase.DeleteWebhook(id); err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, dto.MessageResponse{Message: "Webhook deleted successfully"})
+}
+
+// Deliveries handles retrieving the delivery log of a subscription
+func (c *WebhookController) Deliveries(ctx echo.Context) error {
+	id, err := webhookID(ctx)
+	if err != nil {
+		return err
+	}
+	limit, _ := strconv.Atoi(ctx.QueryParam("limit"))
+	offset, _ := strconv.Atoi(ctx.QueryParam("offset"))
+
+	deliveries, err := c.WebhookUsecase.ListDeliveries(id, limit, offset)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, deliveries)
+}
+
+// Redeliver handles queueing a delivery again
+func (c *WebhookController) Redeliver(ctx echo.Context) error {
+	id, err := webhookID(ctx)
+	if err != nil {
+		return err
+	}
+	deliveryID, err := strconv.ParseUint(ctx.Param("delivery_id"), 10, 32)
+	if err != nil {
+		return errInvalidDelivery
+	}
+
+	delivery, err := c.WebhookUsecase.Redeliver(id, uint(deliveryID))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusAccepted, delivery)
+}
+
+func webhookID(ctx echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(ctx.Param("webhook_id"), 10, 32)
+	if err != nil {
+		return 0, errInvalidWebhookID
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,21 @@
+package controller
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/article/api/middleware"
+	"github.com/yuhari7/backend_supervision/shared/auth"
+)
+
+// RegisterWebhookRoutes sets up the routes managing webhook subscriptions, open to callers with the webhooks:manage permission
+func RegisterWebhookRoutes(e *echo.Group, controller *WebhookController, authenticator auth.Authenticator) {
+	webhooks := e.Group("/webhooks", middleware.AuthMiddleware(authenticator, "webhooks:manage"))
+
+	webhooks.GET("", controller.List)
+	webhooks.POST("", controller.Create)
+	webhooks.GET("/:webhook_id", controller.Get)
+	webhooks.PUT("/:webhook_id", controller.Update)
+	webhooks.DELETE("/:webhook_id", controller.Delete)
+
+	webhooks.GET("/:webhook_id/deliveries", controller.Deliveries)
+	webhooks.POST("/:webhook_id/deliveries/:delivery_id/redeliver", controller.Redeliver)
+}
@@ -0,0 +1,144 @@
+// Package graphqlapi serves the read-only GraphQL API of the article service.
+package graphqlapi
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/usecase"
+	"github.com/yuhari7/backend_supervision/shared/apperror"
+	"github.com/yuhari7/backend_supervision/shared/i18n"
+	"github.com/yuhari7/backend_supervision/shared/problem"
+)
+
+// Path is where the GraphQL endpoint is served
+const Path = "/graphql"
+
+var (
+	errInvalidRequest   = apperror.Validation("invalid_request", "invalid request", nil)
+	errInvalidArticleID = apperror.Validation("invalid_article_id", "invalid article ID", nil)
+)
+
+// Handler executes GraphQL queries. The schema only reads articles through the same usecases
+// as the public REST GET routes, so it is public like they are.
+type Handler struct {
+	schema       graphql.Schema
+	limits       Limits
+	translator   *i18n.Translator
+	articles     usecase.ArticleUsecase
+	translations usecase.TranslationUsecase
+	related      usecase.RelatedArticleUsecase
+}
+
+// NewHandler creates a new GraphQL handler
+func NewHandler(articles usecase.ArticleUsecase, translations usecase.TranslationUsecase, related usecase.RelatedArticleUsecase, views usecase.ViewRecorder, translator *i18n.Translator, limits Limits) (*Handler, error) {
+	schema, err := newSchema(&resolvers{articles: articles, translations: translations, views: views})
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{
+		schema:       schema,
+		limits:       limits,
+		translator:   translator,
+		articles:     articles,
+		translations: translations,
+		related:      related,
+	}, nil
+}
+
+// RegisterRoutes serves queries sent as GET query parameters or as a POST JSON body
+func RegisterRoutes(e *echo.Echo, h *Handler) {
+	e.GET(Path, h.Serve)
+	e.POST(Path, h.Serve)
+}
+
+// Serve parses, validates, measures and executes a query. Malformed requests fail with a problem response,
+// errors found in the query itself are reported in the errors member of a 200 response as GraphQL clients expect.
+func (h *Handler) Serve(c echo.Context) error {
+	var request dto.GraphQLRequest
+	if c.Request().Method == http.MethodGet {
+		request.Query = c.QueryParam("query")
+		request.OperationName = c.QueryParam("operationName")
+		if variables := c.QueryParam("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				return errInvalidRequest.Wrap(err)
+			}
+		}
+	} else if err := c.Bind(&request); err != nil {
+		return errInvalidRequest
+	}
+
+	locale := h.translator.FromRequest(c.Request())
+	document, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(request.Query), Name: "GraphQL request"})})
+	if err != nil {
+		return c.JSON(http.StatusOK, h.response(locale, nil, gqlerrors.FormatErrors(err)))
+	}
+	if validation := graphql.ValidateDocument(&h.schema, document, nil); !validation.IsValid {
+		return c.JSON(http.StatusOK, h.response(locale, nil, validation.Errors))
+	}
+	if err := checkLimits(h.schema, document, request.OperationName, request.Variables, h.limits); err != nil {
+		return c.JSON(http.StatusOK, h.response(locale, nil, []gqlerrors.FormattedError{h.formatError(locale, err, gqlerrors.FormatError(err))}))
+	}
+
+	state := &requestState{
+		loaders:   newLoaders(h.articles, h.translations, h.related),
+		clientKey: c.RealIP() + "|" + c.Request().UserAgent(),
+	}
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           document,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       withState(c.Request().Context(), state),
+	})
+
+	data, _ := result.Data.(map[string]interface{})
+	return c.JSON(http.StatusOK, h.response(locale, data, result.Errors))
+}
+
+// response converts the execution result, errors raised by resolvers are translated like problem responses
+func (h *Handler) response(locale string, data map[string]interface{}, errs []gqlerrors.FormattedError) dto.GraphQLResponse {
+	response := dto.GraphQLResponse{Data: data}
+	for _, formatted := range errs {
+		var cause error
+		if located, ok := formatted.OriginalError().(*gqlerrors.Error); ok {
+			cause = located.OriginalError
+		}
+		if cause != nil {
+			formatted = h.formatError(locale, cause, formatted)
+		}
+
+		gqlErr := dto.GraphQLError{Message: formatted.Message, Path: formatted.Path, Extensions: formatted.Extensions}
+		for _, location := range formatted.Locations {
+			gqlErr.Locations = append(gqlErr.Locations, dto.GraphQLLocation{Line: location.Line, Column: location.Column})
+		}
+		response.Errors = append(response.Errors, gqlErr)
+	}
+	return response
+}
+
+// formatError replaces the message of an error with its translated detail and adds its code,
+// internal errors are logged and never shown to clients
+func (h *Handler) formatError(locale string, err error, formatted gqlerrors.FormattedError) gqlerrors.FormattedError {
+	p := problem.FromError(err, h.translator, locale)
+	if p.Status >= http.StatusInternalServerError {
+		log.Printf("GraphQL %v: %v", formatted.Path, err)
+	}
+
+	formatted.Message = p.Detail
+	formatted.Extensions = map[string]interface{}{"code": p.Code}
+	for key, value := range p.Extensions {
+		formatted.Extensions[key] = value
+	}
+	if len(p.Errors) > 0 {
+		formatted.Extensions["errors"] = p.Errors
+	}
+	return formatted
+}
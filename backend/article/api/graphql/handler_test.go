@@ -0,0 +1,171 @@
+package graphqlapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/usecase"
+	"github.com/yuhari7/backend_supervision/shared/i18n"
+)
+
+// fakeUsecases serves a fixed set of articles and counts the calls made per method.
+// Methods the schema does not use are left to the embedded nil interfaces.
+type fakeUsecases struct {
+	usecase.ArticleUsecase
+	usecase.TranslationUsecase
+	usecase.RelatedArticleUsecase
+	articles []dto.ArticleResponse
+	calls    map[string]int
+}
+
+func newFakeUsecases() *fakeUsecases {
+	f := &fakeUsecases{calls: map[string]int{}}
+	for id := uint(1); id <= 4; id++ {
+		f.articles = append(f.articles, dto.ArticleResponse{ID: id, Title: "Article", Category: "news", Locale: "id", TranslationGroupID: id})
+	}
+	// Article 5 is the English translation of article 1
+	f.articles = append(f.articles, dto.ArticleResponse{ID: 5, Title: "Article", Category: "news", Locale: "en", TranslationGroupID: 1})
+	return f
+}
+
+func (f *fakeUsecases) FindAllArticles(limit, offset int, locale string) ([]dto.ArticleResponse, error) {
+	f.calls["FindAllArticles"]++
+	return f.articles[:min(limit, 4)], nil
+}
+
+func (f *fakeUsecases) FindByIDs(ids []uint) (map[uint]dto.ArticleResponse, error) {
+	f.calls["FindByIDs"]++
+	found := map[uint]dto.ArticleResponse{}
+	for _, article := range f.articles {
+		for _, id := range ids {
+			if article.ID == id {
+				found[id] = article
+			}
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeUsecases) FindGroups(groupIDs []uint) (map[uint][]dto.ArticleResponse, error) {
+	f.calls["FindGroups"]++
+	groups := map[uint][]dto.ArticleResponse{}
+	for _, article := range f.articles {
+		groups[article.TranslationGroupID] = append(groups[article.TranslationGroupID], article)
+	}
+	return groups, nil
+}
+
+func (f *fakeUsecases) GetRelatedBatch(ids []uint, limit int) (map[uint][]dto.RelatedArticleResponse, error) {
+	f.calls["GetRelatedBatch"]++
+	related := map[uint][]dto.RelatedArticleResponse{}
+	for _, id := range ids {
+		related[id] = []dto.RelatedArticleResponse{{ID: id%4 + 1, Title: "Article", Category: "news", Score: 0.5}}
+	}
+	return related, nil
+}
+
+func (f *fakeUsecases) FindLocalized(id uint, locale string) (dto.ArticleResponse, error) {
+	return dto.ArticleResponse{}, usecase.ErrArticleNotFound
+}
+
+type noViews struct{ usecase.ViewRecorder }
+
+func (noViews) Record(articleID uint, clientKey string) {}
+
+func query(t *testing.T, f *fakeUsecases, limits Limits, body string) dto.GraphQLResponse {
+	t.Helper()
+	handler, err := NewHandler(f, f, f, noViews{}, i18n.MustNew("en"), limits)
+	if err != nil {
+		t.Fatalf("building schema: %v", err)
+	}
+
+	e := echo.New()
+	RegisterRoutes(e, handler)
+	req := httptest.NewRequest(http.MethodPost, Path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", rec.Code, rec.Body.String())
+	}
+	var response dto.GraphQLResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	return response
+}
+
+func TestNestedFieldsAreBatched(t *testing.T) {
+	f := newFakeUsecases()
+	response := query(t, f, DefaultLimits, `{"query":"{ articles(limit: 4) { id translations { locale } related { article { id title } } } }"}`)
+	if len(response.Errors) > 0 {
+		t.Fatalf("unexpected errors: %+v", response.Errors)
+	}
+
+	articles := response.Data["articles"].([]interface{})
+	if len(articles) != 4 {
+		t.Fatalf("got %d articles, want 4", len(articles))
+	}
+	translations := articles[0].(map[string]interface{})["translations"].([]interface{})
+	if len(translations) != 1 || translations[0].(map[string]interface{})["locale"] != "en" {
+		t.Errorf("got translations %v of article 1, want the English one", translations)
+	}
+
+	want := map[string]int{"FindAllArticles": 1, "FindGroups": 1, "GetRelatedBatch": 1, "FindByIDs": 1}
+	if !reflect.DeepEqual(f.calls, want) {
+		t.Errorf("got calls %v, want %v", f.calls, want)
+	}
+}
+
+func TestMissingArticleIsNull(t *testing.T) {
+	for _, body := range []string{
+		`{"query":"{ article(id: 99) { id } }"}`,
+		`{"query":"query($id: ID!) { article(id: $id, locale: \"en\") { id } }","variables":{"id":"99"}}`,
+	} {
+		response := query(t, newFakeUsecases(), DefaultLimits, body)
+		if len(response.Errors) > 0 || response.Data["article"] != nil {
+			t.Errorf("%s: got data %v and errors %+v, want a null article", body, response.Data, response.Errors)
+		}
+	}
+}
+
+func TestQueryLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   Limits
+		body     string
+		wantCode string
+	}{
+		{"within limits", Limits{MaxDepth: 3, MaxComplexity: 101}, `{"query":"{ articles(limit: 50) { id title } }"}`, ""},
+		{"too complex", Limits{MaxDepth: 3, MaxComplexity: 100}, `{"query":"{ articles(limit: 50) { id title } }"}`, "query_too_complex"},
+		{"limit from variables", Limits{MaxDepth: 3, MaxComplexity: 100}, `{"query":"query($n: Int) { articles(limit: $n) { id title } }","variables":{"n":50}}`, "query_too_complex"},
+		{"too deep", Limits{MaxDepth: 3, MaxComplexity: 1000}, `{"query":"{ articles { related { article { id } } } }"}`, "query_too_deep"},
+		{"fragments count", Limits{MaxDepth: 3, MaxComplexity: 1000}, `{"query":"{ articles { ...deep } } fragment deep on Article { related { article { id } } }"}`, "query_too_deep"},
+		{"introspection is free", Limits{MaxDepth: 2, MaxComplexity: 10}, `{"query":"{ __schema { types { name fields { name type { name ofType { name } } } } } }"}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := query(t, newFakeUsecases(), tt.limits, tt.body)
+			if tt.wantCode == "" {
+				if len(response.Errors) > 0 {
+					t.Fatalf("unexpected errors: %+v", response.Errors)
+				}
+				return
+			}
+			if len(response.Errors) != 1 || response.Errors[0].Extensions["code"] != tt.wantCode {
+				t.Fatalf("got errors %+v, want %s", response.Errors, tt.wantCode)
+			}
+			if response.Data != nil {
+				t.Errorf("got data %v for a rejected query", response.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package graphqlapi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/yuhari7/backend_supervision/shared/apperror"
+)
+
+// Limits bound the size of a query before it is executed
+type Limits struct {
+	MaxDepth      int // deepest level of nested fields
+	MaxComplexity int // estimated number of resolved fields
+}
+
+// DefaultLimits allow the pages of the frontend with room to spare
+var DefaultLimits = Limits{MaxDepth: 8, MaxComplexity: 1000}
+
+var (
+	errQueryTooDeep    = apperror.Validation("query_too_deep", "query is nested too deeply", nil)
+	errQueryTooComplex = apperror.Validation("query_too_complex", "query is too complex", nil)
+)
+
+// measurer estimates the cost of an operation from its selections.
+// Every field costs one, the selections below a list field count once per item the list can hold,
+// which is the limit argument of the field or defaultPageSize for lists without one.
+// Introspection fields are free so tooling keeps working.
+type measurer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// checkLimits rejects an operation that goes over the limits, the document must already be validated
+func checkLimits(schema graphql.Schema, document *ast.Document, operationName string, variables map[string]interface{}, limits Limits) error {
+	m := &measurer{fragments: map[string]*ast.FragmentDefinition{}, variables: variables}
+	var operation *ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			m.fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	if operation == nil || operation.Operation != ast.OperationTypeQuery {
+		return nil
+	}
+
+	complexity, depth := m.selectionSet(operation.SelectionSet, schema.QueryType(), 1)
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return errQueryTooDeep.With("max_depth", limits.MaxDepth).With("depth", depth)
+	}
+	if limits.MaxComplexity > 0 && complexity > limits.MaxComplexity {
+		return errQueryTooComplex.With("max_complexity", limits.MaxComplexity).With("complexity", complexity)
+	}
+	return nil
+}
+
+// selectionSet returns the cost and the depth reached by the selections made on parent
+func (m *measurer) selectionSet(set *ast.SelectionSet, parent *graphql.Object, depth int) (int, int) {
+	if set == nil || parent == nil {
+		return 0, depth - 1
+	}
+
+	complexity, maxDepth := 0, depth-1
+	add := func(cost, reached int) {
+		complexity += cost
+		if reached > maxDepth {
+			maxDepth = reached
+		}
+	}
+
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			field, ok := parent.Fields()[selection.Name.Value]
+			if !ok {
+				continue
+			}
+			cost, reached := m.selectionSet(selection.SelectionSet, objectType(field.Type), depth+1)
+			add(1+cost*m.listSize(selection, field), max(reached, depth))
+		case *ast.InlineFragment:
+			add(m.selectionSet(selection.SelectionSet, parent, depth))
+		case *ast.FragmentSpread:
+			if fragment, ok := m.fragments[selection.Name.Value]; ok {
+				add(m.selectionSet(fragment.SelectionSet, parent, depth))
+			}
+		}
+	}
+	return complexity, maxDepth
+}
+
+// listSize is the number of items a field can return, one for fields that are not lists
+func (m *measurer) listSize(selection *ast.Field, field *graphql.FieldDefinition) int {
+	if _, ok := unwrap(field.Type).(*graphql.List); !ok {
+		return 1
+	}
+
+	size := defaultPageSize
+	for _, arg := range field.Args {
+		if arg.Name() == "limit" {
+			if value, ok := arg.DefaultValue.(int); ok {
+				size = value
+			}
+		}
+	}
+	for _, arg := range selection.Arguments {
+		if arg.Name.Value != "limit" {
+			continue
+		}
+		switch value := arg.Value.(type) {
+		case *ast.IntValue:
+			size, _ = strconv.Atoi(value.Value)
+		case *ast.Variable:
+			switch v := m.variables[value.Name.Value].(type) {
+			case float64:
+				size = int(v)
+			case int:
+				size = v
+			}
+		}
+	}
+
+	if size <= 0 {
+		return defaultPageSize
+	}
+	return min(size, maxPageSize)
+}
+
+func unwrap(t graphql.Type) graphql.Type {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		return nonNull.OfType
+	}
+	return t
+}
+
+// objectType returns the object type of a field, looking through lists and non-null wrappers
+func objectType(t graphql.Type) *graphql.Object {
+	for {
+		switch wrapped := t.(type) {
+		case *graphql.NonNull:
+			t = wrapped.OfType
+		case *graphql.List:
+			t = wrapped.OfType
+		case *graphql.Object:
+			return wrapped
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package graphqlapi
+
+import (
+	"context"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/usecase"
+	"github.com/yuhari7/backend_supervision/article/pkg/dataloader"
+)
+
+type relatedKey struct {
+	id    uint
+	limit int
+}
+
+type categoryKey struct {
+	name  string
+	limit int
+}
+
+// loaders batch the lookups of nested fields, so a list of articles costs one query per field instead of one per article
+type loaders struct {
+	articles   *dataloader.Loader[uint, *dto.ArticleResponse]
+	groups     *dataloader.Loader[uint, []dto.ArticleResponse]
+	related    *dataloader.Loader[relatedKey, []dto.RelatedArticleResponse]
+	categories *dataloader.Loader[categoryKey, []dto.ArticleResponse]
+}
+
+func newLoaders(articles usecase.ArticleUsecase, translations usecase.TranslationUsecase, related usecase.RelatedArticleUsecase) *loaders {
+	return &loaders{
+		articles: dataloader.New(func(ids []uint) (map[uint]*dto.ArticleResponse, error) {
+			found, err := articles.FindByIDs(ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[uint]*dto.ArticleResponse, len(found))
+			for id := range found {
+				article := found[id]
+				byID[id] = &article
+			}
+			return byID, nil
+		}),
+		groups: dataloader.New(translations.FindGroups),
+		related: dataloader.New(func(keys []relatedKey) (map[relatedKey][]dto.RelatedArticleResponse, error) {
+			result := make(map[relatedKey][]dto.RelatedArticleResponse, len(keys))
+			for limit, ids := range groupByLimit(keys, func(k relatedKey) (uint, int) { return k.id, k.limit }) {
+				byID, err := related.GetRelatedBatch(ids, limit)
+				if err != nil {
+					return nil, err
+				}
+				for id, items := range byID {
+					result[relatedKey{id: id, limit: limit}] = items
+				}
+			}
+			return result, nil
+		}),
+		categories: dataloader.New(func(keys []categoryKey) (map[categoryKey][]dto.ArticleResponse, error) {
+			result := make(map[categoryKey][]dto.ArticleResponse, len(keys))
+			for limit, names := range groupByLimit(keys, func(k categoryKey) (string, int) { return k.name, k.limit }) {
+				byName, err := articles.FindByCategories(names, limit)
+				if err != nil {
+					return nil, err
+				}
+				for name, items := range byName {
+					result[categoryKey{name: name, limit: limit}] = items
+				}
+			}
+			return result, nil
+		}),
+	}
+}
+
+// groupByLimit splits keys by their limit argument, one batch query is made per distinct limit
+func groupByLimit[K any, V comparable](keys []K, split func(K) (V, int)) map[int][]V {
+	groups := make(map[int][]V)
+	for _, key := range keys {
+		value, limit := split(key)
+		groups[limit] = append(groups[limit], value)
+	}
+	return groups
+}
+
+// requestState is the per-request data resolvers read from the context
+type requestState struct {
+	loaders   *loaders
+	clientKey string
+}
+
+type stateKey struct{}
+
+func withState(ctx context.Context, state *requestState) context.Context {
+	return context.WithValue(ctx, stateKey{}, state)
+}
+
+func stateFrom(ctx context.Context) *requestState {
+	return ctx.Value(stateKey{}).(*requestState)
+}
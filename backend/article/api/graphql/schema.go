@@ -0,0 +1,307 @@
+package graphqlapi
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/usecase"
+)
+
+// Page sizes of list fields, larger limits are capped at maxPageSize
+const (
+	defaultPageSize = 10
+	maxPageSize     = 50
+)
+
+// resolvers resolves the schema fields through the same usecases as the REST controllers
+type resolvers struct {
+	articles     usecase.ArticleUsecase
+	translations usecase.TranslationUsecase
+	views        usecase.ViewRecorder
+}
+
+// newSchema builds the read-only schema exposing articles, categories and search
+func newSchema(r *resolvers) (graphql.Schema, error) {
+	relatedArticleType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "RelatedArticle",
+		Description: "A published article similar to another article",
+		Fields: graphql.Fields{
+			"id":       &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: related(func(a dto.RelatedArticleResponse) interface{} { return a.ID })},
+			"title":    &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: related(func(a dto.RelatedArticleResponse) interface{} { return a.Title })},
+			"category": &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: related(func(a dto.RelatedArticleResponse) interface{} { return a.Category })},
+			"tags":     &graphql.Field{Type: stringList, Resolve: related(func(a dto.RelatedArticleResponse) interface{} { return nonNilTags(a.Tags) })},
+			"score":    &graphql.Field{Type: graphql.NewNonNull(graphql.Float), Resolve: related(func(a dto.RelatedArticleResponse) interface{} { return a.Score })},
+		},
+	})
+
+	authorType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Author",
+		Description: "The author of an article, name and avatar are null when the user service cannot be reached",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: author(func(a dto.AuthorResponse) interface{} { return a.ID })},
+			"name":      &graphql.Field{Type: graphql.String, Resolve: author(func(a dto.AuthorResponse) interface{} { return optional(a.Name) })},
+			"avatarUrl": &graphql.Field{Type: graphql.String, Resolve: author(func(a dto.AuthorResponse) interface{} { return optional(a.AvatarURL) })},
+		},
+	})
+
+	articleType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Article",
+		Fields: graphql.Fields{
+			"id":                 &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: article(func(a dto.ArticleResponse) interface{} { return a.ID })},
+			"title":              &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: article(func(a dto.ArticleResponse) interface{} { return a.Title })},
+			"content":            &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: article(func(a dto.ArticleResponse) interface{} { return a.Content })},
+			"category":           &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: article(func(a dto.ArticleResponse) interface{} { return a.Category })},
+			"status":             &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: article(func(a dto.ArticleResponse) interface{} { return a.Status })},
+			"tags":               &graphql.Field{Type: stringList, Resolve: article(func(a dto.ArticleResponse) interface{} { return nonNilTags(a.Tags) })},
+			"locale":             &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: article(func(a dto.ArticleResponse) interface{} { return a.Locale })},
+			"translationGroupId": &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: article(func(a dto.ArticleResponse) interface{} { return a.TranslationGroupID })},
+			"revision":           &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: article(func(a dto.ArticleResponse) interface{} { return a.Revision })},
+			"createdAt":          &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: article(func(a dto.ArticleResponse) interface{} { return a.CreatedAt })},
+			"updatedAt":          &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: article(func(a dto.ArticleResponse) interface{} { return a.UpdatedAt })},
+			"author":             &graphql.Field{Type: authorType, Resolve: article(func(a dto.ArticleResponse) interface{} { return a.Author })},
+		},
+	})
+
+	// Fields referring back to Article are added once the type exists
+	articleList := graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(articleType)))
+	articleType.AddFieldConfig("translations", &graphql.Field{
+		Type:        articleList,
+		Description: "The other articles of the translation group",
+		Resolve:     r.translationsOf,
+	})
+	articleType.AddFieldConfig("related", &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(relatedArticleType))),
+		Description: "Published articles similar to the article",
+		Args:        graphql.FieldConfigArgument{"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 5}},
+		Resolve:     r.relatedOf,
+	})
+	relatedArticleType.AddFieldConfig("article", &graphql.Field{
+		Type:        articleType,
+		Description: "The full related article",
+		Resolve:     r.relatedArticle,
+	})
+
+	categoryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Category",
+		Fields: graphql.Fields{
+			"name":         &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: category(func(c dto.CategoryResponse) interface{} { return c.Name })},
+			"articleCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: category(func(c dto.CategoryResponse) interface{} { return c.Articles })},
+			"articles": &graphql.Field{
+				Type:        articleList,
+				Description: "The newest articles of the category",
+				Args:        graphql.FieldConfigArgument{"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultPageSize}},
+				Resolve:     r.categoryArticles,
+			},
+		},
+	})
+
+	page := graphql.FieldConfigArgument{
+		"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultPageSize},
+		"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"article": &graphql.Field{
+				Type:        articleType,
+				Description: "An article, optionally in another locale",
+				Args: graphql.FieldConfigArgument{
+					"id":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"locale": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: r.article,
+			},
+			"articles": &graphql.Field{
+				Type: articleList,
+				Args: graphql.FieldConfigArgument{
+					"limit":  page["limit"],
+					"offset": page["offset"],
+					"locale": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: r.articleList,
+			},
+			"search": &graphql.Field{
+				Type:        articleList,
+				Description: "Articles matching the text in their title, content, status or category",
+				Args: graphql.FieldConfigArgument{
+					"q":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"limit":  page["limit"],
+					"offset": page["offset"],
+				},
+				Resolve: r.search,
+			},
+			"categories": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType))),
+				Resolve: r.categories,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+var stringList = graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))
+
+func (r *resolvers) article(p graphql.ResolveParams) (interface{}, error) {
+	id, err := strconv.ParseUint(p.Args["id"].(string), 10, 32)
+	if err != nil {
+		return nil, errInvalidArticleID
+	}
+	state := stateFrom(p.Context)
+
+	// Localized lookups follow translation fallbacks, plain lookups are batched with other articles of the query
+	if locale, _ := p.Args["locale"].(string); locale != "" {
+		found, err := r.translations.FindLocalized(uint(id), locale)
+		if errors.Is(err, usecase.ErrArticleNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.views.Record(found.ID, state.clientKey)
+		return found, nil
+	}
+
+	load := state.loaders.articles.Load(uint(id))
+	return func() (interface{}, error) {
+		found, err := load()
+		if err != nil || found == nil {
+			return nil, err
+		}
+		r.views.Record(found.ID, state.clientKey)
+		return found, nil
+	}, nil
+}
+
+func (r *resolvers) articleList(p graphql.ResolveParams) (interface{}, error) {
+	limit, offset := pageArgs(p.Args)
+	locale, _ := p.Args["locale"].(string)
+	return r.articles.FindAllArticles(limit, offset, locale)
+}
+
+func (r *resolvers) search(p graphql.ResolveParams) (interface{}, error) {
+	limit, offset := pageArgs(p.Args)
+	return r.articles.SearchArticles(p.Args["q"].(string), limit, offset)
+}
+
+func (r *resolvers) categories(p graphql.ResolveParams) (interface{}, error) {
+	return r.articles.FindCategories()
+}
+
+func (r *resolvers) categoryArticles(p graphql.ResolveParams) (interface{}, error) {
+	limit, _ := pageArgs(p.Args)
+	name := p.Source.(dto.CategoryResponse).Name
+	return thunk(stateFrom(p.Context).loaders.categories.Load(categoryKey{name: name, limit: limit})), nil
+}
+
+func (r *resolvers) translationsOf(p graphql.ResolveParams) (interface{}, error) {
+	source := articleSource(p.Source)
+	load := stateFrom(p.Context).loaders.groups.Load(source.TranslationGroupID)
+	return func() (interface{}, error) {
+		group, err := load()
+		if err != nil {
+			return nil, err
+		}
+		others := make([]dto.ArticleResponse, 0, len(group))
+		for _, member := range group {
+			if member.ID != source.ID {
+				others = append(others, member)
+			}
+		}
+		return others, nil
+	}, nil
+}
+
+func (r *resolvers) relatedOf(p graphql.ResolveParams) (interface{}, error) {
+	limit, _ := p.Args["limit"].(int)
+	key := relatedKey{id: articleSource(p.Source).ID, limit: limit}
+	return thunk(stateFrom(p.Context).loaders.related.Load(key)), nil
+}
+
+func (r *resolvers) relatedArticle(p graphql.ResolveParams) (interface{}, error) {
+	load := stateFrom(p.Context).loaders.articles.Load(p.Source.(dto.RelatedArticleResponse).ID)
+	return func() (interface{}, error) {
+		found, err := load()
+		if err != nil || found == nil {
+			return nil, err
+		}
+		return found, nil
+	}, nil
+}
+
+// pageArgs reads the limit and offset arguments, falling back to the defaults for invalid values
+func pageArgs(args map[string]interface{}) (int, int) {
+	limit, _ := args["limit"].(int)
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	offset, _ := args["offset"].(int)
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// thunk defers a loader result so sibling fields can queue their keys before the batch runs
+func thunk[V any](load func() (V, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		value, err := load()
+		if err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
+
+// articleSource returns the article a field is resolved on, loaders hand out pointers and lists values
+func articleSource(source interface{}) dto.ArticleResponse {
+	if a, ok := source.(*dto.ArticleResponse); ok {
+		return *a
+	}
+	return source.(dto.ArticleResponse)
+}
+
+func article(field func(dto.ArticleResponse) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return field(articleSource(p.Source)), nil
+	}
+}
+
+func related(field func(dto.RelatedArticleResponse) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return field(p.Source.(dto.RelatedArticleResponse)), nil
+	}
+}
+
+func category(field func(dto.CategoryResponse) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return field(p.Source.(dto.CategoryResponse)), nil
+	}
+}
+
+func author(field func(dto.AuthorResponse) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return field(*p.Source.(*dto.AuthorResponse)), nil
+	}
+}
+
+// optional turns an empty string into null
+func optional(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
@@ -1,36 +1,193 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/yuhari7/backend_supervision/article/api/controller"
+	graphqlapi "github.com/yuhari7/backend_supervision/article/api/graphql"
 	"github.com/yuhari7/backend_supervision/article/config"
+	"github.com/yuhari7/backend_supervision/article/internal/policy"
 	"github.com/yuhari7/backend_supervision/article/internal/repository"
 	"github.com/yuhari7/backend_supervision/article/internal/usecase"
+	"github.com/yuhari7/backend_supervision/article/internal/userclient"
+	"github.com/yuhari7/backend_supervision/shared/auth"
+	"github.com/yuhari7/backend_supervision/shared/i18n"
+	"github.com/yuhari7/backend_supervision/shared/jwks"
+	"github.com/yuhari7/backend_supervision/shared/openapi"
+	"github.com/yuhari7/backend_supervision/shared/outbox"
+	"github.com/yuhari7/backend_supervision/shared/problem"
 )
 
 func NewServer() *echo.Echo {
 
 	config.InitDB()
 
+	// Validation and error messages follow the Accept-Language header, Indonesian by default
+	translator := i18n.MustNew("id")
+
 	e := echo.New()
+	e.HTTPErrorHandler = problem.NewHTTPErrorHandler(translator)
+
+	// CORS comes first, so the frontend can also read the errors of the middlewares after it
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"http://localhost:3000"},
 		AllowMethods: []string{echo.GET, echo.POST, echo.PUT, echo.DELETE},
 		AllowHeaders: []string{echo.HeaderContentType, echo.HeaderAuthorization},
 	}))
 
+	// Requests are validated against the OpenAPI document, responses too when OPENAPI_VALIDATE_RESPONSES is set
+	spec := controller.Spec()
+	validateResponses, _ := strconv.ParseBool(os.Getenv("OPENAPI_VALIDATE_RESPONSES"))
+	e.Use(spec.Validator(openapi.ValidatorConfig{Translator: translator, ValidateResponses: validateResponses}))
+
+	// Access tokens of the user service are checked against the public keys it publishes at JWKS_URL,
+	jwksURL := os.Getenv("JWKS_URL")
+	if jwksURL == "" {
+		jwksURL = "http://localhost:8080/.well-known/jwks.json"
+	}
+	// and each token is confirmed with the gRPC API at USER_SERVICE_GRPC_ADDR, so revoked tokens stop working
+	userServiceAddr := os.Getenv("USER_SERVICE_GRPC_ADDR")
+	if userServiceAddr == "" {
+		userServiceAddr = "localhost:9090"
+	}
+	users, userConn, err := userclient.Dial(userServiceAddr, os.Getenv("USER_SERVICE_TOKEN"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	authenticator := auth.NewJWKSAuthenticator(auth.JWKSConfig{
+		Verifier: jwks.NewVerifier(jwks.VerifierConfig{URL: jwksURL}),
+		Users:    users,
+	})
+
 	articleRepo := repository.NewArticleRepository()
-	articleUsecase := usecase.NewArticleUsecase(articleRepo)
-	articleController := controller.NewArticleController(articleUsecase)
+	articleViewRepo := repository.NewArticleViewRepository()
+
+	// Views are buffered in memory and flushed every 10 seconds, counted once per client every 30 minutes
+	viewRecorder := usecase.NewViewRecorder(articleViewRepo, 10*time.Second, 30*time.Minute)
+	viewRecorder.Start()
+
+	// Domain events are written to the outbox with every change and relayed to the broker chosen by OUTBOX_BROKER.
+	// Webhooks always receive them in process, next to any external broker.
+	webhookRepo := repository.NewWebhookRepository()
+	webhookDispatcher := usecase.NewWebhookDispatcher(webhookRepo, usecase.WebhookConfig{})
+	memoryBroker := outbox.NewMemoryBroker()
+	memoryBroker.Subscribe(outbox.AllEvents, webhookDispatcher.Enqueue)
+
+	broker, err := outbox.BrokerFromEnv(memoryBroker)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if broker != outbox.Broker(memoryBroker) {
+		broker = outbox.Fanout{memoryBroker, broker}
+	}
+	outboxRelay := outbox.NewRelay(outbox.NewGormStore(config.DB), broker, outbox.RelayConfig{})
+	outboxRelay.Start()
+	webhookDispatcher.Start()
+
+	// Related articles are ranked from an in-memory model kept up to date by article changes
+	relatedUsecase := usecase.NewRelatedArticleUsecase(articleRepo, 5*time.Minute)
+	if err := relatedUsecase.Load(); err != nil {
+		log.Println("Failed to load related articles model:", err)
+	}
+
+	// Near-duplicate content only produces a warning unless DUPLICATE_REJECT is enabled
+	duplicateThreshold, _ := strconv.ParseFloat(os.Getenv("DUPLICATE_THRESHOLD"), 64)
+	duplicateReject, _ := strconv.ParseBool(os.Getenv("DUPLICATE_REJECT"))
+	duplicateDetector := usecase.NewDuplicateDetector(articleRepo, usecase.DuplicateConfig{
+		Threshold: duplicateThreshold,
+		Reject:    duplicateReject,
+	})
+	if err := duplicateDetector.Backfill(); err != nil {
+		log.Println("Failed to backfill content fingerprints:", err)
+	}
+
+	// Content policy rules are read from a YAML rule set
+	policyFile := os.Getenv("CONTENT_POLICY_FILE")
+	if policyFile == "" {
+		policyFile = "config/content_policy.yaml"
+	}
+	// A missing or broken rule set stops the service, it would otherwise publish unchecked content
+	contentPolicy, err := policy.LoadEngine(policyFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Author names and avatars come from the gRPC API of the user service, only author IDs are shown while it is down
+	authors := userclient.New(userclient.Config{Users: users})
+
+	// Articles are written in and can be translated into every supported locale, missing translations fall back to the default
+	locales := usecase.LocaleConfig{Default: "id", Supported: []string{"id", "en"}}
+	if defaultLocale := os.Getenv("DEFAULT_LOCALE"); defaultLocale != "" {
+		locales.Default = defaultLocale
+	}
+	if supportedLocales := os.Getenv("SUPPORTED_LOCALES"); supportedLocales != "" {
+		locales.Supported = strings.Split(supportedLocales, ",")
+	}
+	articleUsecase := usecase.NewArticleUsecase(articleRepo, duplicateDetector, contentPolicy, locales, authors, relatedUsecase)
+	translationUsecase := usecase.NewTranslationUsecase(articleRepo, articleUsecase, authors, locales)
+	articleStatsUsecase := usecase.NewArticleStatsUsecase(articleRepo, articleViewRepo)
+
+	articleController := controller.NewArticleController(articleUsecase, translationUsecase, relatedUsecase, duplicateDetector, viewRecorder)
+	articleStatsController := controller.NewArticleStatsController(articleStatsUsecase)
+	webhookController := controller.NewWebhookController(usecase.NewWebhookUsecase(webhookRepo))
 
 	api := e.Group("/api")
-	controller.RegisterArticleRoutes(api, articleController)
+	controller.RegisterArticleRoutes(api, articleController, authenticator)
+	controller.RegisterStatsRoutes(api, articleStatsController)
+	controller.RegisterWebhookRoutes(api, webhookController, authenticator)
+
+	// GraphQL reads at /graphql, query size is bounded by GRAPHQL_MAX_DEPTH and GRAPHQL_MAX_COMPLEXITY
+	limits := graphqlapi.DefaultLimits
+	if maxDepth, err := strconv.Atoi(os.Getenv("GRAPHQL_MAX_DEPTH")); err == nil {
+		limits.MaxDepth = maxDepth
+	}
+	if maxComplexity, err := strconv.Atoi(os.Getenv("GRAPHQL_MAX_COMPLEXITY")); err == nil {
+		limits.MaxComplexity = maxComplexity
+	}
+	graphqlHandler, err := graphqlapi.NewHandler(articleUsecase, translationUsecase, relatedUsecase, viewRecorder, translator, limits)
+	if err != nil {
+		log.Fatal(err)
+	}
+	graphqlapi.RegisterRoutes(e, graphqlHandler)
+
+	// API documentation at /openapi.json and /docs
+	if err := openapi.Register(e, spec); err != nil {
+		log.Fatal(err)
+	}
+
+	// The server runs until SIGINT or SIGTERM, requests in flight finish before the background workers stop
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		log.Println("✅ Starting server on port 8001...")
+		if err := e.Start(":8001"); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+	<-ctx.Done()
 
-	log.Println("✅ Starting server on port 8001...")
-	e.Logger.Fatal(e.Start(":8001"))
+	log.Println("Shutting down the server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Println("Failed to shut down the server:", err)
+	}
+	// The relay feeds the webhooks, so it stops first
+	outboxRelay.Stop()
+	webhookDispatcher.Stop()
+	viewRecorder.Stop()
+	userConn.Close()
 
 	return e
 }
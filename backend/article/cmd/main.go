@@ -7,9 +7,8 @@ import (
 )
 
 func main() {
-	// Initialize and start the server
-	server := api.NewServer()
+	// Initialize and run the server until it is shut down
+	api.NewServer()
 
-	// Log that the server has started successfully
-	log.Println("✅ Server started successfully at", server.Listener.Addr())
+	log.Println("✅ Server stopped")
 }
@@ -1,11 +1,23 @@
 package dto
 
+import (
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/article/internal/policy"
+)
+
 // CreateArticleRequest represents the data required to create an article
 type CreateArticleRequest struct {
-	Title    string `json:"title" validate:"required,min=20"`
-	Content  string `json:"content" validate:"required,min=200"`
-	Category string `json:"category" validate:"required,min=3"`
-	Status   string `json:"status"`
+	Title    string   `json:"title" validate:"required,min=20"`
+	Content  string   `json:"content" validate:"required,min=200"`
+	Category string   `json:"category" validate:"required,min=3"`
+	Status   string   `json:"status"`
+	Tags     []string `json:"tags" validate:"max=10,dive,min=2,max=30"`
+	Locale   string   `json:"locale" validate:"omitempty,bcp47_language_tag"`
+
+	// Set internally, the author is the authenticated user or the author of the translated article
+	AuthorID           *uint `json:"-"`
+	TranslationGroupID uint  `json:"-"`
+	SourceRevision     *int  `json:"-"`
 }
 
 // CreateArticleResponse represents the response data after creating an article
@@ -21,11 +33,14 @@ type CreateArticleResponse struct {
 
 // UpdateArticleRequest represents the data required to update an article
 type UpdateArticleRequest struct {
-	ID       uint   `json:"id"`
-	Title    string `json:"title" validate:"required,min=20"`
-	Content  string `json:"content" validate:"required,min=200"`
-	Category string `json:"category" validate:"required,min=3"`
-	Status   string `json:"status"`
+	ID       uint     `json:"id"`
+	Title    string   `json:"title" validate:"required,min=20"`
+	Content  string   `json:"content" validate:"required,min=200"`
+	Category string   `json:"category" validate:"required,min=3"`
+	Status   string   `json:"status"`
+	Tags     []string `json:"tags" validate:"max=10,dive,min=2,max=30"`
+	// SourceRevision marks a translation as up to date with this revision of its source
+	SourceRevision *int `json:"source_revision" validate:"omitempty,min=1"`
 }
 
 // UpdateArticleResponse represents the response data after updating an article
@@ -44,12 +59,129 @@ type SoftDeleteArticleDTO struct {
 	ID uint `json:"id"`
 }
 
+// MessageResponse represents a confirmation message
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
 type ArticleResponse struct {
+	ID                 uint     `json:"id"`
+	Title              string   `json:"title"`
+	Content            string   `json:"content"`
+	Category           string   `json:"category"`
+	Status             string   `json:"status"`
+	Tags               []string `json:"tags"`
+	Locale             string   `json:"locale"`
+	TranslationGroupID uint     `json:"translation_group_id"`
+	Revision           int      `json:"revision"`
+	CreatedAt          string   `json:"created_date"`
+	UpdatedAt          string   `json:"updated_date"`
+
+	Author *AuthorResponse `json:"author,omitempty"`
+}
+
+// AuthorResponse represents the author of an article. Name and avatar come from the user service
+// and are left out when it cannot be reached.
+type AuthorResponse struct {
 	ID        uint   `json:"id"`
-	Title     string `json:"title"`
-	Content   string `json:"content"`
-	Category  string `json:"category"`
-	Status    string `json:"status"`
-	CreatedAt string `json:"created_date"`
-	UpdatedAt string `json:"updated_date"`
+	Name      string `json:"name,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+}
+
+// CategoryResponse represents a category and its number of articles
+type CategoryResponse struct {
+	Name     string `json:"name"`
+	Articles int64  `json:"articles"`
+}
+
+// RelatedArticleResponse represents an article recommended as related to another article
+type RelatedArticleResponse struct {
+	ID       uint     `json:"id"`
+	Title    string   `json:"title"`
+	Category string   `json:"category"`
+	Tags     []string `json:"tags"`
+	Score    float64  `json:"score"`
+}
+
+// DuplicateMatch represents an existing article whose content is similar to another article
+type DuplicateMatch struct {
+	ID         uint    `json:"id"`
+	Title      string  `json:"title"`
+	Status     string  `json:"status"`
+	Similarity float64 `json:"similarity"`
+}
+
+// ArticleWriteResponse represents a created or updated article along with possible duplicates
+// and non-blocking content policy violations
+type ArticleWriteResponse struct {
+	entity.Article
+	Duplicates       []DuplicateMatch   `json:"duplicates,omitempty"`
+	PolicyViolations []policy.Violation `json:"policy_violations,omitempty"`
+}
+
+// CreateTranslationRequest represents the data required to translate an article into another locale
+type CreateTranslationRequest struct {
+	Locale   string   `json:"locale" validate:"required,bcp47_language_tag"`
+	Title    string   `json:"title" validate:"required,min=20"`
+	Content  string   `json:"content" validate:"required,min=200"`
+	Category string   `json:"category" validate:"omitempty,min=3"`
+	Status   string   `json:"status"`
+	Tags     []string `json:"tags" validate:"max=10,dive,min=2,max=30"`
+}
+
+// TranslationStatus describes the state of one locale of a translation group
+type TranslationStatus struct {
+	Locale         string `json:"locale"`
+	State          string `json:"state"` // missing, outdated or up_to_date
+	ArticleID      uint   `json:"article_id,omitempty"`
+	SourceRevision int    `json:"source_revision,omitempty"`
+}
+
+// TranslationStatusResponse lists which translations of an article are missing or out of date
+type TranslationStatusResponse struct {
+	TranslationGroupID uint                `json:"translation_group_id"`
+	SourceID           uint                `json:"source_id"`
+	SourceLocale       string              `json:"source_locale"`
+	SourceRevision     int                 `json:"source_revision"`
+	Translations       []TranslationStatus `json:"translations"`
+}
+
+// GraphQLRequest represents a GraphQL query sent to the /graphql endpoint
+type GraphQLRequest struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLResponse represents the result of a GraphQL query, data is left out when the query could not run
+type GraphQLResponse struct {
+	Data   map[string]interface{} `json:"data,omitempty"`
+	Errors []GraphQLError         `json:"errors,omitempty"`
+}
+
+// GraphQLError represents an error reported in a GraphQL response
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Locations  []GraphQLLocation      `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// GraphQLLocation points at the part of the query an error is about
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// ArticleEvent is the payload of the article domain events published through the outbox
+type ArticleEvent struct {
+	ID                 uint     `json:"id"`
+	Title              string   `json:"title"`
+	Category           string   `json:"category"`
+	Status             string   `json:"status"`
+	Tags               []string `json:"tags"`
+	Locale             string   `json:"locale"`
+	TranslationGroupID uint     `json:"translation_group_id"`
+	Revision           int      `json:"revision"`
+	AuthorID           *uint    `json:"author_id,omitempty"`
 }
@@ -0,0 +1,34 @@
+package dto
+
+// DailyViews represents the number of views of an article on a single day
+type DailyViews struct {
+	Date  string `json:"date"`
+	Views int64  `json:"views"`
+}
+
+// ArticleStatsResponse represents the view statistics of a single article
+type ArticleStatsResponse struct {
+	ArticleID  uint         `json:"article_id"`
+	Days       int          `json:"days"`
+	TotalViews int64        `json:"total_views"`
+	Daily      []DailyViews `json:"daily"`
+}
+
+// TopArticle represents an article entry in the top articles ranking
+type TopArticle struct {
+	ArticleID uint   `json:"article_id"`
+	Title     string `json:"title"`
+	Views     int64  `json:"views"`
+}
+
+// CategoryTopArticles groups the top articles of a single category
+type CategoryTopArticles struct {
+	Category string       `json:"category"`
+	Articles []TopArticle `json:"articles"`
+}
+
+// TopArticlesResponse represents the most viewed articles per category over a period
+type TopArticlesResponse struct {
+	Period     string                `json:"period"`
+	Categories []CategoryTopArticles `json:"categories"`
+}
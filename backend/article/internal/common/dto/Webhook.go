@@ -0,0 +1,35 @@
+package dto
+
+import "github.com/yuhari7/backend_supervision/article/internal/entity"
+
+// CreateWebhookRequest represents the data required to subscribe an endpoint to article events.
+// Events lists the event types to send, * subscribes to all of them.
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" validate:"required,url,max=2000"`
+	Events []string `json:"events" validate:"required,min=1,max=10,dive,oneof=* article.created article.updated article.published article.trashed article.deleted"`
+	Secret string   `json:"secret,omitempty" validate:"omitempty,min=16,max=100"` // generated when left out
+}
+
+// UpdateWebhookRequest represents the changes to a webhook subscription, activating it again resets its failures
+type UpdateWebhookRequest struct {
+	ID     uint     `json:"-"`
+	URL    string   `json:"url" validate:"required,url,max=2000"`
+	Events []string `json:"events" validate:"required,min=1,max=10,dive,oneof=* article.created article.updated article.published article.trashed article.deleted"`
+	Active bool     `json:"active"`
+}
+
+// WebhookResponse represents a webhook subscription, the secret is only returned when it is created
+type WebhookResponse struct {
+	entity.WebhookSubscription
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookEvent is the body of a webhook request
+type WebhookEvent struct {
+	ID            string      `json:"id"` // event ID, the same on every redelivery
+	Type          string      `json:"type"`
+	AggregateType string      `json:"aggregate_type"`
+	AggregateID   string      `json:"aggregate_id"`
+	Payload       interface{} `json:"payload"`
+	OccurredAt    string      `json:"occurred_at"`
+}
@@ -1,15 +1,89 @@
 package entity
 
-import "time"
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Article statuses used by the editorial workflow
+const (
+	StatusDraft   = "Draft"
+	StatusPublish = "Publish"
+	StatusTrash   = "Trash"
+)
 
 // Article represents the structure of the articles table in the database
 type Article struct {
-	ID          uint       `gorm:"primaryKey" json:"id"`
-	Title       string     `gorm:"not null" json:"title" validate:"required,min=20"`
-	Content     string     `gorm:"not null" json:"content" validate:"required,min=200"`
-	Category    string     `gorm:"not null" json:"category" validate:"required,min=3"`
-	Status      string     `gorm:"not null;default:'Draft'" json:"status"`
-	CreatedDate time.Time  `gorm:"column:created_date;autoCreateTime" json:"created_date"`
-	UpdatedDate time.Time  `gorm:"column:updated_date;autoUpdateTime" json:"updated_date"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // For soft delete
+	ID                 uint       `gorm:"primaryKey" json:"id"`
+	Title              string     `gorm:"not null" json:"title" validate:"required,min=20"`
+	Content            string     `gorm:"not null" json:"content" validate:"required,min=200"`
+	Category           string     `gorm:"not null" json:"category" validate:"required,min=3"`
+	Status             string     `gorm:"not null;default:'Draft'" json:"status"`
+	Tags               TagList    `gorm:"type:text;not null;default:''" json:"tags"`
+	Fingerprint        *int64     `gorm:"column:content_fingerprint" json:"-"` // SimHash of the content
+	Locale             string     `gorm:"not null;default:'id'" json:"locale"`
+	TranslationGroupID uint       `gorm:"column:translation_group_id;not null" json:"translation_group_id"` // ID of the source article of the translation group
+	Revision           int        `gorm:"not null;default:1" json:"revision"`
+	SourceRevision     *int       `gorm:"column:source_revision" json:"source_revision,omitempty"` // Source revision a translation is based on
+	AuthorID           *uint      `gorm:"column:author_id" json:"author_id,omitempty"`             // ID of the author in the user service
+	CreatedDate        time.Time  `gorm:"column:created_date;autoCreateTime" json:"created_date"`
+	UpdatedDate        time.Time  `gorm:"column:updated_date;autoUpdateTime" json:"updated_date"`
+	DeletedAt          *time.Time `json:"deleted_at,omitempty"` // For soft delete
+}
+
+// IsTranslation reports whether the article is a translation of another article
+func (a Article) IsTranslation() bool {
+	return a.TranslationGroupID != 0 && a.TranslationGroupID != a.ID
+}
+
+// CategoryCount is the number of articles in a category
+type CategoryCount struct {
+	Category string `json:"category"`
+	Articles int64  `json:"articles"`
+}
+
+// TagList is a list of tags stored as comma separated text in the database
+type TagList []string
+
+// NormalizeTags lowercases, trims and removes duplicate or empty tags
+func NormalizeTags(tags []string) TagList {
+	seen := make(map[string]bool, len(tags))
+	normalized := TagList{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(tag, ",", " ")))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// Value implements driver.Valuer
+func (t TagList) Value() (driver.Value, error) {
+	return strings.Join(t, ","), nil
+}
+
+// Scan implements sql.Scanner
+func (t *TagList) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		raw = ""
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into TagList", value)
+	}
+
+	*t = TagList{}
+	if raw != "" {
+		*t = strings.Split(raw, ",")
+	}
+	return nil
 }
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// ArticleViewDaily represents the aggregated view count of an article for a single day
+type ArticleViewDaily struct {
+	ArticleID uint      `gorm:"primaryKey;column:article_id" json:"article_id"`
+	ViewDate  time.Time `gorm:"primaryKey;column:view_date;type:date" json:"view_date"`
+	Views     int64     `gorm:"not null;default:0" json:"views"`
+}
+
+// TableName overrides the default table name used by GORM
+func (ArticleViewDaily) TableName() string {
+	return "article_views_daily"
+}
+
+// ArticleViewTotal represents the total views of an article over a period, used for rankings
+type ArticleViewTotal struct {
+	ArticleID uint   `json:"article_id"`
+	Title     string `json:"title"`
+	Category  string `json:"category"`
+	Views     int64  `json:"views"`
+}
@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookSubscription is an endpoint receiving article events
+type WebhookSubscription struct {
+	ID                  uint       `gorm:"primaryKey" json:"id"`
+	URL                 string     `gorm:"column:url;not null" json:"url"`
+	Secret              string     `gorm:"not null" json:"-"`                // HMAC-SHA256 key of the signature header
+	Events              TagList    `gorm:"type:text;not null" json:"events"` // event types, * for all, stored like tags
+	Active              bool       `gorm:"not null;default:true" json:"active"`
+	ConsecutiveFailures int        `gorm:"not null;default:0" json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"` // set when failures disabled the subscription
+	CreatedDate         time.Time  `gorm:"column:created_date;autoCreateTime" json:"created_date"`
+	UpdatedDate         time.Time  `gorm:"column:updated_date;autoUpdateTime" json:"updated_date"`
+}
+
+// Accepts reports whether the subscription wants events of the given type
+func (s WebhookSubscription) Accepts(eventType string) bool {
+	for _, event := range s.Events {
+		if event == "*" || event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is the delivery of one event to one subscription, with the outcome of its last attempt
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint       `gorm:"not null" json:"subscription_id"`
+	EventID        string     `gorm:"type:uuid;not null" json:"event_id"`
+	EventType      string     `gorm:"not null" json:"event_type"`
+	Payload        string     `gorm:"type:jsonb;not null" json:"-"` // request body, the event as JSON
+	Status         string     `gorm:"not null;default:'pending'" json:"status"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	ResponseCode   *int       `json:"response_code,omitempty"`
+	ResponseBody   string     `gorm:"not null;default:''" json:"response_body,omitempty"` // truncated
+	Error          string     `gorm:"not null;default:''" json:"error,omitempty"`
+	DurationMs     int64      `gorm:"not null;default:0" json:"duration_ms"`
+	NextAttemptAt  time.Time  `gorm:"not null" json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedDate    time.Time  `gorm:"column:created_date;autoCreateTime" json:"created_date"`
+	UpdatedDate    time.Time  `gorm:"column:updated_date;autoUpdateTime" json:"updated_date"`
+}
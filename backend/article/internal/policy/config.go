@@ -0,0 +1,88 @@
+package policy
+
+import "fmt"
+
+// Config is the YAML representation of a rule set
+type Config struct {
+	BannedWords      *BannedWordsConfig      `yaml:"banned_words"`
+	MaxLinks         *MaxLinksConfig         `yaml:"max_links"`
+	RequiredHeadings *RequiredHeadingsConfig `yaml:"required_headings"`
+	ImageAltText     *ImageAltTextConfig     `yaml:"image_alt_text"`
+	ForbiddenDomains *ForbiddenDomainsConfig `yaml:"forbidden_domains"`
+}
+
+// BannedWordsConfig configures the banned words rule
+type BannedWordsConfig struct {
+	Severity string   `yaml:"severity"`
+	Words    []string `yaml:"words"`
+}
+
+// MaxLinksConfig configures the maximum link count rule
+type MaxLinksConfig struct {
+	Severity string `yaml:"severity"`
+	Max      int    `yaml:"max"`
+}
+
+// RequiredHeadingsConfig configures the required headings rule
+type RequiredHeadingsConfig struct {
+	Severity string   `yaml:"severity"`
+	Min      int      `yaml:"min"`
+	Headings []string `yaml:"headings"`
+}
+
+// ImageAltTextConfig configures the image alt text rule
+type ImageAltTextConfig struct {
+	Severity  string `yaml:"severity"`
+	MinLength int    `yaml:"min_length"`
+}
+
+// ForbiddenDomainsConfig configures the forbidden link domains rule
+type ForbiddenDomainsConfig struct {
+	Severity string   `yaml:"severity"`
+	Domains  []string `yaml:"domains"`
+}
+
+// Rules builds the rules enabled in the config
+func (c Config) Rules() ([]Rule, error) {
+	var rules []Rule
+
+	if c.BannedWords != nil {
+		if err := checkSeverity("banned_words", c.BannedWords.Severity); err != nil {
+			return nil, err
+		}
+		rules = append(rules, NewBannedWordsRule(c.BannedWords.Severity, c.BannedWords.Words))
+	}
+	if c.MaxLinks != nil {
+		if err := checkSeverity("max_links", c.MaxLinks.Severity); err != nil {
+			return nil, err
+		}
+		rules = append(rules, NewMaxLinksRule(c.MaxLinks.Severity, c.MaxLinks.Max))
+	}
+	if c.RequiredHeadings != nil {
+		if err := checkSeverity("required_headings", c.RequiredHeadings.Severity); err != nil {
+			return nil, err
+		}
+		rules = append(rules, NewRequiredHeadingsRule(c.RequiredHeadings.Severity, c.RequiredHeadings.Min, c.RequiredHeadings.Headings))
+	}
+	if c.ImageAltText != nil {
+		if err := checkSeverity("image_alt_text", c.ImageAltText.Severity); err != nil {
+			return nil, err
+		}
+		rules = append(rules, NewImageAltTextRule(c.ImageAltText.Severity, c.ImageAltText.MinLength))
+	}
+	if c.ForbiddenDomains != nil {
+		if err := checkSeverity("forbidden_domains", c.ForbiddenDomains.Severity); err != nil {
+			return nil, err
+		}
+		rules = append(rules, NewForbiddenDomainsRule(c.ForbiddenDomains.Severity, c.ForbiddenDomains.Domains))
+	}
+
+	return rules, nil
+}
+
+func checkSeverity(rule, severity string) error {
+	if severity != SeverityBlocking && severity != SeverityWarning {
+		return fmt.Errorf("content policy rule %s: severity must be %q or %q", rule, SeverityBlocking, SeverityWarning)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severities of a rule violation
+const (
+	SeverityBlocking = "blocking"
+	SeverityWarning  = "warning"
+)
+
+// Document is the content checked by the policy rules
+type Document struct {
+	Title    string
+	Content  string
+	Category string
+}
+
+// Violation describes a single rule that a document does not satisfy
+type Violation struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Rule checks a document and returns the violations it finds
+type Rule interface {
+	Name() string
+	Check(doc Document) []Violation
+}
+
+// Result is the outcome of running every rule against a document
+type Result struct {
+	Violations []Violation `json:"violations"`
+}
+
+// Blocking reports whether the result contains at least one blocking violation
+func (r Result) Blocking() bool {
+	for _, violation := range r.Violations {
+		if violation.Severity == SeverityBlocking {
+			return true
+		}
+	}
+	return false
+}
+
+// Engine runs a set of content rules
+type Engine struct {
+	rules []Rule
+}
+
+// NewEngine creates an engine from already constructed rules
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// LoadEngine builds an engine from a YAML rule set file
+func LoadEngine(path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content policy: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse content policy: %w", err)
+	}
+
+	rules, err := config.Rules()
+	if err != nil {
+		return nil, err
+	}
+	return NewEngine(rules...), nil
+}
+
+// Evaluate runs every rule against the document
+func (e *Engine) Evaluate(doc Document) Result {
+	result := Result{Violations: []Violation{}}
+	for _, rule := range e.rules {
+		result.Violations = append(result.Violations, rule.Check(doc)...)
+	}
+	return result
+}
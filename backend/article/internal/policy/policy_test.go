@@ -0,0 +1,73 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePolicy writes a rule set to a temporary file and returns its path
+func writePolicy(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadEngine(t *testing.T) {
+	engine, err := LoadEngine(filepath.Join("..", "..", "config", "content_policy.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error loading the shipped rule set: %v", err)
+	}
+	if len(engine.rules) != 5 {
+		t.Errorf("got %d rules, want the 5 of the shipped rule set", len(engine.rules))
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{"missing file", filepath.Join(t.TempDir(), "missing.yaml"), "failed to read content policy"},
+		{"invalid YAML", writePolicy(t, "banned_words: [\n"), "failed to parse content policy"},
+		{"unknown severity", writePolicy(t, "max_links:\n  severity: fatal\n  max: 3\n"), "max_links"},
+		{"missing severity", writePolicy(t, "banned_words:\n  words: [spam]\n"), "banned_words"},
+	}
+	for _, tt := range tests {
+		if _, err := LoadEngine(tt.path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: got error %v, want one mentioning %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	engine, err := LoadEngine(writePolicy(t, `
+banned_words:
+  severity: blocking
+  words: [slot gacor]
+max_links:
+  severity: warning
+  max: 1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clean := engine.Evaluate(Document{Title: "Tips", Content: "See https://example.com"})
+	if clean.Violations == nil || len(clean.Violations) != 0 || clean.Blocking() {
+		t.Errorf("got %+v, want an empty list of violations", clean)
+	}
+
+	warned := engine.Evaluate(Document{Title: "Tips", Content: "https://a.example https://b.example"})
+	if len(warned.Violations) != 1 || warned.Violations[0].Rule != "max_links" || warned.Blocking() {
+		t.Errorf("got %+v, want a single max_links warning", warned)
+	}
+
+	blocked := engine.Evaluate(Document{Title: "Slot Gacor hari ini", Content: "https://a.example https://b.example"})
+	if len(blocked.Violations) != 2 || !blocked.Blocking() {
+		t.Errorf("got %+v, want a blocking banned word next to the warning", blocked)
+	}
+}
@@ -0,0 +1,225 @@
+package policy
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	linkPattern         = regexp.MustCompile(`https?://[^\s"'<>)\]]+`)
+	markdownHeading     = regexp.MustCompile(`(?m)^#{1,6}\s+(.+?)\s*#*\s*$`)
+	htmlHeading         = regexp.MustCompile(`(?is)<h[1-6][^>]*>(.*?)</h[1-6]>`)
+	markdownImage       = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	htmlImage           = regexp.MustCompile(`(?is)<img\b[^>]*>`)
+	htmlAltAttribute    = regexp.MustCompile(`(?is)\balt\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	htmlTagPattern      = regexp.MustCompile(`<[^>]+>`)
+	multipleSpacesRegex = regexp.MustCompile(`\s+`)
+)
+
+type bannedWord struct {
+	word    string
+	pattern *regexp.Regexp
+}
+
+type bannedWordsRule struct {
+	severity string
+	words    []bannedWord
+}
+
+// NewBannedWordsRule reports every banned word or phrase found in the title or content
+func NewBannedWordsRule(severity string, words []string) Rule {
+	patterns := make([]bannedWord, 0, len(words))
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		patterns = append(patterns, bannedWord{
+			word:    word,
+			pattern: regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(word) + `\b`),
+		})
+	}
+	return &bannedWordsRule{severity: severity, words: patterns}
+}
+
+func (r *bannedWordsRule) Name() string { return "banned_words" }
+
+func (r *bannedWordsRule) Check(doc Document) []Violation {
+	var violations []Violation
+	for _, banned := range r.words {
+		if banned.pattern.MatchString(doc.Title) || banned.pattern.MatchString(doc.Content) {
+			violations = append(violations, Violation{
+				Rule:     r.Name(),
+				Severity: r.severity,
+				Message:  fmt.Sprintf("content contains the banned word %q", banned.word),
+			})
+		}
+	}
+	return violations
+}
+
+type maxLinksRule struct {
+	severity string
+	max      int
+}
+
+// NewMaxLinksRule reports content that contains more links than allowed
+func NewMaxLinksRule(severity string, max int) Rule {
+	return &maxLinksRule{severity: severity, max: max}
+}
+
+func (r *maxLinksRule) Name() string { return "max_links" }
+
+func (r *maxLinksRule) Check(doc Document) []Violation {
+	count := len(linkPattern.FindAllString(doc.Content, -1))
+	if count <= r.max {
+		return nil
+	}
+	return []Violation{{
+		Rule:     r.Name(),
+		Severity: r.severity,
+		Message:  fmt.Sprintf("content contains %d links, the maximum is %d", count, r.max),
+	}}
+}
+
+type requiredHeadingsRule struct {
+	severity string
+	min      int
+	headings []string
+}
+
+// NewRequiredHeadingsRule reports content with too few headings or missing a required heading.
+// Markdown (# Heading) and HTML (<h2>Heading</h2>) headings are recognised.
+func NewRequiredHeadingsRule(severity string, min int, headings []string) Rule {
+	return &requiredHeadingsRule{severity: severity, min: min, headings: headings}
+}
+
+func (r *requiredHeadingsRule) Name() string { return "required_headings" }
+
+func (r *requiredHeadingsRule) Check(doc Document) []Violation {
+	var found []string
+	for _, match := range markdownHeading.FindAllStringSubmatch(doc.Content, -1) {
+		found = append(found, strings.ToLower(match[1]))
+	}
+	for _, match := range htmlHeading.FindAllStringSubmatch(doc.Content, -1) {
+		found = append(found, strings.ToLower(plainText(match[1])))
+	}
+
+	var violations []Violation
+	if len(found) < r.min {
+		violations = append(violations, Violation{
+			Rule:     r.Name(),
+			Severity: r.severity,
+			Message:  fmt.Sprintf("content has %d headings, at least %d are required", len(found), r.min),
+		})
+	}
+
+	for _, required := range r.headings {
+		if !containsHeading(found, strings.ToLower(required)) {
+			violations = append(violations, Violation{
+				Rule:     r.Name(),
+				Severity: r.severity,
+				Message:  fmt.Sprintf("content is missing the required heading %q", required),
+			})
+		}
+	}
+	return violations
+}
+
+type imageAltTextRule struct {
+	severity  string
+	minLength int
+}
+
+// NewImageAltTextRule reports images whose alt text is missing or shorter than minLength
+func NewImageAltTextRule(severity string, minLength int) Rule {
+	if minLength <= 0 {
+		minLength = 1
+	}
+	return &imageAltTextRule{severity: severity, minLength: minLength}
+}
+
+func (r *imageAltTextRule) Name() string { return "image_alt_text" }
+
+func (r *imageAltTextRule) Check(doc Document) []Violation {
+	var alts []string
+	for _, match := range markdownImage.FindAllStringSubmatch(doc.Content, -1) {
+		alts = append(alts, match[1])
+	}
+	for _, tag := range htmlImage.FindAllString(doc.Content, -1) {
+		alt := ""
+		if match := htmlAltAttribute.FindStringSubmatch(tag); match != nil {
+			alt = match[1] + match[2]
+		}
+		alts = append(alts, alt)
+	}
+
+	var violations []Violation
+	for i, alt := range alts {
+		if len([]rune(strings.TrimSpace(alt))) < r.minLength {
+			violations = append(violations, Violation{
+				Rule:     r.Name(),
+				Severity: r.severity,
+				Message:  fmt.Sprintf("image %d needs an alt text of at least %d characters", i+1, r.minLength),
+			})
+		}
+	}
+	return violations
+}
+
+type forbiddenDomainsRule struct {
+	severity string
+	domains  []string
+}
+
+// NewForbiddenDomainsRule reports links pointing to a forbidden domain or one of its subdomains
+func NewForbiddenDomainsRule(severity string, domains []string) Rule {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(domain)))
+	}
+	return &forbiddenDomainsRule{severity: severity, domains: normalized}
+}
+
+func (r *forbiddenDomainsRule) Name() string { return "forbidden_domains" }
+
+func (r *forbiddenDomainsRule) Check(doc Document) []Violation {
+	var violations []Violation
+	reported := make(map[string]bool)
+	for _, link := range linkPattern.FindAllString(doc.Content, -1) {
+		parsed, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+
+		host := strings.ToLower(parsed.Hostname())
+		for _, domain := range r.domains {
+			if (host == domain || strings.HasSuffix(host, "."+domain)) && !reported[host] {
+				reported[host] = true
+				violations = append(violations, Violation{
+					Rule:     r.Name(),
+					Severity: r.severity,
+					Message:  fmt.Sprintf("content links to the forbidden domain %q", host),
+				})
+			}
+		}
+	}
+	return violations
+}
+
+func containsHeading(found []string, required string) bool {
+	for _, heading := range found {
+		if strings.Contains(heading, required) {
+			return true
+		}
+	}
+	return false
+}
+
+// plainText strips HTML tags and collapses whitespace
+func plainText(html string) string {
+	text := htmlTagPattern.ReplaceAllString(html, " ")
+	return strings.TrimSpace(multipleSpacesRegex.ReplaceAllString(text, " "))
+}
@@ -0,0 +1,33 @@
+package policy
+
+import "testing"
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		content string
+		want    int
+	}{
+		{"banned word", NewBannedWordsRule(SeverityBlocking, []string{"judi online", " "}), "Main JUDI ONLINE sekarang", 1},
+		{"banned word inside another word", NewBannedWordsRule(SeverityBlocking, []string{"judi"}), "Perjudian dilarang", 0},
+		{"links within the maximum", NewMaxLinksRule(SeverityWarning, 2), "https://a.example and http://b.example", 0},
+		{"too many links", NewMaxLinksRule(SeverityWarning, 1), "https://a.example and http://b.example", 1},
+		{"markdown and HTML headings", NewRequiredHeadingsRule(SeverityWarning, 2, []string{"Kesimpulan"}), "# Pendahuluan\n\n<h2><b>Kesimpulan</b></h2>", 0},
+		{"too few headings and a missing one", NewRequiredHeadingsRule(SeverityWarning, 1, []string{"Kesimpulan"}), "No headings here", 2},
+		{"alt texts", NewImageAltTextRule(SeverityWarning, 5), `![Grafik penjualan](a.png) <img src="b.png" alt='Foto tim'>`, 0},
+		{"missing and short alt texts", NewImageAltTextRule(SeverityWarning, 5), `![](a.png) <img src="b.png" alt="x"> <img src="c.png">`, 3},
+		{"forbidden domain and subdomain, reported once each", NewForbiddenDomainsRule(SeverityBlocking, []string{"Bit.ly"}), "https://bit.ly/a https://bit.ly/b https://go.bit.ly/c https://notbit.ly/d", 2},
+	}
+	for _, tt := range tests {
+		violations := tt.rule.Check(Document{Content: tt.content})
+		if len(violations) != tt.want {
+			t.Errorf("%s: got violations %+v, want %d", tt.name, violations, tt.want)
+		}
+		for _, violation := range violations {
+			if violation.Rule != tt.rule.Name() || violation.Message == "" {
+				t.Errorf("%s: got violation %+v, want it named after the rule with a message", tt.name, violation)
+			}
+		}
+	}
+}
@@ -1,13 +1,18 @@
 package repository
 
 import (
+	"errors"
 	"time"
 
 	"github.com/yuhari7/backend_supervision/article/config"
 	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/shared/outbox"
 	"gorm.io/gorm"
 )
 
+// ErrNotFound is returned when the requested article does not exist
+var ErrNotFound = errors.New("article not found")
+
 // ArticleRepository defines the methods for interacting with the database
 type ArticleRepository interface {
 	Create(article *entity.Article) error
@@ -16,36 +21,73 @@ type ArticleRepository interface {
 	Update(article *entity.Article) error
 	Delete(id uint) error
 	SoftDelete(id uint) error
-	FindWithPagination(limit, offset int, articles *[]entity.Article) error
+	FindWithPagination(locale string, limit, offset int, articles *[]entity.Article) error
 	SearchArticles(query string, limit, offset int) ([]entity.Article, error)
+	FindByStatus(status string) ([]entity.Article, error)
+	FindFingerprints() ([]entity.Article, error)
+	FindWithoutFingerprint(limit int) ([]entity.Article, error)
+	UpdateFingerprint(id uint, fingerprint int64) error
+	FindTranslation(groupID uint, locale string) (*entity.Article, error)
+	FindTranslations(groupID uint) ([]entity.Article, error)
+	FindByIDs(ids []uint) ([]entity.Article, error)
+	FindByGroups(groupIDs []uint) ([]entity.Article, error)
+	FindCategories() ([]entity.CategoryCount, error)
+	FindByCategories(categories []string, limitPerCategory int) ([]entity.Article, error)
+	Transaction(fn func(tx ArticleRepository) error) error
+	AddEvents(events ...outbox.Event) error
 }
 
-type articleRepository struct{}
+type articleRepository struct {
+	db *gorm.DB
+}
 
 // NewArticleRepository creates a new instance of ArticleRepository
 func NewArticleRepository() ArticleRepository {
-	return &articleRepository{}
+	return &articleRepository{db: config.DB}
+}
+
+// Transaction runs fn with a repository whose writes are committed together, or not at all when fn fails
+func (r *articleRepository) Transaction(fn func(tx ArticleRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&articleRepository{db: tx})
+	})
 }
 
-// Create inserts a new article into the database
+// AddEvents writes domain events to the outbox, inside a transaction they are only published if it commits
+func (r *articleRepository) AddEvents(events ...outbox.Event) error {
+	return outbox.Add(r.db, events...)
+}
+
+// Create inserts a new article into the database.
+// An article created without a translation group becomes the source of its own group.
 func (r *articleRepository) Create(article *entity.Article) error {
-	return config.DB.Create(article).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(article).Error; err != nil {
+			return err
+		}
+		if article.TranslationGroupID != 0 {
+			return nil
+		}
+
+		article.TranslationGroupID = article.ID
+		return tx.Model(article).Update("translation_group_id", article.ID).Error
+	})
 }
 
 // FindAll returns all articles from the database
 func (r *articleRepository) FindAll() ([]entity.Article, error) {
 	var articles []entity.Article
-	err := config.DB.Find(&articles).Error
+	err := r.db.Find(&articles).Error
 	return articles, err
 }
 
-// FindByID finds an article by its ID
+// FindByID finds an article by its ID, returning ErrNotFound when it does not exist
 func (r *articleRepository) FindByID(id uint) (*entity.Article, error) {
 	var article entity.Article
-	err := config.DB.First(&article, id).Error
+	err := r.db.First(&article, id).Error
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, nil // If the article is not found, return nil
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
 		}
 		return nil, err // Return other errors
 	}
@@ -54,25 +96,134 @@ func (r *articleRepository) FindByID(id uint) (*entity.Article, error) {
 
 // Update updates an article in the database
 func (r *articleRepository) Update(article *entity.Article) error {
-	return config.DB.Save(article).Error
+	return r.db.Save(article).Error
 }
 
-// Delete deletes an article by its ID
+// Delete deletes an article by its ID, returning ErrNotFound when nothing was deleted
 func (r *articleRepository) Delete(id uint) error {
-	return config.DB.Delete(&entity.Article{}, id).Error
+	result := r.db.Delete(&entity.Article{}, id)
+	return affected(result)
 }
 
 // SoftDelete sets the deleted_at timestamp to implement soft delete
 func (r *articleRepository) SoftDelete(id uint) error {
-	return config.DB.Model(&entity.Article{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error
+	result := r.db.Model(&entity.Article{}).Where("id = ?", id).Update("deleted_at", time.Now())
+	return affected(result)
 }
 
-func (r *articleRepository) FindWithPagination(limit, offset int, articles *[]entity.Article) error {
-	return config.DB.Limit(limit).Offset(offset).Find(articles).Error
+// affected turns a write that matched no rows into ErrNotFound
+func affected(result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *articleRepository) FindWithPagination(locale string, limit, offset int, articles *[]entity.Article) error {
+	query := r.db.Model(&entity.Article{})
+	if locale != "" {
+		query = query.Where("locale = ?", locale)
+	}
+	return query.Limit(limit).Offset(offset).Find(articles).Error
 }
 
 func (r *articleRepository) SearchArticles(query string, limit, offset int) ([]entity.Article, error) {
 	var articles []entity.Article
-	return articles, config.DB.Where("title ILIKE ? OR status ILIKE ? OR content ILIKE ? OR category ILIKE ?", "%"+query+"%", "%"+query+"%", "%"+query+"%", "%"+query+"%").
+	return articles, r.db.Where("title ILIKE ? OR status ILIKE ? OR content ILIKE ? OR category ILIKE ?", "%"+query+"%", "%"+query+"%", "%"+query+"%", "%"+query+"%").
 		Limit(limit).Offset(offset).Find(&articles).Error
 }
+
+// FindByStatus returns all articles with the given status
+func (r *articleRepository) FindByStatus(status string) ([]entity.Article, error) {
+	var articles []entity.Article
+	err := r.db.Where("status = ?", status).Find(&articles).Error
+	return articles, err
+}
+
+// FindFingerprints returns the id, title, status and content fingerprint of every fingerprinted article
+func (r *articleRepository) FindFingerprints() ([]entity.Article, error) {
+	var articles []entity.Article
+	err := r.db.Select("id", "title", "status", "content_fingerprint").
+		Where("content_fingerprint IS NOT NULL").
+		Find(&articles).Error
+	return articles, err
+}
+
+// FindWithoutFingerprint returns articles whose content fingerprint has not been computed yet
+func (r *articleRepository) FindWithoutFingerprint(limit int) ([]entity.Article, error) {
+	var articles []entity.Article
+	err := r.db.Where("content_fingerprint IS NULL").Limit(limit).Find(&articles).Error
+	return articles, err
+}
+
+// UpdateFingerprint stores the content fingerprint of an article
+func (r *articleRepository) UpdateFingerprint(id uint, fingerprint int64) error {
+	return r.db.Model(&entity.Article{}).Where("id = ?", id).Update("content_fingerprint", fingerprint).Error
+}
+
+// FindTranslation finds the article of a translation group written in the given locale,
+// returning ErrNotFound when the group has no article in that locale
+func (r *articleRepository) FindTranslation(groupID uint, locale string) (*entity.Article, error) {
+	var article entity.Article
+	err := r.db.Where("translation_group_id = ? AND locale = ?", groupID, locale).First(&article).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &article, nil
+}
+
+// FindTranslations returns every article of a translation group, including the source
+func (r *articleRepository) FindTranslations(groupID uint) ([]entity.Article, error) {
+	var articles []entity.Article
+	err := r.db.Where("translation_group_id = ?", groupID).Order("id ASC").Find(&articles).Error
+	return articles, err
+}
+
+// FindByIDs returns the articles with the given IDs, IDs without an article are skipped
+func (r *articleRepository) FindByIDs(ids []uint) ([]entity.Article, error) {
+	var articles []entity.Article
+	err := r.db.Where("id IN ?", ids).Order("id ASC").Find(&articles).Error
+	return articles, err
+}
+
+// FindByGroups returns every article of the given translation groups, ordered by group and ID
+func (r *articleRepository) FindByGroups(groupIDs []uint) ([]entity.Article, error) {
+	var articles []entity.Article
+	err := r.db.Where("translation_group_id IN ?", groupIDs).
+		Order("translation_group_id ASC, id ASC").
+		Find(&articles).Error
+	return articles, err
+}
+
+// FindCategories returns every category with the number of articles not in the trash
+func (r *articleRepository) FindCategories() ([]entity.CategoryCount, error) {
+	var categories []entity.CategoryCount
+	err := r.db.Model(&entity.Article{}).
+		Select("category, COUNT(*) AS articles").
+		Where("deleted_at IS NULL").
+		Group("category").
+		Order("category ASC").
+		Scan(&categories).Error
+	return categories, err
+}
+
+// FindByCategories returns the newest articles of each of the given categories, ordered by category
+func (r *articleRepository) FindByCategories(categories []string, limitPerCategory int) ([]entity.Article, error) {
+	var articles []entity.Article
+	err := r.db.Raw(`
+		SELECT * FROM (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY category ORDER BY created_date DESC, id DESC) AS rank
+			FROM articles
+			WHERE category IN ? AND deleted_at IS NULL
+		) ranked
+		WHERE rank <= ?
+		ORDER BY category ASC, rank ASC`, categories, limitPerCategory).
+		Scan(&articles).Error
+	return articles, err
+}
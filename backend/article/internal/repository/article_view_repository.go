@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/article/config"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+)
+
+// ArticleViewRepository defines the methods for storing and reading article view counts
+type ArticleViewRepository interface {
+	IncrementViews(views []entity.ArticleViewDaily) error
+	FindDailyViews(articleID uint, from time.Time) ([]entity.ArticleViewDaily, error)
+	FindTopByCategory(from time.Time, limitPerCategory int) ([]entity.ArticleViewTotal, error)
+}
+
+type articleViewRepository struct{}
+
+// NewArticleViewRepository creates a new instance of ArticleViewRepository
+func NewArticleViewRepository() ArticleViewRepository {
+	return &articleViewRepository{}
+}
+
+// IncrementViews adds the given counts to the daily totals, creating rows when needed.
+// Views of articles deleted in the meantime are dropped, so they cannot fail the whole batch.
+func (r *articleViewRepository) IncrementViews(views []entity.ArticleViewDaily) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	rows := make([]string, 0, len(views))
+	args := make([]interface{}, 0, len(views)*3)
+	for _, view := range views {
+		rows = append(rows, "(?::int, ?::date, ?::bigint)")
+		args = append(args, view.ArticleID, view.ViewDate, view.Views)
+	}
+
+	return config.DB.Exec(`
+		INSERT INTO article_views_daily (article_id, view_date, views)
+		SELECT v.article_id, v.view_date, v.views
+		FROM (VALUES `+strings.Join(rows, ", ")+`) AS v (article_id, view_date, views)
+		WHERE EXISTS (SELECT 1 FROM articles a WHERE a.id = v.article_id)
+		ON CONFLICT (article_id, view_date) DO UPDATE SET views = article_views_daily.views + EXCLUDED.views`, args...).Error
+}
+
+// FindDailyViews returns the daily view counts of an article starting from the given date
+func (r *articleViewRepository) FindDailyViews(articleID uint, from time.Time) ([]entity.ArticleViewDaily, error) {
+	var views []entity.ArticleViewDaily
+	err := config.DB.Where("article_id = ? AND view_date >= ?", articleID, from).
+		Order("view_date ASC").
+		Find(&views).Error
+	return views, err
+}
+
+// FindTopByCategory returns the most viewed articles of each category since the given date
+func (r *articleViewRepository) FindTopByCategory(from time.Time, limitPerCategory int) ([]entity.ArticleViewTotal, error) {
+	var totals []entity.ArticleViewTotal
+	err := config.DB.Raw(`
+		SELECT article_id, title, category, views FROM (
+			SELECT a.id AS article_id, a.title, a.category, SUM(v.views) AS views,
+				ROW_NUMBER() OVER (PARTITION BY a.category ORDER BY SUM(v.views) DESC, a.id ASC) AS rank
+			FROM article_views_daily v
+			JOIN articles a ON a.id = v.article_id
+			WHERE v.view_date >= ? AND a.deleted_at IS NULL
+			GROUP BY a.id, a.title, a.category
+		) ranked
+		WHERE rank <= ?
+		ORDER BY category ASC, views DESC`, from, limitPerCategory).
+		Scan(&totals).Error
+	return totals, err
+}
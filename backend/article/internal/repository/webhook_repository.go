@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/article/config"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookRepository defines the methods for storing webhook subscriptions and their deliveries
+type WebhookRepository interface {
+	Create(subscription *entity.WebhookSubscription) error
+	FindAll() ([]entity.WebhookSubscription, error)
+	FindByID(id uint) (*entity.WebhookSubscription, error)
+	FindActive() ([]entity.WebhookSubscription, error)
+	Update(subscription *entity.WebhookSubscription) error
+	Delete(id uint) error
+	RecordSuccess(subscriptionID uint) error
+	RecordFailure(subscriptionID uint, disableAfter int) (disabled bool, err error)
+
+	CreateDeliveries(deliveries []entity.WebhookDelivery) error
+	ClaimDeliveries(limit int, lease time.Duration) ([]entity.WebhookDelivery, error)
+	FindDeliveries(subscriptionID uint, limit, offset int) ([]entity.WebhookDelivery, error)
+	FindDelivery(subscriptionID, id uint) (*entity.WebhookDelivery, error)
+	UpdateDelivery(delivery *entity.WebhookDelivery) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository
+func NewWebhookRepository() WebhookRepository {
+	return &webhookRepository{db: config.DB}
+}
+
+func (r *webhookRepository) Create(subscription *entity.WebhookSubscription) error {
+	return r.db.Create(subscription).Error
+}
+
+func (r *webhookRepository) FindAll() ([]entity.WebhookSubscription, error) {
+	var subscriptions []entity.WebhookSubscription
+	err := r.db.Order("id ASC").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// FindByID finds a subscription by its ID, returning ErrNotFound when it does not exist
+func (r *webhookRepository) FindByID(id uint) (*entity.WebhookSubscription, error) {
+	var subscription entity.WebhookSubscription
+	err := r.db.First(&subscription, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *webhookRepository) FindActive() ([]entity.WebhookSubscription, error) {
+	var subscriptions []entity.WebhookSubscription
+	err := r.db.Where("active").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *webhookRepository) Update(subscription *entity.WebhookSubscription) error {
+	return r.db.Save(subscription).Error
+}
+
+// Delete removes a subscription and its delivery log, returning ErrNotFound when nothing was deleted
+func (r *webhookRepository) Delete(id uint) error {
+	return affected(r.db.Delete(&entity.WebhookSubscription{}, id))
+}
+
+// RecordSuccess resets the consecutive failures of a subscription
+func (r *webhookRepository) RecordSuccess(subscriptionID uint) error {
+	return r.db.Model(&entity.WebhookSubscription{}).
+		Where("id = ? AND consecutive_failures > 0", subscriptionID).
+		Update("consecutive_failures", 0).Error
+}
+
+// RecordFailure counts a failed attempt and disables the subscription once disableAfter attempts in a row failed
+func (r *webhookRepository) RecordFailure(subscriptionID uint, disableAfter int) (bool, error) {
+	var result struct{ Active bool }
+	err := r.db.Raw(`
+		UPDATE webhook_subscriptions SET
+			consecutive_failures = consecutive_failures + 1,
+			active = active AND consecutive_failures + 1 < ?,
+			disabled_at = CASE WHEN active AND consecutive_failures + 1 >= ? THEN ? ELSE disabled_at END
+		WHERE id = ?
+		RETURNING active`,
+		disableAfter, disableAfter, time.Now().UTC(), subscriptionID,
+	).Scan(&result).Error
+	return !result.Active, err
+}
+
+// CreateDeliveries queues deliveries, an event already queued for a subscription is skipped
+func (r *webhookRepository) CreateDeliveries(deliveries []entity.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "subscription_id"}, {Name: "event_id"}},
+		DoNothing: true,
+	}).Create(&deliveries).Error
+}
+
+// ClaimDeliveries pushes the next attempt of due deliveries to active subscriptions back by the lease,
+// rows locked by another worker are skipped
+func (r *webhookRepository) ClaimDeliveries(limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	now := time.Now().UTC()
+	var deliveries []entity.WebhookDelivery
+	err := r.db.Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhook_subscriptions s ON s.id = d.subscription_id
+			WHERE d.status = ? AND d.next_attempt_at <= ? AND s.active
+			ORDER BY d.id
+			LIMIT ?
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING *`,
+		now.Add(lease), entity.DeliveryPending, now, limit,
+	).Scan(&deliveries).Error
+	return deliveries, err
+}
+
+// FindDeliveries returns the delivery log of a subscription, newest first
+func (r *webhookRepository) FindDeliveries(subscriptionID uint, limit, offset int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	err := r.db.Where("subscription_id = ?", subscriptionID).
+		Order("id DESC").Limit(limit).Offset(offset).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// FindDelivery finds a delivery of a subscription, returning ErrNotFound when it does not exist
+func (r *webhookRepository) FindDelivery(subscriptionID, id uint) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := r.db.Where("subscription_id = ?", subscriptionID).First(&delivery, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) UpdateDelivery(delivery *entity.WebhookDelivery) error {
+	return r.db.Save(delivery).Error
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/article/internal/repository"
+	"github.com/yuhari7/backend_supervision/shared/outbox"
+)
+
+// Article domain events, written to the outbox together with the change
+const (
+	EventArticleCreated   = "article.created"
+	EventArticleUpdated   = "article.updated"
+	EventArticlePublished = "article.published"
+	EventArticleTrashed   = "article.trashed"
+	EventArticleDeleted   = "article.deleted"
+)
+
+// ArticleEvents lists every article event type
+var ArticleEvents = []string{EventArticleCreated, EventArticleUpdated, EventArticlePublished, EventArticleTrashed, EventArticleDeleted}
+
+// articleEvents builds the events of saving an article, previousStatus is empty for a new article
+func articleEvents(article entity.Article, previousStatus string, eventTypes ...string) ([]outbox.Event, error) {
+	if article.Status == entity.StatusPublish && previousStatus != entity.StatusPublish {
+		eventTypes = append(eventTypes, EventArticlePublished)
+	}
+
+	payload := dto.ArticleEvent{
+		ID:                 article.ID,
+		Title:              article.Title,
+		Category:           article.Category,
+		Status:             article.Status,
+		Tags:               article.Tags,
+		Locale:             article.Locale,
+		TranslationGroupID: article.TranslationGroupID,
+		Revision:           article.Revision,
+		AuthorID:           article.AuthorID,
+	}
+	events := make([]outbox.Event, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		event, err := outbox.NewEvent(eventType, "article", article.ID, payload)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// saveWithEvents runs save and writes the resulting events in one transaction
+func (u *articleUsecase) saveWithEvents(article *entity.Article, previousStatus string, save func(repository.ArticleRepository) error, eventTypes ...string) error {
+	return u.repo.Transaction(func(tx repository.ArticleRepository) error {
+		if err := save(tx); err != nil {
+			return err
+		}
+		events, err := articleEvents(*article, previousStatus, eventTypes...)
+		if err != nil {
+			return err
+		}
+		return tx.AddEvents(events...)
+	})
+}
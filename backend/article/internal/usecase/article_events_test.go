@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+)
+
+func TestArticleLifecycleEvents(t *testing.T) {
+	repo := newFakeArticleRepository()
+	articles := newUsecases(repo).articles
+	content := strings.Repeat("Lorem ipsum dolor sit amet. ", 10)
+
+	created, err := articles.CreateArticle(dto.CreateArticleRequest{Title: "A draft about outboxes", Content: content, Category: "Tech", Status: entity.StatusDraft, Locale: "id"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	id := created.Article.ID
+
+	// Publishing a draft adds article.published, saving it again while published does not
+	update := dto.UpdateArticleRequest{ID: id, Title: "A draft about outboxes", Content: content, Category: "Tech", Status: entity.StatusPublish}
+	for i := 0; i < 2; i++ {
+		if _, err := articles.UpdateArticle(update); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := articles.SoftDeleteArticle(dto.SoftDeleteArticleDTO{ID: id}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := articles.DeleteArticle(dto.SoftDeleteArticleDTO{ID: id}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{EventArticleCreated, EventArticleUpdated, EventArticlePublished, EventArticleUpdated, EventArticleTrashed, EventArticleDeleted}
+	if got := repo.eventTypes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got events %v, want %v", got, want)
+	}
+
+	var payload dto.ArticleEvent
+	if err := json.Unmarshal(repo.events[2].Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.ID != id || payload.Status != entity.StatusPublish || repo.events[2].AggregateID != "1" {
+		t.Errorf("got payload %+v for aggregate %s, want the published article %d", payload, repo.events[2].AggregateID, id)
+	}
+}
+
+func TestFailedEventRollsBackSave(t *testing.T) {
+	repo := newFakeArticleRepository(entity.Article{ID: 1, Title: "A draft about outboxes", Status: entity.StatusDraft})
+	repo.eventsErr = errDatabase
+
+	if _, err := newUsecases(repo).articles.SoftDeleteArticle(dto.SoftDeleteArticleDTO{ID: 1}); err == nil {
+		t.Fatal("expected an error when the events cannot be written")
+	}
+	if repo.articles[1].Status != entity.StatusDraft || len(repo.events) != 0 {
+		t.Errorf("got status %s with %d events, want the change rolled back", repo.articles[1].Status, len(repo.events))
+	}
+}
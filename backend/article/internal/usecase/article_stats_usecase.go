@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/repository"
+)
+
+const maxStatsDays = 365
+
+// ArticleStatsUsecase defines the methods for reading article view statistics
+type ArticleStatsUsecase interface {
+	GetArticleStats(id uint, days int) (dto.ArticleStatsResponse, error)
+	GetTopArticles(period string, limit int) (dto.TopArticlesResponse, error)
+}
+
+type articleStatsUsecase struct {
+	articleRepo repository.ArticleRepository
+	viewRepo    repository.ArticleViewRepository
+}
+
+// NewArticleStatsUsecase creates a new instance of ArticleStatsUsecase
+func NewArticleStatsUsecase(articleRepo repository.ArticleRepository, viewRepo repository.ArticleViewRepository) ArticleStatsUsecase {
+	return &articleStatsUsecase{articleRepo: articleRepo, viewRepo: viewRepo}
+}
+
+// GetArticleStats returns the daily view time series of an article for the last given days
+func (u *articleStatsUsecase) GetArticleStats(id uint, days int) (dto.ArticleStatsResponse, error) {
+	if days <= 0 || days > maxStatsDays {
+		days = 30
+	}
+
+	if _, err := findArticle(u.articleRepo, id); err != nil {
+		return dto.ArticleStatsResponse{}, err
+	}
+
+	from := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+	views, err := u.viewRepo.FindDailyViews(id, from)
+	if err != nil {
+		return dto.ArticleStatsResponse{}, err
+	}
+
+	counts := make(map[string]int64, len(views))
+	for _, view := range views {
+		counts[view.ViewDate.Format("2006-01-02")] = view.Views
+	}
+
+	// Fill every day of the period so days without views are reported as zero
+	response := dto.ArticleStatsResponse{ArticleID: id, Days: days}
+	for i := 0; i < days; i++ {
+		date := from.AddDate(0, 0, i).Format("2006-01-02")
+		response.Daily = append(response.Daily, dto.DailyViews{Date: date, Views: counts[date]})
+		response.TotalViews += counts[date]
+	}
+
+	return response, nil
+}
+
+// GetTopArticles returns the most viewed articles of each category within the period (e.g. "7d")
+func (u *articleStatsUsecase) GetTopArticles(period string, limit int) (dto.TopArticlesResponse, error) {
+	days, err := parsePeriodDays(period)
+	if err != nil {
+		return dto.TopArticlesResponse{}, err
+	}
+	if limit <= 0 {
+		limit = 5
+	}
+
+	from := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+	totals, err := u.viewRepo.FindTopByCategory(from, limit)
+	if err != nil {
+		return dto.TopArticlesResponse{}, err
+	}
+
+	// Totals are ordered by category, so consecutive rows belong to the same group
+	response := dto.TopArticlesResponse{Period: period, Categories: []dto.CategoryTopArticles{}}
+	for _, total := range totals {
+		last := len(response.Categories) - 1
+		if last < 0 || response.Categories[last].Category != total.Category {
+			response.Categories = append(response.Categories, dto.CategoryTopArticles{Category: total.Category})
+			last++
+		}
+		response.Categories[last].Articles = append(response.Categories[last].Articles, dto.TopArticle{
+			ArticleID: total.ArticleID,
+			Title:     total.Title,
+			Views:     total.Views,
+		})
+	}
+
+	return response, nil
+}
+
+// parsePeriodDays converts a period such as "7d" into a number of days
+func parsePeriodDays(period string) (int, error) {
+	if !strings.HasSuffix(period, "d") {
+		return 0, ErrInvalidPeriod
+	}
+
+	days, err := strconv.Atoi(strings.TrimSuffix(period, "d"))
+	if err != nil || days <= 0 || days > maxStatsDays {
+		return 0, ErrInvalidPeriod
+	}
+
+	return days, nil
+}
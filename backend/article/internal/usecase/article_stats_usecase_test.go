@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+)
+
+func TestArticleStatsFillsEmptyDays(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	views := &fakeViewRepository{daily: []entity.ArticleViewDaily{
+		{ArticleID: 1, ViewDate: today.AddDate(0, 0, -2), Views: 4},
+		{ArticleID: 1, ViewDate: today, Views: 3},
+		{ArticleID: 2, ViewDate: today, Views: 9},
+	}}
+	stats := NewArticleStatsUsecase(newFakeArticleRepository(entity.Article{ID: 1, Title: "Berita"}), views)
+
+	response, err := stats.GetArticleStats(1, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []dto.DailyViews{
+		{Date: today.AddDate(0, 0, -2).Format("2006-01-02"), Views: 4},
+		{Date: today.AddDate(0, 0, -1).Format("2006-01-02"), Views: 0},
+		{Date: today.Format("2006-01-02"), Views: 3},
+	}
+	if !reflect.DeepEqual(response.Daily, want) || response.TotalViews != 7 {
+		t.Errorf("got %+v, want %+v with 7 views in total", response, want)
+	}
+
+	// Out of range periods fall back to 30 days
+	for _, days := range []int{0, -1, maxStatsDays + 1} {
+		response, err := stats.GetArticleStats(1, days)
+		if err != nil || response.Days != 30 || len(response.Daily) != 30 {
+			t.Errorf("got %d days and error %v for %d, want 30", len(response.Daily), err, days)
+		}
+	}
+}
+
+func TestTopArticlesAreGroupedByCategory(t *testing.T) {
+	views := &fakeViewRepository{totals: []entity.ArticleViewTotal{
+		{ArticleID: 3, Title: "Pemilu", Category: "News", Views: 20},
+		{ArticleID: 1, Title: "Banjir", Category: "News", Views: 10},
+		{ArticleID: 2, Title: "Golang", Category: "Tech", Views: 5},
+	}}
+	stats := NewArticleStatsUsecase(newFakeArticleRepository(), views)
+
+	response, err := stats.GetTopArticles("7d", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []dto.CategoryTopArticles{
+		{Category: "News", Articles: []dto.TopArticle{{ArticleID: 3, Title: "Pemilu", Views: 20}, {ArticleID: 1, Title: "Banjir", Views: 10}}},
+		{Category: "Tech", Articles: []dto.TopArticle{{ArticleID: 2, Title: "Golang", Views: 5}}},
+	}
+	if response.Period != "7d" || !reflect.DeepEqual(response.Categories, want) {
+		t.Errorf("got %+v, want %+v", response.Categories, want)
+	}
+
+	// Without views the categories are an empty list rather than null
+	empty, err := NewArticleStatsUsecase(newFakeArticleRepository(), &fakeViewRepository{}).GetTopArticles("1d", 5)
+	if err != nil || empty.Categories == nil || len(empty.Categories) != 0 {
+		t.Errorf("got %+v and error %v, want no categories", empty, err)
+	}
+}
+
+func TestParsePeriodDays(t *testing.T) {
+	tests := []struct {
+		period string
+		want   int
+	}{
+		{"1d", 1},
+		{"7d", 7},
+		{"365d", 365},
+		{"0d", 0},
+		{"366d", 0},
+		{"-7d", 0},
+		{"7", 0},
+		{"d", 0},
+		{"7w", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		days, err := parsePeriodDays(tt.period)
+		if tt.want == 0 && !errors.Is(err, ErrInvalidPeriod) {
+			t.Errorf("got %d and error %v for %q, want ErrInvalidPeriod", days, err, tt.period)
+		}
+		if tt.want != 0 && (err != nil || days != tt.want) {
+			t.Errorf("got %d and error %v for %q, want %d", days, err, tt.period, tt.want)
+		}
+	}
+}
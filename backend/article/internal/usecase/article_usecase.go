@@ -5,131 +5,266 @@ import (
 
 	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
 	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/article/internal/policy"
 	"github.com/yuhari7/backend_supervision/article/internal/repository"
 )
 
 // ArticleUsecase defines the methods for interacting with articles
 type ArticleUsecase interface {
-	CreateArticle(dto dto.CreateArticleRequest) (entity.Article, error)
-	UpdateArticle(dto dto.UpdateArticleRequest) (entity.Article, error)
+	CreateArticle(dto dto.CreateArticleRequest) (dto.ArticleWriteResponse, error)
+	UpdateArticle(dto dto.UpdateArticleRequest) (dto.ArticleWriteResponse, error)
 	SoftDeleteArticle(dto dto.SoftDeleteArticleDTO) (entity.Article, error)
 	DeleteArticle(dto dto.SoftDeleteArticleDTO) error
 	FindByID(id uint) (dto.ArticleResponse, error)
-	FindAllArticles(limit, offset int) ([]dto.ArticleResponse, error)
+	FindAllArticles(limit, offset int, locale string) ([]dto.ArticleResponse, error)
 	SearchArticles(query string, limit, offset int) ([]dto.ArticleResponse, error)
+	FindByIDs(ids []uint) (map[uint]dto.ArticleResponse, error)
+	FindCategories() ([]dto.CategoryResponse, error)
+	FindByCategories(categories []string, limit int) (map[string][]dto.ArticleResponse, error)
+}
+
+// ArticleObserver is notified after an article has been saved or permanently removed
+type ArticleObserver interface {
+	ArticleSaved(article entity.Article)
+	ArticleRemoved(id uint)
 }
 
 type articleUsecase struct {
-	repo repository.ArticleRepository
+	repo          repository.ArticleRepository
+	duplicates    DuplicateDetector
+	contentPolicy *policy.Engine
+	locales       LocaleConfig
+	authors       AuthorDirectory
+	observers     []ArticleObserver
 }
 
-// NewArticleUsecase creates a new instance of ArticleUsecase
-func NewArticleUsecase(r repository.ArticleRepository) ArticleUsecase {
-	return &articleUsecase{repo: r}
+// NewArticleUsecase creates a new instance of ArticleUsecase, authors may be nil to only show author IDs
+func NewArticleUsecase(r repository.ArticleRepository, duplicates DuplicateDetector, contentPolicy *policy.Engine, locales LocaleConfig, authors AuthorDirectory, observers ...ArticleObserver) ArticleUsecase {
+	return &articleUsecase{repo: r, duplicates: duplicates, contentPolicy: contentPolicy, locales: locales, authors: authors, observers: observers}
 }
 
-// FindAllArticles retrieves all articles from the repository
-func (u *articleUsecase) FindAllArticles(limit, offset int) ([]dto.ArticleResponse, error) {
-	var articles []entity.Article
-	// Fetch articles with limit and offset
-	err := u.repo.FindWithPagination(limit, offset, &articles)
+// checkPolicy runs the content policy, blocking violations are only fatal when the article is published
+func (u *articleUsecase) checkPolicy(article entity.Article) ([]policy.Violation, error) {
+	result := u.contentPolicy.Evaluate(policy.Document{
+		Title:    article.Title,
+		Content:  article.Content,
+		Category: article.Category,
+	})
+
+	if article.Status == entity.StatusPublish && result.Blocking() {
+		return nil, ErrContentPolicyViolation.With("violations", result.Violations)
+	}
+	return result.Violations, nil
+}
+
+func (u *articleUsecase) notifySaved(article entity.Article) {
+	for _, observer := range u.observers {
+		observer.ArticleSaved(article)
+	}
+}
+
+func (u *articleUsecase) notifyRemoved(id uint) {
+	for _, observer := range u.observers {
+		observer.ArticleRemoved(id)
+	}
+}
+
+// findArticle loads an article, reporting a missing row as ErrArticleNotFound
+func findArticle(repo repository.ArticleRepository, id uint) (*entity.Article, error) {
+	article, err := repo.FindByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrArticleNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
+	return article, nil
+}
 
-	// Prepare response DTOs
-	var responses []dto.ArticleResponse
+// toArticleResponse converts an article entity to the response DTO
+func toArticleResponse(article entity.Article) dto.ArticleResponse {
+	return dto.ArticleResponse{
+		ID:                 article.ID,
+		Title:              article.Title,
+		Content:            article.Content,
+		Category:           article.Category,
+		Status:             article.Status,
+		Tags:               article.Tags,
+		Locale:             article.Locale,
+		TranslationGroupID: article.TranslationGroupID,
+		Revision:           article.Revision,
+		CreatedAt:          article.CreatedDate.Format("2006-01-02 15:04:05"),
+		UpdatedAt:          article.UpdatedDate.Format("2006-01-02 15:04:05"),
+		Author:             authorOf(article),
+	}
+}
+
+// authorOf returns the author of an article with its ID only, the rest is filled in by fillAuthors
+func authorOf(article entity.Article) *dto.AuthorResponse {
+	if article.AuthorID == nil {
+		return nil
+	}
+	return &dto.AuthorResponse{ID: *article.AuthorID}
+}
+
+// toArticleResponses converts a page of articles and looks up their authors
+func toArticleResponses(authors AuthorDirectory, articles []entity.Article) []dto.ArticleResponse {
+	responses := make([]dto.ArticleResponse, 0, len(articles))
 	for _, article := range articles {
-		response := dto.ArticleResponse{
-			ID:        article.ID,
-			Title:     article.Title,
-			Content:   article.Content,
-			Category:  article.Category,
-			Status:    article.Status,
-			CreatedAt: article.CreatedDate.Format("2006-01-02 15:04:05"),
-			UpdatedAt: article.UpdatedDate.Format("2006-01-02 15:04:05"),
-		}
-		responses = append(responses, response)
+		responses = append(responses, toArticleResponse(article))
 	}
+	fillAuthors(authors, responses)
+	return responses
+}
 
-	return responses, nil
+// FindAllArticles retrieves all articles from the repository, optionally only those in the given locale
+func (u *articleUsecase) FindAllArticles(limit, offset int, locale string) ([]dto.ArticleResponse, error) {
+	var articles []entity.Article
+	// Fetch articles with limit and offset
+	err := u.repo.FindWithPagination(locale, limit, offset, &articles)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare response DTOs together with the authors of the page
+	return toArticleResponses(u.authors, articles), nil
 }
 
 func (u *articleUsecase) FindByID(id uint) (dto.ArticleResponse, error) {
-	article, err := u.repo.FindByID(id)
+	article, err := findArticle(u.repo, id)
 	if err != nil {
-		return dto.ArticleResponse{}, errors.New("article not found")
+		return dto.ArticleResponse{}, err
 	}
 
 	// Convert the article entity to the response DTO
-	response := dto.ArticleResponse{
-		ID:        article.ID,
-		Title:     article.Title,
-		Content:   article.Content,
-		Category:  article.Category,
-		Status:    article.Status,
-		CreatedAt: article.CreatedDate.Format("2006-01-02 15:04:05"),
-		UpdatedAt: article.UpdatedDate.Format("2006-01-02 15:04:05"),
-	}
-
-	return response, nil
+	return toArticleResponses(u.authors, []entity.Article{*article})[0], nil
 }
 
-func (u *articleUsecase) CreateArticle(dto dto.CreateArticleRequest) (entity.Article, error) {
+func (u *articleUsecase) CreateArticle(req dto.CreateArticleRequest) (dto.ArticleWriteResponse, error) {
+	// Articles without a locale are written in the default one
+	if req.Locale == "" {
+		req.Locale = u.locales.Default
+	}
+	if !u.locales.supports(req.Locale) {
+		return dto.ArticleWriteResponse{}, ErrUnsupportedLocale
+	}
+
 	article := entity.Article{
-		Title:    dto.Title,
-		Content:  dto.Content,
-		Category: dto.Category,
-		Status:   dto.Status,
+		Title:    req.Title,
+		Content:  req.Content,
+		Category: req.Category,
+		Status:   req.Status,
+		Tags:     entity.NormalizeTags(req.Tags),
+		Locale:   req.Locale,
+		AuthorID: req.AuthorID,
+
+		TranslationGroupID: req.TranslationGroupID,
+		SourceRevision:     req.SourceRevision,
 	}
 
-	// Save article to the repository (database)
-	err := u.repo.Create(&article)
+	// Run the content policy before anything is saved
+	violations, err := u.checkPolicy(article)
 	if err != nil {
-		return entity.Article{}, err
+		return dto.ArticleWriteResponse{}, err
 	}
 
-	// Return the article entity directly
-	return article, nil
+	// Fingerprint the content and look for near-duplicates before saving
+	duplicates, err := u.duplicates.Check(&article)
+	if err != nil {
+		return dto.ArticleWriteResponse{}, err
+	}
+
+	// Save article to the repository (database) together with its events
+	err = u.saveWithEvents(&article, "", func(tx repository.ArticleRepository) error {
+		return tx.Create(&article)
+	}, EventArticleCreated)
+	if err != nil {
+		return dto.ArticleWriteResponse{}, err
+	}
+	u.notifySaved(article)
+
+	// Return the article entity along with any duplicate and policy warnings
+	return dto.ArticleWriteResponse{Article: article, Duplicates: duplicates, PolicyViolations: violations}, nil
 }
 
-func (u *articleUsecase) UpdateArticle(dto dto.UpdateArticleRequest) (entity.Article, error) {
+func (u *articleUsecase) UpdateArticle(req dto.UpdateArticleRequest) (dto.ArticleWriteResponse, error) {
 	// Find the existing article by ID
-	article, err := u.repo.FindByID(dto.ID)
+	article, err := findArticle(u.repo, req.ID)
 	if err != nil {
-		return entity.Article{}, errors.New("article not found")
+		return dto.ArticleWriteResponse{}, err
+	}
+
+	// Bump the revision when the text changes so translations can be flagged as out of date
+	if article.Title != req.Title || article.Content != req.Content {
+		article.Revision++
+	}
+
+	// Translations record which revision of the source they were brought up to date with, which cannot be a later one
+	if req.SourceRevision != nil && article.IsTranslation() {
+		source, err := u.repo.FindByID(article.TranslationGroupID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return dto.ArticleWriteResponse{}, ErrSourceArticleNotFound
+		}
+		if err != nil {
+			return dto.ArticleWriteResponse{}, err
+		}
+		if *req.SourceRevision > source.Revision {
+			return dto.ArticleWriteResponse{}, ErrInvalidSourceRevision
+		}
+		article.SourceRevision = req.SourceRevision
 	}
 
 	// Update the article fields with the new data
-	article.Title = dto.Title
-	article.Content = dto.Content
-	article.Category = dto.Category
-	article.Status = dto.Status
+	previousStatus := article.Status
+	article.Title = req.Title
+	article.Content = req.Content
+	article.Category = req.Category
+	article.Status = req.Status
+	article.Tags = entity.NormalizeTags(req.Tags)
 
-	// Save the updated article to the repository (database)
-	err = u.repo.Update(article) // Pass the pointer directly here
+	// Run the content policy, blocking violations stop the transition to Publish
+	violations, err := u.checkPolicy(*article)
 	if err != nil {
-		return entity.Article{}, err
+		return dto.ArticleWriteResponse{}, err
+	}
+
+	// Refresh the fingerprint and look for near-duplicates of the new content
+	duplicates, err := u.duplicates.Check(article)
+	if err != nil {
+		return dto.ArticleWriteResponse{}, err
 	}
 
-	// Return the updated article entity
-	return *article, nil // Return the dereferenced article (value)
+	// Save the updated article to the repository (database) together with its events
+	err = u.saveWithEvents(article, previousStatus, func(tx repository.ArticleRepository) error {
+		return tx.Update(article)
+	}, EventArticleUpdated)
+	if err != nil {
+		return dto.ArticleWriteResponse{}, err
+	}
+	u.notifySaved(*article)
+
+	// Return the updated article entity along with any duplicate and policy warnings
+	return dto.ArticleWriteResponse{Article: *article, Duplicates: duplicates, PolicyViolations: violations}, nil
 }
 
 func (u *articleUsecase) SoftDeleteArticle(dto dto.SoftDeleteArticleDTO) (entity.Article, error) {
-	article, err := u.repo.FindByID(dto.ID)
+	article, err := findArticle(u.repo, dto.ID)
 	if err != nil {
-		return entity.Article{}, errors.New("article not found")
+		return entity.Article{}, err
 	}
 
 	// Set the status to "Trash"
-	article.Status = "Trash"
+	previousStatus := article.Status
+	article.Status = entity.StatusTrash
 
 	// Save the updated article
-	err = u.repo.Update(article)
+	err = u.saveWithEvents(article, previousStatus, func(tx repository.ArticleRepository) error {
+		return tx.Update(article)
+	}, EventArticleTrashed)
 	if err != nil {
 		return entity.Article{}, err
 	}
+	u.notifySaved(*article)
 
 	// Return the updated article
 	return *article, nil
@@ -137,21 +272,27 @@ func (u *articleUsecase) SoftDeleteArticle(dto dto.SoftDeleteArticleDTO) (entity
 
 // DeleteArticle permanently removes an article if its status is Trash
 func (u *articleUsecase) DeleteArticle(dto dto.SoftDeleteArticleDTO) error {
-	article, err := u.repo.FindByID(dto.ID)
+	article, err := findArticle(u.repo, dto.ID)
 	if err != nil {
-		return errors.New("article not found")
+		return err
 	}
 
 	// Check if the article status is Trash (can be permanently deleted)
-	if article.Status != "Trash" {
-		return errors.New("only articles in trash can be permanently deleted")
+	if article.Status != entity.StatusTrash {
+		return ErrArticleNotInTrash
 	}
 
 	// Permanently delete the article from the repository
-	err = u.repo.Delete(dto.ID)
+	err = u.saveWithEvents(article, article.Status, func(tx repository.ArticleRepository) error {
+		return tx.Delete(dto.ID)
+	}, EventArticleDeleted)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrArticleNotFound
+	}
 	if err != nil {
 		return err
 	}
+	u.notifyRemoved(dto.ID)
 
 	return nil
 }
@@ -162,18 +303,47 @@ func (u *articleUsecase) SearchArticles(query string, limit, offset int) ([]dto.
 		return nil, err
 	}
 
-	var response []dto.ArticleResponse
-	for _, article := range articles {
-		response = append(response, dto.ArticleResponse{
-			ID:        article.ID,
-			Title:     article.Title,
-			Content:   article.Content,
-			Category:  article.Category,
-			Status:    article.Status,
-			CreatedAt: article.CreatedDate.Format("2006-01-02 15:04:05"),
-			UpdatedAt: article.UpdatedDate.Format("2006-01-02 15:04:05"),
-		})
+	return toArticleResponses(u.authors, articles), nil
+}
+
+// FindByIDs loads several articles with a single query, keyed by ID. Unknown IDs are left out.
+func (u *articleUsecase) FindByIDs(ids []uint) (map[uint]dto.ArticleResponse, error) {
+	articles, err := u.repo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
 	}
 
+	responses := make(map[uint]dto.ArticleResponse, len(articles))
+	for _, response := range toArticleResponses(u.authors, articles) {
+		responses[response.ID] = response
+	}
+	return responses, nil
+}
+
+// FindCategories lists every category with its number of articles
+func (u *articleUsecase) FindCategories() ([]dto.CategoryResponse, error) {
+	categories, err := u.repo.FindCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.CategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		response = append(response, dto.CategoryResponse{Name: category.Category, Articles: category.Articles})
+	}
 	return response, nil
 }
+
+// FindByCategories returns the newest articles of several categories with a single query, keyed by category
+func (u *articleUsecase) FindByCategories(categories []string, limit int) (map[string][]dto.ArticleResponse, error) {
+	articles, err := u.repo.FindByCategories(categories, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make(map[string][]dto.ArticleResponse, len(categories))
+	for _, response := range toArticleResponses(u.authors, articles) {
+		responses[response.Category] = append(responses[response.Category], response)
+	}
+	return responses, nil
+}
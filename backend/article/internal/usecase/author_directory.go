@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"log"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+)
+
+// AuthorDirectory looks up the name and avatar of article authors, keyed by user ID.
+// Authors missing from the result are shown with their ID only, a failed lookup may still return the authors it found.
+type AuthorDirectory interface {
+	FindAuthors(ids []uint) (map[uint]dto.AuthorResponse, error)
+}
+
+// fillAuthors completes the authors of a page of articles with a single directory lookup.
+// Articles are still served when the lookup fails, authors it could not find are left with the ID only.
+func fillAuthors(directory AuthorDirectory, responses []dto.ArticleResponse) {
+	if directory == nil {
+		return
+	}
+
+	seen := make(map[uint]bool)
+	var ids []uint
+	for _, response := range responses {
+		if response.Author != nil && !seen[response.Author.ID] {
+			seen[response.Author.ID] = true
+			ids = append(ids, response.Author.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	authors, err := directory.FindAuthors(ids)
+	if err != nil {
+		log.Println("Failed to look up article authors, showing IDs only:", err)
+	}
+	for i := range responses {
+		if responses[i].Author == nil {
+			continue
+		}
+		if author, ok := authors[responses[i].Author.ID]; ok {
+			responses[i].Author = &author
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"sort"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/article/internal/repository"
+	"github.com/yuhari7/backend_supervision/article/pkg/simhash"
+)
+
+// DuplicateConfig controls how near-duplicate content is handled
+type DuplicateConfig struct {
+	Threshold float64 // minimum similarity (0-1) for two articles to be considered duplicates
+	Reject    bool    // reject the write instead of only warning
+}
+
+// DuplicateDetector fingerprints article content and finds near-duplicate articles
+type DuplicateDetector interface {
+	Check(article *entity.Article) ([]dto.DuplicateMatch, error)
+	GetDuplicates(id uint) ([]dto.DuplicateMatch, error)
+	Backfill() error
+}
+
+type duplicateDetector struct {
+	repo   repository.ArticleRepository
+	config DuplicateConfig
+}
+
+// NewDuplicateDetector creates a new instance of DuplicateDetector
+func NewDuplicateDetector(r repository.ArticleRepository, config DuplicateConfig) DuplicateDetector {
+	if config.Threshold <= 0 || config.Threshold > 1 {
+		config.Threshold = 0.9
+	}
+	return &duplicateDetector{repo: r, config: config}
+}
+
+// Check sets the fingerprint of the article and returns the articles it duplicates.
+// When rejection is enabled and matches exist, ErrDuplicateContent is returned with the matches.
+func (d *duplicateDetector) Check(article *entity.Article) ([]dto.DuplicateMatch, error) {
+	fingerprint := int64(simhash.Fingerprint(article.Content))
+	article.Fingerprint = &fingerprint
+
+	matches, err := d.findMatches(article.ID, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.config.Reject && len(matches) > 0 {
+		return nil, ErrDuplicateContent.With("duplicates", matches)
+	}
+	return matches, nil
+}
+
+// GetDuplicates returns the articles that duplicate the given article
+func (d *duplicateDetector) GetDuplicates(id uint) ([]dto.DuplicateMatch, error) {
+	article, err := findArticle(d.repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint := int64(simhash.Fingerprint(article.Content))
+	if article.Fingerprint != nil {
+		fingerprint = *article.Fingerprint
+	}
+
+	return d.findMatches(article.ID, fingerprint)
+}
+
+// Backfill computes the fingerprint of articles created before fingerprints existed
+func (d *duplicateDetector) Backfill() error {
+	for {
+		articles, err := d.repo.FindWithoutFingerprint(100)
+		if err != nil {
+			return err
+		}
+		if len(articles) == 0 {
+			return nil
+		}
+
+		for _, article := range articles {
+			if err := d.repo.UpdateFingerprint(article.ID, int64(simhash.Fingerprint(article.Content))); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (d *duplicateDetector) findMatches(articleID uint, fingerprint int64) ([]dto.DuplicateMatch, error) {
+	candidates, err := d.repo.FindFingerprints()
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []dto.DuplicateMatch{}
+	for _, candidate := range candidates {
+		if candidate.ID == articleID || candidate.Fingerprint == nil {
+			continue
+		}
+
+		similarity := simhash.Similarity(uint64(fingerprint), uint64(*candidate.Fingerprint))
+		if similarity >= d.config.Threshold {
+			matches = append(matches, dto.DuplicateMatch{
+				ID:         candidate.ID,
+				Title:      candidate.Title,
+				Status:     candidate.Status,
+				Similarity: similarity,
+			})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Similarity > matches[j].Similarity
+	})
+	return matches, nil
+}
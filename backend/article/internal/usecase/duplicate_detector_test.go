@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/article/internal/policy"
+	"github.com/yuhari7/backend_supervision/article/pkg/simhash"
+	"github.com/yuhari7/backend_supervision/shared/apperror"
+)
+
+const (
+	parkContent  = "Pemerintah kota meresmikan taman baru di pusat kota pada hari Minggu. Taman seluas dua hektare itu dilengkapi jalur sepeda, area bermain anak, dan kolam retensi."
+	chiliContent = "Harga cabai rawit di pasar tradisional naik tajam menjelang akhir pekan karena pasokan dari petani berkurang setelah hujan deras."
+)
+
+// newFingerprintedArticle returns an article whose fingerprint was computed like on save
+func newFingerprintedArticle(id uint, title, content string) entity.Article {
+	fingerprint := int64(simhash.Fingerprint(content))
+	return entity.Article{ID: id, Title: title, Content: content, Category: "News", Status: entity.StatusPublish, Locale: "id", Fingerprint: &fingerprint}
+}
+
+func newDuplicateArticles(repo *fakeArticleRepository, reject bool) ArticleUsecase {
+	duplicates := NewDuplicateDetector(repo, DuplicateConfig{Threshold: 0.9, Reject: reject})
+	return NewArticleUsecase(repo, duplicates, policy.NewEngine(), LocaleConfig{Default: "id", Supported: []string{"id"}}, nil)
+}
+
+func TestDuplicatesOnlyWarnByDefault(t *testing.T) {
+	repo := newFakeArticleRepository(newFingerprintedArticle(1, "Taman baru", parkContent), newFingerprintedArticle(2, "Harga cabai", chiliContent))
+	articles := newDuplicateArticles(repo, false)
+
+	created, err := articles.CreateArticle(dto.CreateArticleRequest{Title: "Taman kota", Content: parkContent, Category: "News", Status: entity.StatusDraft})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []uint
+	for _, match := range created.Duplicates {
+		ids = append(ids, match.ID)
+	}
+	if !reflect.DeepEqual(ids, []uint{1}) {
+		t.Errorf("got duplicates %v, want article 1", ids)
+	}
+	if _, err := repo.FindByID(created.Article.ID); err != nil {
+		t.Errorf("got error %v, want the duplicate saved anyway", err)
+	}
+}
+
+func TestDuplicatesAreRejectedWhenEnabled(t *testing.T) {
+	repo := newFakeArticleRepository(newFingerprintedArticle(1, "Taman baru", parkContent), newFingerprintedArticle(2, "Harga cabai", chiliContent))
+	articles := newDuplicateArticles(repo, true)
+
+	_, err := articles.CreateArticle(dto.CreateArticleRequest{Title: "Taman kota", Content: parkContent, Category: "News", Status: entity.StatusDraft})
+	if !errors.Is(err, ErrDuplicateContent) {
+		t.Fatalf("got error %v, want %v", err, ErrDuplicateContent)
+	}
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		t.Fatalf("got error %T, want an *apperror.Error", err)
+	}
+	if matches, _ := appErr.Extensions["duplicates"].([]dto.DuplicateMatch); len(matches) != 1 || matches[0].ID != 1 {
+		t.Errorf("got duplicates %v, want article 1 in the error", appErr.Extensions["duplicates"])
+	}
+	if len(repo.articles) != 2 {
+		t.Errorf("got %d articles, want the duplicate not saved", len(repo.articles))
+	}
+
+	// Updating an article to the content of another is rejected too
+	update := dto.UpdateArticleRequest{ID: 2, Title: "Harga cabai", Content: parkContent, Category: "News", Status: entity.StatusPublish}
+	if _, err := articles.UpdateArticle(update); !errors.Is(err, ErrDuplicateContent) {
+		t.Errorf("got error %v, want %v", err, ErrDuplicateContent)
+	}
+	if repo.articles[2].Content != chiliContent {
+		t.Errorf("got content %q, want the rejected update not saved", repo.articles[2].Content)
+	}
+
+	// An article is not a duplicate of itself
+	update = dto.UpdateArticleRequest{ID: 1, Title: "Taman baru diresmikan", Content: parkContent, Category: "News", Status: entity.StatusPublish}
+	updated, err := articles.UpdateArticle(update)
+	if err != nil || len(updated.Duplicates) != 0 {
+		t.Errorf("got duplicates %+v and error %v, want none when saving an article unchanged", updated.Duplicates, err)
+	}
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import "github.com/yuhari7/backend_supervision/shared/apperror"
+
+// Domain errors returned by the article usecases
+var (
+	ErrArticleNotFound        = apperror.NotFound("article_not_found", "article not found")
+	ErrSourceArticleNotFound  = apperror.NotFound("source_article_not_found", "source article not found")
+	ErrArticleNotInTrash      = apperror.Conflict("article_not_in_trash", "only articles in trash can be permanently deleted")
+	ErrDuplicateContent       = apperror.Conflict("duplicate_content", "article content duplicates an existing article")
+	ErrContentPolicyViolation = apperror.Unprocessable("content_policy_violation", "article violates the content policy and cannot be published")
+	ErrInvalidPeriod          = apperror.Validation("invalid_period", "invalid period, use a number of days such as 7d", nil)
+	ErrUnsupportedLocale      = apperror.Validation("unsupported_locale", "unsupported locale", nil)
+	ErrTranslationExists      = apperror.Conflict("translation_exists", "translation for this locale already exists")
+	ErrInvalidSourceRevision  = apperror.Validation("invalid_source_revision", "source revision is ahead of the source article", nil)
+)
+
+// Domain errors returned by the webhook usecases
+var (
+	ErrWebhookNotFound         = apperror.NotFound("webhook_not_found", "webhook not found")
+	ErrWebhookDeliveryNotFound = apperror.NotFound("webhook_delivery_not_found", "webhook delivery not found")
+	ErrInvalidWebhookURL       = apperror.Validation("invalid_webhook_url", "webhook URL must be an absolute http or https URL", nil)
+	ErrForbiddenWebhookURL     = apperror.Validation("forbidden_webhook_url", "webhook URL must resolve to a public address", nil)
+)
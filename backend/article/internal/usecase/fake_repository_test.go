@@ -0,0 +1,449 @@
+package usecase
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/article/internal/repository"
+	"github.com/yuhari7/backend_supervision/shared/outbox"
+)
+
+// fakeArticleRepository is an in-memory ArticleRepository following the same not found contract as the real one.
+// When err is set every call fails with it, standing in for a broken database.
+type fakeArticleRepository struct {
+	articles map[uint]entity.Article
+	nextID   uint
+	err      error
+	events   []outbox.Event
+
+	// eventsErr only fails writes to the outbox, to check that the change is rolled back
+	eventsErr error
+}
+
+func newFakeArticleRepository(articles ...entity.Article) *fakeArticleRepository {
+	r := &fakeArticleRepository{articles: map[uint]entity.Article{}, nextID: 1}
+	for _, article := range articles {
+		r.articles[article.ID] = article
+		if article.ID >= r.nextID {
+			r.nextID = article.ID + 1
+		}
+	}
+	return r
+}
+
+func (r *fakeArticleRepository) Create(article *entity.Article) error {
+	if r.err != nil {
+		return r.err
+	}
+	article.ID = r.nextID
+	r.nextID++
+	if article.TranslationGroupID == 0 {
+		article.TranslationGroupID = article.ID
+	}
+	r.articles[article.ID] = *article
+	return nil
+}
+
+func (r *fakeArticleRepository) FindAll() ([]entity.Article, error) {
+	return r.filter(func(entity.Article) bool { return true })
+}
+
+func (r *fakeArticleRepository) FindByID(id uint) (*entity.Article, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	article, ok := r.articles[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &article, nil
+}
+
+func (r *fakeArticleRepository) Update(article *entity.Article) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.articles[article.ID] = *article
+	return nil
+}
+
+func (r *fakeArticleRepository) Delete(id uint) error {
+	if r.err != nil {
+		return r.err
+	}
+	if _, ok := r.articles[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.articles, id)
+	return nil
+}
+
+func (r *fakeArticleRepository) SoftDelete(id uint) error {
+	if r.err != nil {
+		return r.err
+	}
+	article, ok := r.articles[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	now := time.Now()
+	article.DeletedAt = &now
+	r.articles[id] = article
+	return nil
+}
+
+func (r *fakeArticleRepository) FindWithPagination(locale string, limit, offset int, articles *[]entity.Article) error {
+	found, err := r.filter(func(a entity.Article) bool { return locale == "" || a.Locale == locale })
+	if err != nil {
+		return err
+	}
+	if offset > len(found) {
+		offset = len(found)
+	}
+	found = found[offset:]
+	if limit < len(found) {
+		found = found[:limit]
+	}
+	*articles = found
+	return nil
+}
+
+func (r *fakeArticleRepository) SearchArticles(query string, limit, offset int) ([]entity.Article, error) {
+	return r.filter(func(a entity.Article) bool { return strings.Contains(a.Title+a.Content, query) })
+}
+
+func (r *fakeArticleRepository) FindByStatus(status string) ([]entity.Article, error) {
+	return r.filter(func(a entity.Article) bool { return a.Status == status })
+}
+
+func (r *fakeArticleRepository) FindFingerprints() ([]entity.Article, error) {
+	return r.filter(func(a entity.Article) bool { return a.Fingerprint != nil })
+}
+
+func (r *fakeArticleRepository) FindWithoutFingerprint(limit int) ([]entity.Article, error) {
+	return r.filter(func(a entity.Article) bool { return a.Fingerprint == nil })
+}
+
+func (r *fakeArticleRepository) UpdateFingerprint(id uint, fingerprint int64) error {
+	if r.err != nil {
+		return r.err
+	}
+	article, ok := r.articles[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	article.Fingerprint = &fingerprint
+	r.articles[id] = article
+	return nil
+}
+
+func (r *fakeArticleRepository) FindTranslation(groupID uint, locale string) (*entity.Article, error) {
+	found, err := r.filter(func(a entity.Article) bool { return a.TranslationGroupID == groupID && a.Locale == locale })
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return &found[0], nil
+}
+
+func (r *fakeArticleRepository) FindTranslations(groupID uint) ([]entity.Article, error) {
+	return r.filter(func(a entity.Article) bool { return a.TranslationGroupID == groupID })
+}
+
+func (r *fakeArticleRepository) FindByIDs(ids []uint) ([]entity.Article, error) {
+	wanted := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	return r.filter(func(a entity.Article) bool { return wanted[a.ID] })
+}
+
+func (r *fakeArticleRepository) FindByGroups(groupIDs []uint) ([]entity.Article, error) {
+	wanted := make(map[uint]bool, len(groupIDs))
+	for _, id := range groupIDs {
+		wanted[id] = true
+	}
+	return r.filter(func(a entity.Article) bool { return wanted[a.TranslationGroupID] })
+}
+
+func (r *fakeArticleRepository) FindCategories() ([]entity.CategoryCount, error) {
+	articles, err := r.filter(func(a entity.Article) bool { return a.DeletedAt == nil })
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int64{}
+	for _, article := range articles {
+		counts[article.Category]++
+	}
+	categories := make([]entity.CategoryCount, 0, len(counts))
+	for category, count := range counts {
+		categories = append(categories, entity.CategoryCount{Category: category, Articles: count})
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Category < categories[j].Category })
+	return categories, nil
+}
+
+func (r *fakeArticleRepository) FindByCategories(categories []string, limitPerCategory int) ([]entity.Article, error) {
+	wanted := make(map[string]int, len(categories))
+	for _, category := range categories {
+		wanted[category] = limitPerCategory
+	}
+	return r.filter(func(a entity.Article) bool {
+		if a.DeletedAt != nil || wanted[a.Category] == 0 {
+			return false
+		}
+		wanted[a.Category]--
+		return true
+	})
+}
+
+// filter returns the matching articles ordered by ID
+func (r *fakeArticleRepository) filter(match func(entity.Article) bool) ([]entity.Article, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	var found []entity.Article
+	for id := uint(1); id < r.nextID; id++ {
+		if article, ok := r.articles[id]; ok && match(article) {
+			found = append(found, article)
+		}
+	}
+	return found, nil
+}
+
+// fakeViewRepository is an in-memory ArticleViewRepository returning the given daily views and totals.
+// Flushed batches are kept in batches, when err is set IncrementViews fails with it.
+type fakeViewRepository struct {
+	mu      sync.Mutex
+	daily   []entity.ArticleViewDaily
+	totals  []entity.ArticleViewTotal
+	batches [][]entity.ArticleViewDaily
+	err     error
+}
+
+func (r *fakeViewRepository) IncrementViews(views []entity.ArticleViewDaily) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.batches = append(r.batches, views)
+	return nil
+}
+
+func (r *fakeViewRepository) FindDailyViews(articleID uint, from time.Time) ([]entity.ArticleViewDaily, error) {
+	var views []entity.ArticleViewDaily
+	for _, view := range r.daily {
+		if view.ArticleID == articleID && !view.ViewDate.Before(from) {
+			views = append(views, view)
+		}
+	}
+	return views, nil
+}
+
+func (r *fakeViewRepository) FindTopByCategory(from time.Time, limitPerCategory int) ([]entity.ArticleViewTotal, error) {
+	return r.totals, nil
+}
+
+// views returns the flushed view counts per article
+func (r *fakeViewRepository) views() map[uint]int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[uint]int64)
+	for _, batch := range r.batches {
+		for _, view := range batch {
+			counts[view.ArticleID] += view.Views
+		}
+	}
+	return counts
+}
+
+// Transaction restores the articles and events when fn fails, like a rollback
+func (r *fakeArticleRepository) Transaction(fn func(tx repository.ArticleRepository) error) error {
+	articles := make(map[uint]entity.Article, len(r.articles))
+	for id, article := range r.articles {
+		articles[id] = article
+	}
+	events := r.events
+
+	if err := fn(r); err != nil {
+		r.articles, r.events = articles, events
+		return err
+	}
+	return nil
+}
+
+func (r *fakeArticleRepository) AddEvents(events ...outbox.Event) error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.eventsErr != nil {
+		return r.eventsErr
+	}
+	r.events = append(r.events, events...)
+	return nil
+}
+
+// eventTypes returns the types of the events written so far
+func (r *fakeArticleRepository) eventTypes() []string {
+	var types []string
+	for _, event := range r.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+// fakeWebhookRepository is an in-memory WebhookRepository with the same claim semantics as the real one
+type fakeWebhookRepository struct {
+	subscriptions map[uint]*entity.WebhookSubscription
+	deliveries    []*entity.WebhookDelivery
+}
+
+func newFakeWebhookRepository(subscriptions ...entity.WebhookSubscription) *fakeWebhookRepository {
+	r := &fakeWebhookRepository{subscriptions: map[uint]*entity.WebhookSubscription{}}
+	for i := range subscriptions {
+		r.subscriptions[subscriptions[i].ID] = &subscriptions[i]
+	}
+	return r
+}
+
+func (r *fakeWebhookRepository) Create(subscription *entity.WebhookSubscription) error {
+	subscription.ID = uint(len(r.subscriptions) + 1)
+	stored := *subscription
+	r.subscriptions[subscription.ID] = &stored
+	return nil
+}
+
+func (r *fakeWebhookRepository) FindAll() ([]entity.WebhookSubscription, error) {
+	var subscriptions []entity.WebhookSubscription
+	for id := uint(1); id <= uint(len(r.subscriptions)); id++ {
+		if subscription, ok := r.subscriptions[id]; ok {
+			subscriptions = append(subscriptions, *subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (r *fakeWebhookRepository) FindByID(id uint) (*entity.WebhookSubscription, error) {
+	subscription, ok := r.subscriptions[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *subscription
+	return &copied, nil
+}
+
+func (r *fakeWebhookRepository) FindActive() ([]entity.WebhookSubscription, error) {
+	all, _ := r.FindAll()
+	var active []entity.WebhookSubscription
+	for _, subscription := range all {
+		if subscription.Active {
+			active = append(active, subscription)
+		}
+	}
+	return active, nil
+}
+
+func (r *fakeWebhookRepository) Update(subscription *entity.WebhookSubscription) error {
+	stored := *subscription
+	r.subscriptions[subscription.ID] = &stored
+	return nil
+}
+
+func (r *fakeWebhookRepository) Delete(id uint) error {
+	if _, ok := r.subscriptions[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.subscriptions, id)
+	return nil
+}
+
+func (r *fakeWebhookRepository) RecordSuccess(subscriptionID uint) error {
+	r.subscriptions[subscriptionID].ConsecutiveFailures = 0
+	return nil
+}
+
+func (r *fakeWebhookRepository) RecordFailure(subscriptionID uint, disableAfter int) (bool, error) {
+	subscription := r.subscriptions[subscriptionID]
+	subscription.ConsecutiveFailures++
+	if subscription.Active && subscription.ConsecutiveFailures >= disableAfter {
+		now := time.Now()
+		subscription.Active = false
+		subscription.DisabledAt = &now
+	}
+	return !subscription.Active, nil
+}
+
+func (r *fakeWebhookRepository) CreateDeliveries(deliveries []entity.WebhookDelivery) error {
+	for _, delivery := range deliveries {
+		if _, err := r.findDelivery(delivery.SubscriptionID, delivery.EventID); err == nil {
+			continue
+		}
+		delivery.ID = uint(len(r.deliveries) + 1)
+		r.deliveries = append(r.deliveries, &delivery)
+	}
+	return nil
+}
+
+func (r *fakeWebhookRepository) findDelivery(subscriptionID uint, eventID string) (*entity.WebhookDelivery, error) {
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID == subscriptionID && delivery.EventID == eventID {
+			return delivery, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *fakeWebhookRepository) ClaimDeliveries(limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	var claimed []entity.WebhookDelivery
+	now := time.Now().UTC()
+	for _, delivery := range r.deliveries {
+		subscription, ok := r.subscriptions[delivery.SubscriptionID]
+		if len(claimed) == limit || !ok || !subscription.Active || delivery.Status != entity.DeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		delivery.NextAttemptAt = now.Add(lease)
+		claimed = append(claimed, *delivery)
+	}
+	return claimed, nil
+}
+
+func (r *fakeWebhookRepository) FindDeliveries(subscriptionID uint, limit, offset int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	for i := len(r.deliveries) - 1; i >= 0; i-- {
+		if r.deliveries[i].SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, *r.deliveries[i])
+		}
+	}
+	if offset >= len(deliveries) {
+		return nil, nil
+	}
+	return deliveries[offset:min(offset+limit, len(deliveries))], nil
+}
+
+func (r *fakeWebhookRepository) FindDelivery(subscriptionID, id uint) (*entity.WebhookDelivery, error) {
+	for _, delivery := range r.deliveries {
+		if delivery.ID == id && delivery.SubscriptionID == subscriptionID {
+			copied := *delivery
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *fakeWebhookRepository) UpdateDelivery(delivery *entity.WebhookDelivery) error {
+	// Like a TEXT column in Postgres
+	if !utf8.ValidString(delivery.ResponseBody) || strings.ContainsRune(delivery.ResponseBody, 0) {
+		return errors.New("invalid byte sequence for encoding UTF8")
+	}
+	stored := *delivery
+	r.deliveries[delivery.ID-1] = &stored
+	return nil
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/article/internal/policy"
+)
+
+const missingID uint = 404
+
+var errDatabase = errors.New("connection refused")
+
+// usecases bundles every usecase wired to the same fake repository
+type usecases struct {
+	articles     ArticleUsecase
+	stats        ArticleStatsUsecase
+	related      RelatedArticleUsecase
+	duplicates   DuplicateDetector
+	translations TranslationUsecase
+}
+
+func newUsecases(repo *fakeArticleRepository) usecases {
+	duplicates := NewDuplicateDetector(repo, DuplicateConfig{Threshold: 0.9})
+	locales := LocaleConfig{Default: "id", Supported: []string{"id", "en"}}
+	articles := NewArticleUsecase(repo, duplicates, policy.NewEngine(), locales, nil)
+	return usecases{
+		articles:     articles,
+		stats:        NewArticleStatsUsecase(repo, &fakeViewRepository{}),
+		related:      NewRelatedArticleUsecase(repo, time.Minute),
+		duplicates:   duplicates,
+		translations: NewTranslationUsecase(repo, articles, nil, locales),
+	}
+}
+
+func TestMissingArticle(t *testing.T) {
+	update := dto.UpdateArticleRequest{ID: missingID, Title: "Updated title", Content: "Updated content", Category: "News"}
+	translation := dto.CreateTranslationRequest{Locale: "en", Title: "Translated title", Content: "Translated content"}
+
+	tests := []struct {
+		name string
+		call func(u usecases) error
+	}{
+		{"FindByID", func(u usecases) error {
+			_, err := u.articles.FindByID(missingID)
+			return err
+		}},
+		{"UpdateArticle", func(u usecases) error {
+			_, err := u.articles.UpdateArticle(update)
+			return err
+		}},
+		{"SoftDeleteArticle", func(u usecases) error {
+			_, err := u.articles.SoftDeleteArticle(dto.SoftDeleteArticleDTO{ID: missingID})
+			return err
+		}},
+		{"DeleteArticle", func(u usecases) error {
+			return u.articles.DeleteArticle(dto.SoftDeleteArticleDTO{ID: missingID})
+		}},
+		{"GetArticleStats", func(u usecases) error {
+			_, err := u.stats.GetArticleStats(missingID, 7)
+			return err
+		}},
+		{"GetRelated", func(u usecases) error {
+			_, err := u.related.GetRelated(missingID, 5)
+			return err
+		}},
+		{"GetDuplicates", func(u usecases) error {
+			_, err := u.duplicates.GetDuplicates(missingID)
+			return err
+		}},
+		{"FindLocalized", func(u usecases) error {
+			_, err := u.translations.FindLocalized(missingID, "en")
+			return err
+		}},
+		{"CreateTranslation", func(u usecases) error {
+			_, err := u.translations.CreateTranslation(missingID, translation)
+			return err
+		}},
+		{"GetTranslationStatus", func(u usecases) error {
+			_, err := u.translations.GetTranslationStatus(missingID)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(newUsecases(newFakeArticleRepository()))
+			if !errors.Is(err, ErrArticleNotFound) {
+				t.Fatalf("got error %v, want %v", err, ErrArticleNotFound)
+			}
+		})
+
+		t.Run(tt.name+"/database error", func(t *testing.T) {
+			repo := newFakeArticleRepository()
+			repo.err = errDatabase
+
+			err := tt.call(newUsecases(repo))
+			if !errors.Is(err, errDatabase) {
+				t.Fatalf("got error %v, want %v", err, errDatabase)
+			}
+		})
+	}
+}
+
+func TestMissingSourceArticle(t *testing.T) {
+	// A translation whose source article has been permanently deleted
+	orphan := entity.Article{ID: 2, Title: "Orphaned translation", Locale: "en", TranslationGroupID: 1, Revision: 1}
+
+	tests := []struct {
+		name string
+		call func(u usecases) error
+	}{
+		{"CreateTranslation", func(u usecases) error {
+			_, err := u.translations.CreateTranslation(orphan.ID, dto.CreateTranslationRequest{Locale: "id", Title: "Judul", Content: "Isi"})
+			return err
+		}},
+		{"GetTranslationStatus", func(u usecases) error {
+			_, err := u.translations.GetTranslationStatus(orphan.ID)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(newUsecases(newFakeArticleRepository(orphan)))
+			if !errors.Is(err, ErrSourceArticleNotFound) {
+				t.Fatalf("got error %v, want %v", err, ErrSourceArticleNotFound)
+			}
+		})
+	}
+}
+
+func TestMissingTranslationFallsBack(t *testing.T) {
+	source := entity.Article{ID: 1, Title: "Artikel sumber", Locale: "id", TranslationGroupID: 1, Revision: 1}
+	english := entity.Article{ID: 2, Title: "Source article", Locale: "en", TranslationGroupID: 1, Revision: 1}
+
+	tests := []struct {
+		name     string
+		articles []entity.Article
+		id       uint
+		locale   string
+		wantID   uint
+	}{
+		{"requested locale", []entity.Article{source, english}, source.ID, "en", english.ID},
+		{"default locale", []entity.Article{source, english}, english.ID, "fr", source.ID},
+		{"article itself", []entity.Article{english}, english.ID, "fr", english.ID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newUsecases(newFakeArticleRepository(tt.articles...))
+
+			article, err := u.translations.FindLocalized(tt.id, tt.locale)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if article.ID != tt.wantID {
+				t.Fatalf("got article %d, want %d", article.ID, tt.wantID)
+			}
+		})
+	}
+}
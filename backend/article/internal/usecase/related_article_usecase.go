@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/article/internal/repository"
+	"github.com/yuhari7/backend_supervision/article/pkg/tfidf"
+)
+
+// Weights of each similarity signal in the final related score
+const (
+	contentWeight  = 0.6
+	categoryWeight = 0.25
+	tagWeight      = 0.15
+)
+
+// RelatedArticleUsecase ranks published articles by similarity to a given article.
+// It observes article changes so the TF-IDF model is refreshed incrementally.
+type RelatedArticleUsecase interface {
+	ArticleObserver
+	Load() error
+	GetRelated(id uint, limit int) ([]dto.RelatedArticleResponse, error)
+	GetRelatedBatch(ids []uint, limit int) (map[uint][]dto.RelatedArticleResponse, error)
+}
+
+type relatedMeta struct {
+	title    string
+	category string
+	tags     []string
+}
+
+type relatedCacheKey struct {
+	id    uint
+	limit int
+}
+
+type relatedCacheEntry struct {
+	version   uint64
+	expiresAt time.Time
+	items     []dto.RelatedArticleResponse
+}
+
+type relatedArticleUsecase struct {
+	repo     repository.ArticleRepository
+	index    *tfidf.Index
+	cacheTTL time.Duration
+
+	mu      sync.RWMutex
+	meta    map[uint]relatedMeta
+	version uint64
+	cache   map[relatedCacheKey]relatedCacheEntry
+}
+
+// NewRelatedArticleUsecase creates a new instance of RelatedArticleUsecase
+func NewRelatedArticleUsecase(r repository.ArticleRepository, cacheTTL time.Duration) RelatedArticleUsecase {
+	return &relatedArticleUsecase{
+		repo:     r,
+		index:    tfidf.NewIndex(),
+		cacheTTL: cacheTTL,
+		meta:     make(map[uint]relatedMeta),
+		cache:    make(map[relatedCacheKey]relatedCacheEntry),
+	}
+}
+
+// Load builds the model from every published article
+func (u *relatedArticleUsecase) Load() error {
+	articles, err := u.repo.FindByStatus(entity.StatusPublish)
+	if err != nil {
+		return err
+	}
+
+	for _, article := range articles {
+		u.ArticleSaved(article)
+	}
+	return nil
+}
+
+// ArticleSaved indexes published articles and drops every other status from the model
+func (u *relatedArticleUsecase) ArticleSaved(article entity.Article) {
+	if article.Status != entity.StatusPublish {
+		u.ArticleRemoved(article.ID)
+		return
+	}
+
+	u.index.Upsert(article.ID, relatedText(article))
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.meta[article.ID] = relatedMeta{title: article.Title, category: article.Category, tags: article.Tags}
+	u.invalidate()
+}
+
+// ArticleRemoved drops an article from the model
+func (u *relatedArticleUsecase) ArticleRemoved(id uint) {
+	u.index.Remove(id)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if _, ok := u.meta[id]; ok {
+		delete(u.meta, id)
+		u.invalidate()
+	}
+}
+
+// GetRelated returns the published articles most similar to the given article
+func (u *relatedArticleUsecase) GetRelated(id uint, limit int) ([]dto.RelatedArticleResponse, error) {
+	related, err := u.GetRelatedBatch([]uint{id}, limit)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := related[id]
+	if !ok {
+		return nil, ErrArticleNotFound
+	}
+	return items, nil
+}
+
+// GetRelatedBatch returns the related articles of several articles at once,
+// articles missing from the cache are loaded with a single query and unknown IDs are left out
+func (u *relatedArticleUsecase) GetRelatedBatch(ids []uint, limit int) (map[uint][]dto.RelatedArticleResponse, error) {
+	if limit <= 0 || limit > 20 {
+		limit = 5
+	}
+
+	related := make(map[uint][]dto.RelatedArticleResponse, len(ids))
+	var uncached []uint
+
+	u.mu.RLock()
+	version := u.version
+	for _, id := range ids {
+		entry, ok := u.cache[relatedCacheKey{id: id, limit: limit}]
+		if ok && entry.version == version && time.Now().Before(entry.expiresAt) {
+			related[id] = entry.items
+		} else {
+			uncached = append(uncached, id)
+		}
+	}
+	u.mu.RUnlock()
+	if len(uncached) == 0 {
+		return related, nil
+	}
+
+	articles, err := u.repo.FindByIDs(uncached)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, article := range articles {
+		items := u.rank(article, limit)
+		related[article.ID] = items
+
+		u.mu.Lock()
+		u.cache[relatedCacheKey{id: article.ID, limit: limit}] = relatedCacheEntry{version: version, expiresAt: time.Now().Add(u.cacheTTL), items: items}
+		u.mu.Unlock()
+	}
+
+	return related, nil
+}
+
+// rank scores every published article against the given article and keeps the best ones
+func (u *relatedArticleUsecase) rank(article entity.Article, limit int) []dto.RelatedArticleResponse {
+	contentScores := u.index.Similar(relatedText(article))
+
+	u.mu.RLock()
+	items := make([]dto.RelatedArticleResponse, 0, len(u.meta))
+	for candidateID, meta := range u.meta {
+		if candidateID == article.ID {
+			continue
+		}
+
+		score := contentWeight * contentScores[candidateID]
+		if strings.EqualFold(meta.category, article.Category) {
+			score += categoryWeight
+		}
+		score += tagWeight * jaccard(meta.tags, article.Tags)
+		if score <= 0 {
+			continue
+		}
+
+		items = append(items, dto.RelatedArticleResponse{
+			ID:       candidateID,
+			Title:    meta.title,
+			Category: meta.category,
+			Tags:     meta.tags,
+			Score:    score,
+		})
+	}
+	u.mu.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Score == items[j].Score {
+			return items[i].ID > items[j].ID
+		}
+		return items[i].Score > items[j].Score
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// invalidate bumps the model version so cached results are recomputed, must be called with mu held
+func (u *relatedArticleUsecase) invalidate() {
+	u.version++
+	u.cache = make(map[relatedCacheKey]relatedCacheEntry)
+}
+
+// relatedText weights the title twice as much as the content
+func relatedText(article entity.Article) string {
+	return article.Title + " " + article.Title + " " + article.Content
+}
+
+func jaccard(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	set := make(map[string]bool, len(a))
+	for _, tag := range a {
+		set[tag] = true
+	}
+
+	intersection := 0
+	union := len(set)
+	for _, tag := range b {
+		if set[tag] {
+			intersection++
+		} else {
+			union++
+		}
+	}
+	return float64(intersection) / float64(union)
+}
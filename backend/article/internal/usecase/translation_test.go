@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+)
+
+func TestCreateArticleLocale(t *testing.T) {
+	repo := newFakeArticleRepository()
+	articles := newUsecases(repo).articles
+
+	tests := []struct {
+		locale string
+		want   string
+		err    error
+	}{
+		{"", "id", nil},
+		{"en", "en", nil},
+		{"fr", "", ErrUnsupportedLocale},
+	}
+	for _, tt := range tests {
+		created, err := articles.CreateArticle(dto.CreateArticleRequest{Title: "Article in " + tt.locale, Content: "Written in " + tt.locale, Category: "News", Status: entity.StatusDraft, Locale: tt.locale})
+		if !errors.Is(err, tt.err) {
+			t.Errorf("got error %v for locale %q, want %v", err, tt.locale, tt.err)
+			continue
+		}
+		if err == nil && created.Article.Locale != tt.want {
+			t.Errorf("got locale %q for locale %q, want %q", created.Article.Locale, tt.locale, tt.want)
+		}
+	}
+	if len(repo.articles) != 2 {
+		t.Errorf("got %d articles, want the one in an unsupported locale refused", len(repo.articles))
+	}
+}
+
+func TestSourceRevisionIsBounded(t *testing.T) {
+	repo := newFakeArticleRepository(entity.Article{ID: 1, Title: "Sumber", Content: "Isi artikel sumber", Category: "News", Status: entity.StatusDraft, Locale: "id", TranslationGroupID: 1, Revision: 3})
+	u := newUsecases(repo)
+
+	created, err := u.translations.CreateTranslation(1, dto.CreateTranslationRequest{Locale: "en", Title: "Source", Content: "Content of the source article"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	translation := created.Article
+	if *translation.SourceRevision != 3 {
+		t.Errorf("got source revision %d, want the revision of the source", *translation.SourceRevision)
+	}
+
+	update := func(revision int) error {
+		_, err := u.articles.UpdateArticle(dto.UpdateArticleRequest{ID: translation.ID, Title: translation.Title, Content: translation.Content, Category: "News", Status: entity.StatusDraft, SourceRevision: &revision})
+		return err
+	}
+	if err := update(4); !errors.Is(err, ErrInvalidSourceRevision) {
+		t.Errorf("got error %v for a revision ahead of the source, want ErrInvalidSourceRevision", err)
+	}
+	if err := update(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := *repo.articles[translation.ID].SourceRevision; got != 2 {
+		t.Errorf("got source revision %d, want 2", got)
+	}
+
+	// Without its source there is nothing to bound the revision by
+	delete(repo.articles, 1)
+	if err := update(1); !errors.Is(err, ErrSourceArticleNotFound) {
+		t.Errorf("got error %v, want ErrSourceArticleNotFound", err)
+	}
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/article/internal/repository"
+)
+
+// Translation states reported by GetTranslationStatus
+const (
+	TranslationMissing  = "missing"
+	TranslationOutdated = "outdated"
+	TranslationUpToDate = "up_to_date"
+)
+
+// LocaleConfig lists the locales articles can be written in
+type LocaleConfig struct {
+	Default   string
+	Supported []string
+}
+
+func (c LocaleConfig) supports(locale string) bool {
+	for _, supported := range c.Supported {
+		if supported == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// TranslationUsecase defines the methods for working with article translations
+type TranslationUsecase interface {
+	FindLocalized(id uint, locale string) (dto.ArticleResponse, error)
+	CreateTranslation(id uint, req dto.CreateTranslationRequest) (dto.ArticleWriteResponse, error)
+	GetTranslationStatus(id uint) (dto.TranslationStatusResponse, error)
+	FindGroups(groupIDs []uint) (map[uint][]dto.ArticleResponse, error)
+}
+
+type translationUsecase struct {
+	repo           repository.ArticleRepository
+	articleUsecase ArticleUsecase
+	authors        AuthorDirectory
+	locales        LocaleConfig
+}
+
+// NewTranslationUsecase creates a new instance of TranslationUsecase, authors may be nil to only show author IDs
+func NewTranslationUsecase(r repository.ArticleRepository, articleUsecase ArticleUsecase, authors AuthorDirectory, locales LocaleConfig) TranslationUsecase {
+	return &translationUsecase{repo: r, articleUsecase: articleUsecase, authors: authors, locales: locales}
+}
+
+// FindLocalized returns the article in the requested locale,
+// falling back to the default locale and then to the article itself
+func (u *translationUsecase) FindLocalized(id uint, locale string) (dto.ArticleResponse, error) {
+	article, err := findArticle(u.repo, id)
+	if err != nil {
+		return dto.ArticleResponse{}, err
+	}
+	if article.Locale == locale {
+		return u.withAuthor(*article), nil
+	}
+
+	for _, candidate := range []string{locale, u.locales.Default} {
+		translation, err := u.repo.FindTranslation(article.TranslationGroupID, candidate)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return dto.ArticleResponse{}, err
+		}
+		return u.withAuthor(*translation), nil
+	}
+
+	return u.withAuthor(*article), nil
+}
+
+// withAuthor converts an article to its response DTO together with its author
+func (u *translationUsecase) withAuthor(article entity.Article) dto.ArticleResponse {
+	return toArticleResponses(u.authors, []entity.Article{article})[0]
+}
+
+// FindGroups returns every article of several translation groups with a single query, keyed by group
+func (u *translationUsecase) FindGroups(groupIDs []uint) (map[uint][]dto.ArticleResponse, error) {
+	articles, err := u.repo.FindByGroups(groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[uint][]dto.ArticleResponse, len(groupIDs))
+	for _, response := range toArticleResponses(u.authors, articles) {
+		groups[response.TranslationGroupID] = append(groups[response.TranslationGroupID], response)
+	}
+	return groups, nil
+}
+
+// CreateTranslation creates a translation of the article's source in a new locale
+func (u *translationUsecase) CreateTranslation(id uint, req dto.CreateTranslationRequest) (dto.ArticleWriteResponse, error) {
+	if !u.locales.supports(req.Locale) {
+		return dto.ArticleWriteResponse{}, ErrUnsupportedLocale
+	}
+
+	source, err := u.findSource(id)
+	if err != nil {
+		return dto.ArticleWriteResponse{}, err
+	}
+
+	_, err = u.repo.FindTranslation(source.TranslationGroupID, req.Locale)
+	if err == nil {
+		return dto.ArticleWriteResponse{}, ErrTranslationExists
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return dto.ArticleWriteResponse{}, err
+	}
+
+	category := req.Category
+	if category == "" {
+		category = source.Category
+	}
+	status := req.Status
+	if status == "" {
+		status = entity.StatusDraft
+	}
+	sourceRevision := source.Revision
+
+	// Go through the regular create flow so content policy and duplicate checks still apply
+	return u.articleUsecase.CreateArticle(dto.CreateArticleRequest{
+		Title:              req.Title,
+		Content:            req.Content,
+		Category:           category,
+		Status:             status,
+		Tags:               req.Tags,
+		Locale:             req.Locale,
+		AuthorID:           source.AuthorID,
+		TranslationGroupID: source.TranslationGroupID,
+		SourceRevision:     &sourceRevision,
+	})
+}
+
+// GetTranslationStatus reports, for every supported locale, whether the translation is missing or out of date
+func (u *translationUsecase) GetTranslationStatus(id uint) (dto.TranslationStatusResponse, error) {
+	source, err := u.findSource(id)
+	if err != nil {
+		return dto.TranslationStatusResponse{}, err
+	}
+
+	articles, err := u.repo.FindTranslations(source.TranslationGroupID)
+	if err != nil {
+		return dto.TranslationStatusResponse{}, err
+	}
+
+	byLocale := make(map[string]entity.Article, len(articles))
+	for _, article := range articles {
+		byLocale[article.Locale] = article
+	}
+
+	response := dto.TranslationStatusResponse{
+		TranslationGroupID: source.TranslationGroupID,
+		SourceID:           source.ID,
+		SourceLocale:       source.Locale,
+		SourceRevision:     source.Revision,
+		Translations:       []dto.TranslationStatus{},
+	}
+	for _, locale := range u.locales.Supported {
+		if locale == source.Locale {
+			continue
+		}
+
+		translation, ok := byLocale[locale]
+		if !ok {
+			response.Translations = append(response.Translations, dto.TranslationStatus{Locale: locale, State: TranslationMissing})
+			continue
+		}
+
+		status := dto.TranslationStatus{Locale: locale, State: TranslationUpToDate, ArticleID: translation.ID}
+		if translation.SourceRevision != nil {
+			status.SourceRevision = *translation.SourceRevision
+		}
+		if status.SourceRevision < source.Revision {
+			status.State = TranslationOutdated
+		}
+		response.Translations = append(response.Translations, status)
+	}
+
+	return response, nil
+}
+
+// findSource returns the source article of the translation group the article belongs to
+func (u *translationUsecase) findSource(id uint) (*entity.Article, error) {
+	article, err := findArticle(u.repo, id)
+	if err != nil {
+		return nil, err
+	}
+	if !article.IsTranslation() {
+		return article, nil
+	}
+
+	source, err := u.repo.FindByID(article.TranslationGroupID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrSourceArticleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return source, nil
+}
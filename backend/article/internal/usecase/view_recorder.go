@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/article/internal/repository"
+)
+
+// ViewRecorder buffers article views in memory and flushes them to the repository in batches
+type ViewRecorder interface {
+	Record(articleID uint, clientKey string)
+	Start()
+	Stop()
+}
+
+type viewKey struct {
+	articleID uint
+	date      time.Time
+}
+
+type viewRecorder struct {
+	repo          repository.ArticleViewRepository
+	flushInterval time.Duration
+	dedupWindow   time.Duration
+	maxPending    int
+
+	mu      sync.Mutex
+	pending map[viewKey]int64
+	seen    map[string]time.Time
+
+	flushCh chan struct{}
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+// NewViewRecorder creates a new instance of ViewRecorder.
+// Views of the same article by the same client inside dedupWindow are counted once.
+func NewViewRecorder(r repository.ArticleViewRepository, flushInterval, dedupWindow time.Duration) ViewRecorder {
+	return &viewRecorder{
+		repo:          r,
+		flushInterval: flushInterval,
+		dedupWindow:   dedupWindow,
+		maxPending:    1000,
+		pending:       make(map[viewKey]int64),
+		seen:          make(map[string]time.Time),
+		flushCh:       make(chan struct{}, 1),
+		stopCh:        make(chan struct{}),
+		doneCh:        make(chan struct{}),
+	}
+}
+
+// Record registers a view without touching the database, so it never slows down the request
+func (v *viewRecorder) Record(articleID uint, clientKey string) {
+	now := time.Now().UTC()
+	dedupKey := clientKey + "|" + strconv.FormatUint(uint64(articleID), 10)
+
+	v.mu.Lock()
+	if last, ok := v.seen[dedupKey]; ok && now.Sub(last) < v.dedupWindow {
+		v.mu.Unlock()
+		return
+	}
+	v.seen[dedupKey] = now
+	v.pending[viewKey{articleID: articleID, date: now.Truncate(24 * time.Hour)}]++
+	full := len(v.pending) >= v.maxPending
+	v.mu.Unlock()
+
+	// Ask for an early flush when the buffer grows too large
+	if full {
+		select {
+		case v.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Start runs the background flush loop
+func (v *viewRecorder) Start() {
+	go func() {
+		defer close(v.doneCh)
+
+		ticker := time.NewTicker(v.flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				v.flush()
+				v.pruneSeen()
+			case <-v.flushCh:
+				v.flush()
+			case <-v.stopCh:
+				v.flush()
+				return
+			}
+		}
+	}()
+}
+
+// Stop flushes the remaining views and stops the background loop
+func (v *viewRecorder) Stop() {
+	close(v.stopCh)
+	<-v.doneCh
+}
+
+func (v *viewRecorder) flush() {
+	v.mu.Lock()
+	if len(v.pending) == 0 {
+		v.mu.Unlock()
+		return
+	}
+	batch := v.pending
+	v.pending = make(map[viewKey]int64)
+	v.mu.Unlock()
+
+	views := make([]entity.ArticleViewDaily, 0, len(batch))
+	for key, count := range batch {
+		views = append(views, entity.ArticleViewDaily{
+			ArticleID: key.articleID,
+			ViewDate:  key.date,
+			Views:     count,
+		})
+	}
+
+	if err := v.repo.IncrementViews(views); err != nil {
+		log.Println("Failed to flush article views:", err)
+
+		// Put the batch back so the views are retried on the next flush
+		v.mu.Lock()
+		for key, count := range batch {
+			v.pending[key] += count
+		}
+		v.mu.Unlock()
+	}
+}
+
+// pruneSeen drops dedup entries that are older than the dedup window
+func (v *viewRecorder) pruneSeen() {
+	now := time.Now().UTC()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for key, last := range v.seen {
+		if now.Sub(last) >= v.dedupWindow {
+			delete(v.seen, key)
+		}
+	}
+}
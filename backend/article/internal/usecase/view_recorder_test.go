@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// waitForViews waits until the recorder flushed the wanted counts
+func waitForViews(t *testing.T, repo *fakeViewRepository, want map[uint]int64) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !reflect.DeepEqual(repo.views(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("got views %v, want %v", repo.views(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestViewRecorderCountsClientsOncePerWindow(t *testing.T) {
+	repo := &fakeViewRepository{}
+	recorder := NewViewRecorder(repo, time.Hour, 30*time.Minute)
+	recorder.Start()
+
+	recorder.Record(1, "budi")
+	recorder.Record(1, "budi")
+	recorder.Record(1, "sari")
+	recorder.Record(2, "budi")
+
+	// Stopping flushes what is still buffered
+	recorder.Stop()
+	if got, want := repo.views(), map[uint]int64{1: 2, 2: 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got views %v, want %v", got, want)
+	}
+}
+
+func TestViewRecorderFlushesEarlyWhenFull(t *testing.T) {
+	repo := &fakeViewRepository{}
+	recorder := NewViewRecorder(repo, time.Hour, time.Minute)
+	recorder.(*viewRecorder).maxPending = 2
+	recorder.Start()
+	defer recorder.Stop()
+
+	recorder.Record(1, "budi")
+	if len(repo.views()) != 0 {
+		t.Fatal("got views flushed before the buffer was full")
+	}
+	recorder.Record(2, "budi")
+	waitForViews(t, repo, map[uint]int64{1: 1, 2: 1})
+}
+
+func TestViewRecorderRetriesFailedFlush(t *testing.T) {
+	repo := &fakeViewRepository{err: errDatabase}
+	recorder := NewViewRecorder(repo, time.Hour, time.Minute).(*viewRecorder)
+
+	recorder.Record(1, "budi")
+	recorder.flush()
+	recorder.Record(1, "sari")
+
+	repo.err = nil
+	recorder.flush()
+	if got, want := repo.views(), map[uint]int64{1: 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got views %v, want the failed batch retried with the new view", got)
+	}
+
+	// The dedup window forgets clients once it passed
+	recorder.dedupWindow = 0
+	recorder.pruneSeen()
+	if len(recorder.seen) != 0 {
+		t.Errorf("got %d clients remembered, want none after the window", len(recorder.seen))
+	}
+}
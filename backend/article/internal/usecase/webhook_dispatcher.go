@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/article/internal/repository"
+	"github.com/yuhari7/backend_supervision/article/pkg/netguard"
+	"github.com/yuhari7/backend_supervision/article/pkg/signature"
+	"github.com/yuhari7/backend_supervision/shared/outbox"
+)
+
+// Headers sent with every webhook request
+const (
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookEventID   = "X-Webhook-Event-Id" // the same on redeliveries, receivers drop duplicates with it
+	HeaderWebhookDelivery  = "X-Webhook-Delivery"
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	HeaderWebhookSignature = "X-Webhook-Signature" // sha256=HMAC(secret, "<timestamp>.<body>")
+)
+
+// maxResponseBody is how much of a response is kept in the delivery log
+const maxResponseBody = 1024
+
+// WebhookConfig configures the webhook dispatcher, zero values fall back to the defaults
+type WebhookConfig struct {
+	Interval     time.Duration // how often due deliveries are sent, defaults to 2s
+	BatchSize    int           // deliveries sent per round, defaults to 50
+	Timeout      time.Duration // per request, defaults to 10s
+	MaxAttempts  int           // attempts before a delivery is given up, defaults to 8
+	MaxBackoff   time.Duration // longest wait between attempts, defaults to 1h
+	DisableAfter int           // failed attempts in a row disabling a subscription, defaults to 20
+
+	HTTPClient *http.Client // defaults to a client refusing private and local addresses
+}
+
+// WebhookDispatcher queues article events for the matching subscriptions and sends them in the background
+type WebhookDispatcher interface {
+	Enqueue(ctx context.Context, event outbox.Event) error
+	Flush(ctx context.Context) (int, error)
+	Start()
+	Stop()
+}
+
+type webhookDispatcher struct {
+	repo   repository.WebhookRepository
+	config WebhookConfig
+	client *http.Client
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewWebhookDispatcher creates a new instance of WebhookDispatcher.
+// Enqueue is meant to be subscribed to the outbox broker so events reach webhooks at least once.
+func NewWebhookDispatcher(r repository.WebhookRepository, config WebhookConfig) WebhookDispatcher {
+	if config.Interval <= 0 {
+		config.Interval = 2 * time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 50
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 8
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = time.Hour
+	}
+	if config.DisableAfter <= 0 {
+		config.DisableAfter = 20
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = netguard.NewClient()
+	}
+
+	return &webhookDispatcher{
+		repo:   r,
+		config: config,
+		client: client,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+}
+
+// Enqueue records a delivery of the event for every active subscription accepting it.
+// An event relayed twice is only queued once per subscription.
+func (d *webhookDispatcher) Enqueue(ctx context.Context, event outbox.Event) error {
+	subscriptions, err := d.repo.FindActive()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(dto.WebhookEvent{
+		ID:            event.ID,
+		Type:          event.Type,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		Payload:       event.Payload,
+		OccurredAt:    event.OccurredAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	var deliveries []entity.WebhookDelivery
+	now := time.Now().UTC()
+	for _, subscription := range subscriptions {
+		if subscription.Accepts(event.Type) {
+			deliveries = append(deliveries, entity.WebhookDelivery{
+				SubscriptionID: subscription.ID,
+				EventID:        event.ID,
+				EventType:      event.Type,
+				Payload:        string(body),
+				Status:         entity.DeliveryPending,
+				NextAttemptAt:  now,
+			})
+		}
+	}
+	return d.repo.CreateDeliveries(deliveries)
+}
+
+// Start runs the background delivery loop
+func (d *webhookDispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		defer close(d.doneCh)
+		defer cancel()
+
+		ticker := time.NewTicker(d.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := d.Flush(ctx); err != nil {
+					log.Println("Failed to send webhooks:", err)
+				}
+			case <-d.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background loop, pending deliveries are sent after the next start
+func (d *webhookDispatcher) Stop() {
+	close(d.stopCh)
+	<-d.doneCh
+}
+
+// Flush sends one batch of due deliveries and returns how many were attempted
+func (d *webhookDispatcher) Flush(ctx context.Context) (int, error) {
+	deliveries, err := d.repo.ClaimDeliveries(d.config.BatchSize, d.config.Timeout+time.Minute)
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := make(map[uint]*entity.WebhookSubscription)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			if subscription, err = d.repo.FindByID(delivery.SubscriptionID); err != nil {
+				return i, err
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+		// A subscription disabled earlier in this batch gets its deliveries back once it is activated again
+		if !subscription.Active {
+			continue
+		}
+
+		if err := d.attempt(ctx, subscription, delivery); err != nil {
+			return i + 1, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt sends a delivery once and records the outcome, scheduling a retry or counting the failure
+func (d *webhookDispatcher) attempt(ctx context.Context, subscription *entity.WebhookSubscription, delivery *entity.WebhookDelivery) error {
+	start := time.Now()
+	code, body, err := d.send(ctx, subscription, delivery)
+
+	delivery.Attempts++
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	delivery.ResponseCode = code
+	delivery.ResponseBody = body
+	delivery.Error = ""
+
+	if err == nil {
+		now := time.Now().UTC()
+		delivery.Status = entity.DeliverySucceeded
+		delivery.DeliveredAt = &now
+		if err := d.repo.RecordSuccess(subscription.ID); err != nil {
+			return err
+		}
+		return d.repo.UpdateDelivery(delivery)
+	}
+
+	delivery.Error = err.Error()
+	if delivery.Attempts >= d.config.MaxAttempts {
+		delivery.Status = entity.DeliveryFailed
+	} else {
+		delivery.NextAttemptAt = time.Now().UTC().Add(d.backoff(delivery.Attempts))
+	}
+
+	disabled, recordErr := d.repo.RecordFailure(subscription.ID, d.config.DisableAfter)
+	if recordErr != nil {
+		return recordErr
+	}
+	if disabled {
+		log.Printf("Disabled webhook %d after %d failed deliveries in a row", subscription.ID, d.config.DisableAfter)
+		subscription.Active = false
+	}
+	return d.repo.UpdateDelivery(delivery)
+}
+
+// send posts the signed payload, a response other than 2xx counts as a failure
+func (d *webhookDispatcher) send(ctx context.Context, subscription *entity.WebhookSubscription, delivery *entity.WebhookDelivery) (*int, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	defer cancel()
+
+	payload := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, "", err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "article-service-webhooks/1.0")
+	req.Header.Set(HeaderWebhookEvent, delivery.EventType)
+	req.Header.Set(HeaderWebhookEventID, delivery.EventID)
+	req.Header.Set(HeaderWebhookDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderWebhookSignature, signature.Sign(subscription.Secret, timestamp, payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, maxResponseBody))
+	code := res.StatusCode
+	if code < 200 || code > 299 {
+		return &code, responseText(body), fmt.Errorf("endpoint responded with status %d", code)
+	}
+	return &code, responseText(body), nil
+}
+
+// responseText makes a response body storable as text, Postgres refuses NUL bytes and invalid UTF-8
+func responseText(body []byte) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(string(body), "\uFFFD"), "\x00", "")
+}
+
+// backoff waits 30 seconds after the first failed attempt and doubles the wait after every further one
+func (d *webhookDispatcher) backoff(attempts int) time.Duration {
+	wait := 30 * time.Second
+	for i := 1; i < attempts && wait < d.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.config.MaxBackoff)
+}
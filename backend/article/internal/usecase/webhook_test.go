@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/article/pkg/signature"
+	"github.com/yuhari7/backend_supervision/shared/outbox"
+)
+
+// receiver stands in for a downstream system, answering with status and checking signatures
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	body     []byte
+	requests []*http.Request
+	verified []bool
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	timestamp, _ := strconv.ParseInt(req.Header.Get(HeaderWebhookTimestamp), 10, 64)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.verified = append(r.verified, signature.Verify("0123456789abcdef", timestamp, body, req.Header.Get(HeaderWebhookSignature)))
+	w.WriteHeader(r.status)
+	if r.body != nil {
+		_, _ = w.Write(r.body)
+		return
+	}
+	_, _ = w.Write([]byte("received"))
+}
+
+// newReceiver serves on loopback, dispatchers reaching it need an HTTPClient other than the default
+func newReceiver(t *testing.T, status int) (*receiver, string) {
+	r := &receiver{status: status}
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return r, server.URL
+}
+
+func newPublishedEvent(t *testing.T) outbox.Event {
+	t.Helper()
+	event, err := outbox.NewEvent(EventArticlePublished, "article", 7, dto.ArticleEvent{ID: 7, Title: "Published", Status: entity.StatusPublish})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+// makeDue moves every pending delivery to the front of the queue, skipping the backoff
+func makeDue(repo *fakeWebhookRepository) {
+	for _, delivery := range repo.deliveries {
+		delivery.NextAttemptAt = time.Now().Add(-time.Second)
+	}
+}
+
+func TestWebhooksAreFilteredAndSigned(t *testing.T) {
+	rec, url := newReceiver(t, http.StatusOK)
+	repo := newFakeWebhookRepository(
+		entity.WebhookSubscription{ID: 1, URL: url, Secret: "0123456789abcdef", Events: entity.TagList{EventArticlePublished}, Active: true},
+		entity.WebhookSubscription{ID: 2, URL: url, Secret: "0123456789abcdef", Events: entity.TagList{"*"}, Active: true},
+		entity.WebhookSubscription{ID: 3, URL: url, Secret: "0123456789abcdef", Events: entity.TagList{EventArticleDeleted}, Active: true},
+		entity.WebhookSubscription{ID: 4, URL: url, Secret: "0123456789abcdef", Events: entity.TagList{"*"}, Active: false},
+	)
+	dispatcher := NewWebhookDispatcher(repo, WebhookConfig{HTTPClient: http.DefaultClient})
+
+	// The outbox relays at least once, the second copy of the event is not queued again
+	event := newPublishedEvent(t)
+	for i := 0; i < 2; i++ {
+		if err := dispatcher.Enqueue(context.Background(), event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if sent, err := dispatcher.Flush(context.Background()); err != nil || sent != 2 {
+		t.Fatalf("got %d deliveries sent and error %v, want 2 for subscriptions 1 and 2", sent, err)
+	}
+
+	for i, req := range rec.requests {
+		if !rec.verified[i] {
+			t.Errorf("request %d has an invalid signature %q", i, req.Header.Get(HeaderWebhookSignature))
+		}
+		if req.Header.Get(HeaderWebhookEvent) != EventArticlePublished || req.Header.Get(HeaderWebhookEventID) != event.ID {
+			t.Errorf("request %d has event headers %v", i, req.Header)
+		}
+	}
+	for _, delivery := range repo.deliveries {
+		if delivery.Status != entity.DeliverySucceeded || delivery.ResponseCode == nil || *delivery.ResponseCode != http.StatusOK || delivery.DeliveredAt == nil {
+			t.Errorf("got delivery %+v, want it succeeded with status 200", delivery)
+		}
+	}
+}
+
+func TestFailedWebhooksAreRetriedWithBackoff(t *testing.T) {
+	rec, url := newReceiver(t, http.StatusBadGateway)
+	repo := newFakeWebhookRepository(entity.WebhookSubscription{ID: 1, URL: url, Secret: "0123456789abcdef", Events: entity.TagList{"*"}, Active: true})
+	dispatcher := NewWebhookDispatcher(repo, WebhookConfig{MaxAttempts: 3, HTTPClient: http.DefaultClient})
+	if err := dispatcher.Enqueue(context.Background(), newPublishedEvent(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher.Flush(context.Background())
+	delivery := repo.deliveries[0]
+	if delivery.Status != entity.DeliveryPending || delivery.Attempts != 1 || *delivery.ResponseCode != http.StatusBadGateway || delivery.ResponseBody != "received" {
+		t.Fatalf("got delivery %+v after a failure, want it pending with the response logged", delivery)
+	}
+	if wait := time.Until(delivery.NextAttemptAt); wait < 25*time.Second || wait > 30*time.Second {
+		t.Errorf("got next attempt in %v, want about 30s", wait)
+	}
+
+	// Nothing is sent before the backoff has passed
+	if sent, _ := dispatcher.Flush(context.Background()); sent != 0 {
+		t.Errorf("got %d deliveries sent during the backoff, want none", sent)
+	}
+
+	for i := 0; i < 2; i++ {
+		makeDue(repo)
+		dispatcher.Flush(context.Background())
+	}
+	if delivery := repo.deliveries[0]; delivery.Status != entity.DeliveryFailed || delivery.Attempts != 3 || len(rec.requests) != 3 {
+		t.Errorf("got delivery %+v after %d requests, want it failed after 3 attempts", delivery, len(rec.requests))
+	}
+}
+
+func TestBinaryResponsesAreStored(t *testing.T) {
+	rec, url := newReceiver(t, http.StatusOK)
+	rec.body = []byte("\x1f\x8b\x08\x00ok\xff")
+	repo := newFakeWebhookRepository(entity.WebhookSubscription{ID: 1, URL: url, Secret: "0123456789abcdef", Events: entity.TagList{"*"}, Active: true})
+	dispatcher := NewWebhookDispatcher(repo, WebhookConfig{HTTPClient: http.DefaultClient})
+	if err := dispatcher.Enqueue(context.Background(), newPublishedEvent(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent, err := dispatcher.Flush(context.Background()); err != nil || sent != 1 {
+		t.Fatalf("got %d deliveries sent and error %v, want the delivery recorded", sent, err)
+	}
+	if delivery := repo.deliveries[0]; delivery.Status != entity.DeliverySucceeded || delivery.Attempts != 1 || delivery.ResponseBody != "\x1f\uFFFD\x08ok\uFFFD" {
+		t.Errorf("got delivery %+v, want it succeeded with the body cleaned up", delivery)
+	}
+}
+
+func TestDefaultClientRefusesLocalAddresses(t *testing.T) {
+	rec, url := newReceiver(t, http.StatusOK)
+	repo := newFakeWebhookRepository(entity.WebhookSubscription{ID: 1, URL: url, Secret: "0123456789abcdef", Events: entity.TagList{"*"}, Active: true})
+	dispatcher := NewWebhookDispatcher(repo, WebhookConfig{})
+	if err := dispatcher.Enqueue(context.Background(), newPublishedEvent(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher.Flush(context.Background())
+	if delivery := repo.deliveries[0]; delivery.Status != entity.DeliveryPending || delivery.Attempts != 1 || len(rec.requests) != 0 {
+		t.Errorf("got delivery %+v after %d requests, want the loopback receiver never reached", delivery, len(rec.requests))
+	}
+}
+
+func TestRepeatedFailuresDisableWebhook(t *testing.T) {
+	rec, url := newReceiver(t, http.StatusInternalServerError)
+	repo := newFakeWebhookRepository(entity.WebhookSubscription{ID: 1, URL: url, Secret: "0123456789abcdef", Events: entity.TagList{"*"}, Active: true})
+	dispatcher := NewWebhookDispatcher(repo, WebhookConfig{DisableAfter: 2, HTTPClient: http.DefaultClient})
+	webhooks := NewWebhookUsecase(repo)
+
+	for i := 0; i < 3; i++ {
+		if err := dispatcher.Enqueue(context.Background(), newPublishedEvent(t)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dispatcher.Flush(context.Background())
+
+	// The third delivery is held back once two failures in a row disabled the subscription
+	subscription, _ := webhooks.GetWebhook(1)
+	if subscription.Active || subscription.DisabledAt == nil || len(rec.requests) != 2 {
+		t.Fatalf("got subscription %+v after %d requests, want it disabled after 2", subscription, len(rec.requests))
+	}
+	if err := dispatcher.Enqueue(context.Background(), newPublishedEvent(t)); err != nil || len(repo.deliveries) != 3 {
+		t.Errorf("got %d deliveries and error %v, want no new delivery for a disabled subscription", len(repo.deliveries), err)
+	}
+
+	// Activating it again resets the failures, a manual redelivery then goes through
+	rec.mu.Lock()
+	rec.status = http.StatusOK
+	rec.mu.Unlock()
+	if _, err := webhooks.UpdateWebhook(dto.UpdateWebhookRequest{ID: 1, URL: url, Events: []string{"*"}, Active: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := webhooks.Redeliver(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(repo)
+	dispatcher.Flush(context.Background())
+
+	deliveries, err := webhooks.ListDeliveries(1, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 3 || deliveries[2].Status != entity.DeliverySucceeded || deliveries[2].Attempts != 1 {
+		t.Errorf("got deliveries %+v, want the redelivered one succeeded on its first new attempt", deliveries)
+	}
+	if subscription, _ := webhooks.GetWebhook(1); subscription.ConsecutiveFailures != 0 {
+		t.Errorf("got %d consecutive failures, want them reset", subscription.ConsecutiveFailures)
+	}
+}
+
+// staticResolver resolves hosts from a fixed table, so URL checks do not depend on DNS
+type staticResolver map[string]string
+
+func (r staticResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ip, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+}
+
+func TestWebhookErrors(t *testing.T) {
+	webhooks := &webhookUsecase{
+		repo:     newFakeWebhookRepository(),
+		resolver: staticResolver{"example.com": "93.184.215.14", "intranet.example.com": "192.168.1.10"},
+	}
+
+	if _, err := webhooks.CreateWebhook(dto.CreateWebhookRequest{URL: "ftp://example.com", Events: []string{"*"}}); !errors.Is(err, ErrInvalidWebhookURL) {
+		t.Errorf("got error %v for an ftp URL, want %v", err, ErrInvalidWebhookURL)
+	}
+	for _, url := range []string{"http://127.0.0.1:8001/hook", "http://169.254.169.254/latest/meta-data", "http://[::1]/hook", "https://intranet.example.com/hook", "https://missing.example.com/hook"} {
+		if _, err := webhooks.CreateWebhook(dto.CreateWebhookRequest{URL: url, Events: []string{"*"}}); !errors.Is(err, ErrForbiddenWebhookURL) {
+			t.Errorf("got error %v for %s, want %v", err, url, ErrForbiddenWebhookURL)
+		}
+	}
+	if _, err := webhooks.GetWebhook(missingID); !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrWebhookNotFound)
+	}
+	if _, err := webhooks.Redeliver(missingID, 1); !errors.Is(err, ErrWebhookDeliveryNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrWebhookDeliveryNotFound)
+	}
+
+	created, err := webhooks.CreateWebhook(dto.CreateWebhookRequest{URL: "https://example.com/hook", Events: []string{EventArticlePublished}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created.Secret) != 64 {
+		t.Errorf("got secret %q, want 32 random bytes in hex", created.Secret)
+	}
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/internal/entity"
+	"github.com/yuhari7/backend_supervision/article/internal/repository"
+	"github.com/yuhari7/backend_supervision/article/pkg/netguard"
+)
+
+// WebhookUsecase defines the methods for managing webhook subscriptions and their delivery log
+type WebhookUsecase interface {
+	CreateWebhook(req dto.CreateWebhookRequest) (dto.WebhookResponse, error)
+	ListWebhooks() ([]entity.WebhookSubscription, error)
+	GetWebhook(id uint) (entity.WebhookSubscription, error)
+	UpdateWebhook(req dto.UpdateWebhookRequest) (entity.WebhookSubscription, error)
+	DeleteWebhook(id uint) error
+	ListDeliveries(id uint, limit, offset int) ([]entity.WebhookDelivery, error)
+	Redeliver(id, deliveryID uint) (entity.WebhookDelivery, error)
+}
+
+type webhookUsecase struct {
+	repo     repository.WebhookRepository
+	resolver netguard.Resolver
+}
+
+// NewWebhookUsecase creates a new instance of WebhookUsecase
+func NewWebhookUsecase(r repository.WebhookRepository) WebhookUsecase {
+	return &webhookUsecase{repo: r, resolver: net.DefaultResolver}
+}
+
+// CreateWebhook subscribes an endpoint, generating a signing secret when none is given
+func (u *webhookUsecase) CreateWebhook(req dto.CreateWebhookRequest) (dto.WebhookResponse, error) {
+	if err := u.checkWebhookURL(req.URL); err != nil {
+		return dto.WebhookResponse{}, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return dto.WebhookResponse{}, err
+		}
+		secret = hex.EncodeToString(key)
+	}
+
+	subscription := entity.WebhookSubscription{
+		URL:    req.URL,
+		Secret: secret,
+		Events: entity.NormalizeTags(req.Events),
+		Active: true,
+	}
+	if err := u.repo.Create(&subscription); err != nil {
+		return dto.WebhookResponse{}, err
+	}
+	return dto.WebhookResponse{WebhookSubscription: subscription, Secret: secret}, nil
+}
+
+func (u *webhookUsecase) ListWebhooks() ([]entity.WebhookSubscription, error) {
+	return u.repo.FindAll()
+}
+
+func (u *webhookUsecase) GetWebhook(id uint) (entity.WebhookSubscription, error) {
+	subscription, err := u.findWebhook(id)
+	if err != nil {
+		return entity.WebhookSubscription{}, err
+	}
+	return *subscription, nil
+}
+
+// UpdateWebhook changes a subscription, activating a disabled subscription starts counting failures afresh.
+// Only a new URL is checked again, the dispatcher refuses private addresses on every delivery anyway.
+func (u *webhookUsecase) UpdateWebhook(req dto.UpdateWebhookRequest) (entity.WebhookSubscription, error) {
+	subscription, err := u.findWebhook(req.ID)
+	if err != nil {
+		return entity.WebhookSubscription{}, err
+	}
+	if req.URL != subscription.URL {
+		if err := u.checkWebhookURL(req.URL); err != nil {
+			return entity.WebhookSubscription{}, err
+		}
+	}
+
+	if req.Active && !subscription.Active {
+		subscription.ConsecutiveFailures = 0
+		subscription.DisabledAt = nil
+	}
+	subscription.URL = req.URL
+	subscription.Events = entity.NormalizeTags(req.Events)
+	subscription.Active = req.Active
+
+	if err := u.repo.Update(subscription); err != nil {
+		return entity.WebhookSubscription{}, err
+	}
+	return *subscription, nil
+}
+
+// DeleteWebhook removes a subscription together with its delivery log
+func (u *webhookUsecase) DeleteWebhook(id uint) error {
+	err := u.repo.Delete(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// ListDeliveries returns the delivery log of a subscription, newest first
+func (u *webhookUsecase) ListDeliveries(id uint, limit, offset int) ([]entity.WebhookDelivery, error) {
+	if _, err := u.findWebhook(id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return u.repo.FindDeliveries(id, limit, offset)
+}
+
+// Redeliver queues a delivery again with a fresh set of attempts, whatever its outcome so far.
+// Deliveries to a disabled subscription wait until it is activated again.
+func (u *webhookUsecase) Redeliver(id, deliveryID uint) (entity.WebhookDelivery, error) {
+	delivery, err := u.repo.FindDelivery(id, deliveryID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return entity.WebhookDelivery{}, ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+
+	delivery.Status = entity.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+	if err := u.repo.UpdateDelivery(delivery); err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+	return *delivery, nil
+}
+
+func (u *webhookUsecase) findWebhook(id uint) (*entity.WebhookSubscription, error) {
+	subscription, err := u.repo.FindByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// checkWebhookURL only accepts absolute http and https URLs whose host resolves to public addresses.
+// The dispatcher checks the address again when connecting, the host may be re-pointed in between.
+func (u *webhookUsecase) checkWebhookURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := netguard.CheckHost(ctx, u.resolver, parsed.Hostname()); err != nil {
+		return ErrForbiddenWebhookURL.Wrap(err)
+	}
+	return nil
+}
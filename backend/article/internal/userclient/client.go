@@ -0,0 +1,154 @@
+// Package userclient calls the internal gRPC API of the user service.
+package userclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/article/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/article/pkg/breaker"
+	userv1 "github.com/yuhari7/backend_supervision/shared/proto/user/v1"
+)
+
+// maxBatchSize is the largest number of IDs the user service accepts in one lookup
+const maxBatchSize = 500
+
+// User is the public profile of a user
+type User struct {
+	ID        uint
+	Name      string
+	AvatarURL string
+}
+
+// Config configures the client, zero durations and thresholds fall back to the defaults
+type Config struct {
+	Users userv1.UserServiceClient // see Dial
+
+	Timeout          time.Duration // per request, defaults to 2s
+	CacheTTL         time.Duration // how long users are cached, defaults to 1m
+	FailureThreshold int           // consecutive failures opening the circuit breaker, defaults to 5
+	Cooldown         time.Duration // how long the breaker stays open, defaults to 30s
+}
+
+type cacheEntry struct {
+	user      User
+	found     bool
+	expiresAt time.Time
+}
+
+// Client looks up users in batches, caching them for a short time.
+// Calls go through a circuit breaker so a user service outage does not slow down every response.
+type Client struct {
+	config  Config
+	breaker *breaker.Breaker
+
+	mu    sync.Mutex
+	cache map[uint]cacheEntry
+}
+
+// New creates a client for the user service answering config.Users
+func New(config Config) *Client {
+	if config.Timeout <= 0 {
+		config.Timeout = 2 * time.Second
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = time.Minute
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 30 * time.Second
+	}
+
+	return &Client{
+		config:  config,
+		breaker: breaker.New(config.FailureThreshold, config.Cooldown),
+		cache:   make(map[uint]cacheEntry),
+	}
+}
+
+// Users returns the users with the given IDs, unknown IDs are left out.
+// Cached users are served without a call, the others are fetched in as few requests as possible.
+// When a request fails the users found so far are returned along with the error.
+func (c *Client) Users(ctx context.Context, ids []uint) (map[uint]User, error) {
+	users := make(map[uint]User, len(ids))
+	var missing []uint
+	queued := make(map[uint]bool)
+
+	c.mu.Lock()
+	now := time.Now()
+	for _, id := range ids {
+		entry, ok := c.cache[id]
+		switch {
+		case ok && now.Before(entry.expiresAt):
+			if entry.found {
+				users[id] = entry.user
+			}
+		case !queued[id]:
+			queued[id] = true
+			missing = append(missing, id)
+		}
+	}
+	c.mu.Unlock()
+
+	for start := 0; start < len(missing); start += maxBatchSize {
+		batch := missing[start:min(start+maxBatchSize, len(missing))]
+		fetched, err := c.fetch(ctx, batch)
+		if err != nil {
+			return users, err
+		}
+
+		c.mu.Lock()
+		expiresAt := time.Now().Add(c.config.CacheTTL)
+		for _, id := range batch {
+			user, found := fetched[id]
+			c.cache[id] = cacheEntry{user: user, found: found, expiresAt: expiresAt}
+			if found {
+				users[id] = user
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	return users, nil
+}
+
+// FindAuthors implements usecase.AuthorDirectory, returning the cached authors when the user service fails
+func (c *Client) FindAuthors(ids []uint) (map[uint]dto.AuthorResponse, error) {
+	users, err := c.Users(context.Background(), ids)
+	authors := make(map[uint]dto.AuthorResponse, len(users))
+	for id, user := range users {
+		authors[id] = dto.AuthorResponse{ID: user.ID, Name: user.Name, AvatarURL: user.AvatarURL}
+	}
+	return authors, err
+}
+
+// fetch requests one batch of users through the circuit breaker
+func (c *Client) fetch(ctx context.Context, ids []uint) (map[uint]User, error) {
+	req := &userv1.BatchGetUsersRequest{Ids: make([]uint64, 0, len(ids))}
+	for _, id := range ids {
+		req.Ids = append(req.Ids, uint64(id))
+	}
+
+	var res *userv1.BatchGetUsersResponse
+	err := c.breaker.Do(func() error {
+		ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+
+		var err error
+		res, err = c.config.Users.BatchGetUsers(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("looking up users: %w", err)
+	}
+
+	users := make(map[uint]User, len(res.GetUsers()))
+	for _, user := range res.GetUsers() {
+		users[uint(user.GetId())] = User{ID: uint(user.GetId()), Name: user.GetName(), AvatarURL: user.GetAvatarUrl()}
+	}
+	return users, nil
+}
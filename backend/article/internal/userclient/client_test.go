@@ -0,0 +1,157 @@
+package userclient
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yuhari7/backend_supervision/article/pkg/breaker"
+	userv1 "github.com/yuhari7/backend_supervision/shared/proto/user/v1"
+)
+
+// userService stands in for the gRPC API of the user service
+type userService struct {
+	userv1.UserServiceClient
+
+	mu       sync.Mutex
+	requests []string
+	err      error
+	delay    time.Duration
+}
+
+func (s *userService) BatchGetUsers(ctx context.Context, in *userv1.BatchGetUsersRequest, opts ...grpc.CallOption) (*userv1.BatchGetUsersResponse, error) {
+	parts := make([]string, 0, len(in.GetIds()))
+	for _, id := range in.GetIds() {
+		parts = append(parts, strconv.FormatUint(id, 10))
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, strings.Join(parts, ","))
+	err, delay := s.err, s.delay
+	s.mu.Unlock()
+
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Every ID below 100 belongs to a user
+	response := &userv1.BatchGetUsersResponse{}
+	for i, id := range in.GetIds() {
+		if id < 100 {
+			response.Users = append(response.Users, &userv1.User{Id: id, Name: "User " + parts[i], AvatarUrl: "https://avatars.test/" + parts[i]})
+		} else {
+			response.MissingIds = append(response.MissingIds, id)
+		}
+	}
+	return response, nil
+}
+
+func (s *userService) set(err error, delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err, s.delay = err, delay
+}
+
+func (s *userService) calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func newTestClient(t *testing.T, config Config) (*Client, *userService) {
+	service := &userService{}
+	config.Users = service
+	return New(config), service
+}
+
+func TestUsersAreBatchedAndCached(t *testing.T) {
+	client, service := newTestClient(t, Config{})
+
+	users, err := client.Users(context.Background(), []uint{1, 2, 2, 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != 2 || users[2].Name != "User 2" {
+		t.Errorf("got users %v, want users 1 and 2", users)
+	}
+
+	// Known and unknown IDs are both cached, only new IDs are requested
+	if _, err := client.Users(context.Background(), []uint{1, 2, 3, 100}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"1,2,100", "3"}; !reflect.DeepEqual(service.calls(), want) {
+		t.Errorf("got requests %q, want %q", service.calls(), want)
+	}
+}
+
+func TestCacheExpires(t *testing.T) {
+	client, service := newTestClient(t, Config{CacheTTL: 20 * time.Millisecond})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Users(context.Background(), []uint{1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		time.Sleep(30 * time.Millisecond)
+	}
+	if got := len(service.calls()); got != 2 {
+		t.Errorf("got %d requests, want 2 once the cache expired", got)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	client, service := newTestClient(t, Config{Timeout: 20 * time.Millisecond})
+	service.set(nil, 200*time.Millisecond)
+
+	if _, err := client.Users(context.Background(), []uint{1}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want a deadline exceeded error", err)
+	}
+}
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	client, service := newTestClient(t, Config{FailureThreshold: 2, Cooldown: 50 * time.Millisecond})
+
+	// Cache user 1 before the outage
+	if _, err := client.Users(context.Background(), []uint{1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service.set(status.Error(codes.Unavailable, "connection refused"), 0)
+
+	for id := uint(2); id <= 3; id++ {
+		if _, err := client.Users(context.Background(), []uint{id}); err == nil {
+			t.Fatalf("expected an error while the user service fails")
+		}
+	}
+
+	// The breaker is open: no request is made and cached authors are still returned
+	authors, err := client.FindAuthors([]uint{1, 4})
+	if !errors.Is(err, breaker.ErrOpen) {
+		t.Errorf("got error %v, want %v", err, breaker.ErrOpen)
+	}
+	if len(authors) != 1 || authors[1].Name != "User 1" {
+		t.Errorf("got authors %v, want the cached user 1 only", authors)
+	}
+	if got := len(service.calls()); got != 3 {
+		t.Errorf("got %d requests, want 3 as the open breaker skips the call", got)
+	}
+
+	// After the cooldown a trial request goes through and closes the breaker again
+	service.set(nil, 0)
+	time.Sleep(60 * time.Millisecond)
+	users, err := client.Users(context.Background(), []uint{4})
+	if err != nil || users[4].Name != "User 4" {
+		t.Errorf("got users %v and error %v after the cooldown, want user 4", users, err)
+	}
+}
@@ -0,0 +1,27 @@
+package userclient
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	userv1 "github.com/yuhari7/backend_supervision/shared/proto/user/v1"
+)
+
+// Dial connects to the gRPC API of the user service at addr, e.g. localhost:9090.
+// Every call sends token, one of the SERVICE_TOKENS of the user service.
+func Dial(addr, token string) (userv1.UserServiceClient, *grpc.ClientConn, error) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return userv1.NewUserServiceClient(conn), conn, nil
+}
@@ -0,0 +1,8 @@
+CREATE TABLE article_views_daily (
+    article_id INT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    view_date DATE NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (article_id, view_date)
+);
+
+CREATE INDEX idx_article_views_daily_view_date ON article_views_daily (view_date);
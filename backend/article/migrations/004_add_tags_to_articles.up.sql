@@ -0,0 +1,2 @@
+ALTER TABLE articles
+ADD COLUMN tags TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE articles
+ADD COLUMN content_fingerprint BIGINT NULL;
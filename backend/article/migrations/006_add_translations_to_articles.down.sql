@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_articles_locale;
+DROP INDEX IF EXISTS idx_articles_translation_group_locale;
+
+ALTER TABLE articles
+DROP COLUMN IF EXISTS source_revision;
+
+ALTER TABLE articles
+DROP COLUMN IF EXISTS revision;
+
+ALTER TABLE articles
+DROP COLUMN IF EXISTS translation_group_id;
+
+ALTER TABLE articles
+DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,22 @@
+ALTER TABLE articles
+ADD COLUMN locale VARCHAR(10) NOT NULL DEFAULT 'id';
+
+ALTER TABLE articles
+ADD COLUMN translation_group_id INT NULL;
+
+-- Existing articles become the source of their own translation group
+UPDATE articles SET translation_group_id = id;
+
+ALTER TABLE articles
+ALTER COLUMN translation_group_id SET NOT NULL;
+
+-- revision is bumped whenever the title or content changes,
+-- source_revision records which revision of the source a translation is based on
+ALTER TABLE articles
+ADD COLUMN revision INT NOT NULL DEFAULT 1;
+
+ALTER TABLE articles
+ADD COLUMN source_revision INT NULL;
+
+CREATE UNIQUE INDEX idx_articles_translation_group_locale ON articles (translation_group_id, locale);
+CREATE INDEX idx_articles_locale ON articles (locale);
@@ -0,0 +1,5 @@
+-- ID of the user service user who wrote the article, NULL for articles written before authors were tracked
+ALTER TABLE articles
+ADD COLUMN author_id INT NULL;
+
+CREATE INDEX idx_articles_author_id ON articles (author_id);
@@ -0,0 +1,71 @@
+// Package breaker implements a circuit breaker that stops calling a failing dependency for a while.
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned instead of calling the dependency while the breaker is open
+var ErrOpen = errors.New("circuit breaker is open")
+
+// Breaker opens after a number of consecutive failures and rejects calls until the cooldown has passed.
+// The first call after the cooldown is let through as a trial, its outcome closes or reopens the breaker.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// New creates a breaker opening after threshold consecutive failures for the cooldown duration
+func New(threshold int, cooldown time.Duration) *Breaker {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return &Breaker{threshold: threshold, cooldown: cooldown}
+}
+
+// Do calls fn unless the breaker is open, recording whether it failed
+func (b *Breaker) Do(fn func() error) error {
+	if !b.allow() {
+		return ErrOpen
+	}
+	err := fn()
+	b.record(err)
+	return err
+}
+
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	// Open: only a single trial call is let through once the cooldown has passed
+	if b.trial || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if err == nil {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
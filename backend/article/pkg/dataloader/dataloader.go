@@ -0,0 +1,67 @@
+// Package dataloader batches lookups made while resolving a single request.
+package dataloader
+
+import "sync"
+
+// BatchFunc loads the values of several keys at once. Keys missing from the result resolve to the zero value.
+type BatchFunc[K comparable, V any] func(keys []K) (map[K]V, error)
+
+// Loader collects the keys passed to Load and fetches them with one call to its BatchFunc
+// the first time one of the returned thunks is called. Results are cached for the lifetime
+// of the loader, so a loader is meant to be created per request.
+type Loader[K comparable, V any] struct {
+	batch BatchFunc[K, V]
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	values  map[K]V
+	errs    map[K]error
+}
+
+// New creates a loader fetching keys with batch
+func New[K comparable, V any](batch BatchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		batch:  batch,
+		queued: make(map[K]bool),
+		values: make(map[K]V),
+		errs:   make(map[K]error),
+	}
+}
+
+// Load queues a key and returns a thunk resolving its value
+func (l *Loader[K, V]) Load(key K) func() (V, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.dispatch()
+		return l.values[key], l.errs[key]
+	}
+}
+
+// dispatch fetches every pending key, must be called with mu held
+func (l *Loader[K, V]) dispatch() {
+	if len(l.pending) == 0 {
+		return
+	}
+	keys := l.pending
+	l.pending = nil
+
+	values, err := l.batch(keys)
+	for _, key := range keys {
+		if err != nil {
+			l.errs[key] = err
+			continue
+		}
+		if value, ok := values[key]; ok {
+			l.values[key] = value
+		}
+	}
+}
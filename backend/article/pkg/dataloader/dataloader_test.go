@@ -0,0 +1,63 @@
+package dataloader
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLoaderBatchesPendingKeys(t *testing.T) {
+	var calls [][]int
+	loader := New(func(keys []int) (map[int]string, error) {
+		calls = append(calls, keys)
+		values := map[int]string{}
+		for _, key := range keys {
+			if key != 3 {
+				values[key] = string(rune('a' + key))
+			}
+		}
+		return values, nil
+	})
+
+	thunks := []func() (string, error){loader.Load(1), loader.Load(2), loader.Load(1), loader.Load(3)}
+	var got []string
+	for _, thunk := range thunks {
+		value, err := thunk()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, value)
+	}
+
+	if want := []string{"b", "c", "b", ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got values %q, want %q", got, want)
+	}
+	if want := [][]int{{1, 2, 3}}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("got batches %v, want %v", calls, want)
+	}
+
+	// Cached keys are not fetched again, new keys start a new batch
+	if _, err := loader.Load(2)(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := loader.Load(4)(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := [][]int{{1, 2, 3}, {4}}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("got batches %v, want %v", calls, want)
+	}
+}
+
+func TestLoaderReportsBatchErrors(t *testing.T) {
+	failure := errors.New("database down")
+	loader := New(func(keys []int) (map[int]string, error) {
+		return nil, failure
+	})
+
+	first, second := loader.Load(1), loader.Load(2)
+	for _, thunk := range []func() (string, error){first, second} {
+		if _, err := thunk(); !errors.Is(err, failure) {
+			t.Errorf("got error %v, want %v", err, failure)
+		}
+	}
+}
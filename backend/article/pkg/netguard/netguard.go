@@ -0,0 +1,71 @@
+// Package netguard keeps outgoing requests to user supplied URLs away from the internal network.
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedAddress is returned for addresses in the loopback, link-local, private or unique-local ranges
+var ErrBlockedAddress = errors.New("address is not publicly routable")
+
+// Resolver looks up the addresses of a host, net.DefaultResolver in production
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// Blocked reports whether ip must not be reached: loopback, link-local (e.g. the 169.254.169.254 metadata service),
+// RFC 1918 and unique-local (fc00::/7) addresses, and the unspecified address
+func Blocked(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified()
+}
+
+// CheckHost resolves host and fails when one of its addresses is blocked
+func CheckHost(ctx context.Context, resolver Resolver, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if Blocked(ip) {
+			return fmt.Errorf("%s: %w", host, ErrBlockedAddress)
+		}
+		return nil
+	}
+
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if Blocked(addr.IP) {
+			return fmt.Errorf("%s resolves to %s: %w", host, addr.IP, ErrBlockedAddress)
+		}
+	}
+	return nil
+}
+
+// Control is a net.Dialer Control hook refusing blocked addresses.
+// It runs on the address actually dialed, after DNS resolution, so a host re-pointed after CheckHost is still refused.
+func Control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || Blocked(ip) {
+		return fmt.Errorf("dialing %s: %w", address, ErrBlockedAddress)
+	}
+	return nil
+}
+
+// NewClient returns an HTTP client that only connects to public addresses, redirects included.
+// Proxies from the environment are ignored, they would be dialed instead of the target.
+func NewClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: Control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,70 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// staticResolver resolves hosts from a fixed table
+type staticResolver map[string]string
+
+func (r staticResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ip, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+}
+
+func TestBlocked(t *testing.T) {
+	for ip, want := range map[string]bool{
+		"127.0.0.1":       true,
+		"::1":             true,
+		"169.254.169.254": true,
+		"fe80::1":         true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.1.10":    true,
+		"fd12:3456::1":    true,
+		"0.0.0.0":         true,
+		"93.184.215.14":   false,
+		"2606:4700::1111": false,
+	} {
+		if got := Blocked(net.ParseIP(ip)); got != want {
+			t.Errorf("got Blocked(%s) = %v, want %v", ip, got, want)
+		}
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	resolver := staticResolver{"example.com": "93.184.215.14", "internal.example.com": "10.0.0.5"}
+
+	for host, want := range map[string]error{
+		"example.com":          nil,
+		"internal.example.com": ErrBlockedAddress,
+		"169.254.169.254":      ErrBlockedAddress,
+		"93.184.215.14":        nil,
+	} {
+		if err := CheckHost(context.Background(), resolver, host); !errors.Is(err, want) {
+			t.Errorf("got error %v for %s, want %v", err, host, want)
+		}
+	}
+	if err := CheckHost(context.Background(), resolver, "unknown.example.com"); err == nil {
+		t.Error("got no error for a host that does not resolve")
+	}
+}
+
+func TestClientRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if _, err := NewClient().Get(server.URL); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("got error %v, want %v", err, ErrBlockedAddress)
+	}
+}
@@ -0,0 +1,33 @@
+package signature
+
+import "testing"
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"type":"article.published"}`)
+	signature := Sign("secret", 1700000000, body)
+
+	// Known value, receivers in other languages compute the same HMAC over "<timestamp>.<body>"
+	if want := "sha256=da7c66ae042269ca459d5e07237e5bb8c89e534b010571bb80da802425a53db7"; signature != want {
+		t.Fatalf("got signature %q, want %q", signature, want)
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      string
+		signature string
+		want      bool
+	}{
+		{"valid", "secret", 1700000000, string(body), signature, true},
+		{"wrong secret", "other", 1700000000, string(body), signature, false},
+		{"replayed with a new timestamp", "secret", 1700000060, string(body), signature, false},
+		{"tampered body", "secret", 1700000000, `{"type":"article.deleted"}`, signature, false},
+		{"missing prefix", "secret", 1700000000, string(body), signature[len(prefix):], false},
+	}
+	for _, tt := range tests {
+		if got := Verify(tt.secret, tt.timestamp, []byte(tt.body), tt.signature); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
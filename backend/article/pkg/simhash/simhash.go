@@ -0,0 +1,61 @@
+package simhash
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+
+	"github.com/yuhari7/backend_supervision/article/pkg/tfidf"
+)
+
+// ShingleSize is the number of consecutive words hashed together
+const ShingleSize = 3
+
+// Fingerprint computes a 64-bit SimHash over word shingles of the text.
+// Texts that share most of their shingles produce fingerprints with a small Hamming distance.
+func Fingerprint(text string) uint64 {
+	words := tfidf.Tokenize(text)
+	if len(words) == 0 {
+		return 0
+	}
+
+	var vector [64]int
+	for _, shingle := range shingles(words) {
+		h := fnv.New64a()
+		h.Write([]byte(shingle))
+		sum := h.Sum64()
+
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<uint(bit)) != 0 {
+				vector[bit]++
+			} else {
+				vector[bit]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for bit := 0; bit < 64; bit++ {
+		if vector[bit] > 0 {
+			fingerprint |= 1 << uint(bit)
+		}
+	}
+	return fingerprint
+}
+
+// Similarity returns a value between 0 and 1, where 1 means identical fingerprints
+func Similarity(a, b uint64) float64 {
+	return 1 - float64(bits.OnesCount64(a^b))/64
+}
+
+func shingles(words []string) []string {
+	if len(words) <= ShingleSize {
+		return []string{strings.Join(words, " ")}
+	}
+
+	result := make([]string, 0, len(words)-ShingleSize+1)
+	for i := 0; i+ShingleSize <= len(words); i++ {
+		result = append(result, strings.Join(words[i:i+ShingleSize], " "))
+	}
+	return result
+}
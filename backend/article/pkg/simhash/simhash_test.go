@@ -0,0 +1,67 @@
+package simhash
+
+import (
+	"reflect"
+	"testing"
+)
+
+const rest = "Taman seluas dua hektare itu dilengkapi jalur sepeda, area bermain anak, dan kolam retensi " +
+	"untuk mengurangi banjir di kawasan sekitarnya."
+
+const original = "Pemerintah kota meresmikan taman baru di pusat kota pada hari Minggu. " + rest
+
+func TestFingerprintFindsNearDuplicates(t *testing.T) {
+	fingerprint := Fingerprint(original)
+	if fingerprint == 0 {
+		t.Fatal("got an empty fingerprint for a full text")
+	}
+	if Fingerprint(original) != fingerprint {
+		t.Error("got a different fingerprint for the same text")
+	}
+
+	// Case and punctuation do not change the terms, a changed word only a few shingles
+	if got := Similarity(fingerprint, Fingerprint("PEMERINTAH KOTA meresmikan taman baru, di pusat kota pada hari minggu! "+rest)); got != 1 {
+		t.Errorf("got similarity %v ignoring case and punctuation, want 1", got)
+	}
+	edited := Similarity(fingerprint, Fingerprint("Pemerintah kota meresmikan taman baru di pusat kota pada hari Sabtu. "+rest))
+	unrelated := Similarity(fingerprint, Fingerprint("Harga cabai rawit di pasar tradisional naik tajam menjelang akhir pekan karena pasokan dari petani berkurang setelah hujan deras."))
+	if edited < 0.75 || edited <= unrelated {
+		t.Errorf("got similarity %v for a small edit and %v for an unrelated text, want the edit close and above the unrelated text", edited, unrelated)
+	}
+}
+
+func TestFingerprintOfShortTexts(t *testing.T) {
+	if got := Fingerprint("di ke dan"); got != 0 {
+		t.Errorf("got fingerprint %x for stop words only, want 0", got)
+	}
+	if Fingerprint("taman kota baru") == 0 {
+		t.Error("got an empty fingerprint for a text shorter than a shingle")
+	}
+}
+
+func TestShingles(t *testing.T) {
+	got := shingles([]string{"taman", "kota", "baru", "resmi"})
+	want := []string{"taman kota baru", "kota baru resmi"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got shingles %v, want %v", got, want)
+	}
+	if got := shingles([]string{"taman", "kota"}); !reflect.DeepEqual(got, []string{"taman kota"}) {
+		t.Errorf("got shingles %v, want the words as one shingle", got)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want float64
+	}{
+		{0, 0, 1},
+		{0, ^uint64(0), 0},
+		{0xff, 0, 0.875},
+	}
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("got similarity %v for %x and %x, want %v", got, tt.a, tt.b, tt.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package tfidf
+
+import (
+	"math"
+	"sync"
+)
+
+// Index is an in-memory TF-IDF model that can be updated one document at a time
+type Index struct {
+	mu   sync.RWMutex
+	docs map[uint]map[string]int // term counts per document
+	df   map[string]int          // number of documents containing each term
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		docs: make(map[uint]map[string]int),
+		df:   make(map[string]int),
+	}
+}
+
+// Upsert adds a document to the index or replaces its previous version
+func (i *Index) Upsert(id uint, text string) {
+	counts := make(map[string]int)
+	for _, term := range Tokenize(text) {
+		counts[term]++
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(id)
+	i.docs[id] = counts
+	for term := range counts {
+		i.df[term]++
+	}
+}
+
+// Remove deletes a document from the index
+func (i *Index) Remove(id uint) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(id)
+}
+
+// Len returns the number of indexed documents
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return len(i.docs)
+}
+
+// Similar returns the cosine similarity between text and every indexed document with a non-zero score
+func (i *Index) Similar(text string) map[uint]float64 {
+	queryCounts := make(map[string]int)
+	for _, term := range Tokenize(text) {
+		queryCounts[term]++
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	query := i.weights(queryCounts)
+	queryNorm := norm(query)
+	scores := make(map[uint]float64)
+	if queryNorm == 0 {
+		return scores
+	}
+
+	for id, counts := range i.docs {
+		doc := i.weights(counts)
+		docNorm := norm(doc)
+		if docNorm == 0 {
+			continue
+		}
+
+		var dot float64
+		for term, weight := range query {
+			dot += weight * doc[term]
+		}
+		if dot > 0 {
+			scores[id] = dot / (queryNorm * docNorm)
+		}
+	}
+
+	return scores
+}
+
+func (i *Index) remove(id uint) {
+	counts, ok := i.docs[id]
+	if !ok {
+		return
+	}
+
+	for term := range counts {
+		i.df[term]--
+		if i.df[term] <= 0 {
+			delete(i.df, term)
+		}
+	}
+	delete(i.docs, id)
+}
+
+// weights converts raw term counts into sublinear TF-IDF weights
+func (i *Index) weights(counts map[string]int) map[string]float64 {
+	total := float64(len(i.docs))
+	weights := make(map[string]float64, len(counts))
+	for term, count := range counts {
+		idf := math.Log((total+1)/(float64(i.df[term])+1)) + 1
+		weights[term] = (1 + math.Log(float64(count))) * idf
+	}
+	return weights
+}
+
+func norm(weights map[string]float64) float64 {
+	var sum float64
+	for _, weight := range weights {
+		sum += weight * weight
+	}
+	return math.Sqrt(sum)
+}
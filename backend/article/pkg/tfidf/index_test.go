@@ -0,0 +1,68 @@
+package tfidf
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("The Go-compiler, dan 42 kucing di Jakarta! Go")
+	want := []string{"compiler", "kucing", "jakarta"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got terms %v, want %v without short and stop words", got, want)
+	}
+}
+
+func TestSimilarRanksSharedTerms(t *testing.T) {
+	index := NewIndex()
+	index.Upsert(1, "golang concurrency with goroutines and channels")
+	index.Upsert(2, "golang generics explained")
+	index.Upsert(3, "resep nasi goreng kampung")
+
+	scores := index.Similar("goroutines and channels in golang")
+	if _, ok := scores[3]; ok {
+		t.Errorf("got score %v for a document without shared terms, want none", scores[3])
+	}
+	if scores[1] <= scores[2] || scores[2] <= 0 {
+		t.Errorf("got scores %v, want the concurrency article first and the generics article second", scores)
+	}
+	if identical := index.Similar("golang concurrency with goroutines and channels"); math.Abs(identical[1]-1) > 1e-9 {
+		t.Errorf("got score %v for the same text, want 1", identical[1])
+	}
+
+	// Rare terms weigh more than terms found in most documents
+	if scores := index.Similar("golang generics"); scores[2] <= scores[1] {
+		t.Errorf("got scores %v, want the article sharing the rare term ranked first", scores)
+	}
+
+	if scores := index.Similar("the and di"); len(scores) != 0 {
+		t.Errorf("got scores %v for stop words only, want none", scores)
+	}
+}
+
+func TestUpsertReplacesAndRemoveForgets(t *testing.T) {
+	index := NewIndex()
+	index.Upsert(1, "golang concurrency")
+	index.Upsert(2, "golang generics")
+
+	index.Upsert(1, "resep nasi goreng")
+	if index.Len() != 2 {
+		t.Fatalf("got %d documents, want 2 after replacing one", index.Len())
+	}
+	if scores := index.Similar("concurrency"); len(scores) != 0 {
+		t.Errorf("got scores %v, want the old version of the document forgotten", scores)
+	}
+	if index.df["golang"] != 1 {
+		t.Errorf("got document frequency %d for golang, want 1", index.df["golang"])
+	}
+
+	index.Remove(2)
+	index.Remove(99)
+	if index.Len() != 1 || len(index.df) != 3 {
+		t.Errorf("got %d documents and %d terms, want only the recipe left", index.Len(), len(index.df))
+	}
+	if scores := index.Similar("golang generics"); len(scores) != 0 {
+		t.Errorf("got scores %v, want the removed document gone", scores)
+	}
+}
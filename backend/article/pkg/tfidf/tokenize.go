@@ -0,0 +1,34 @@
+package tfidf
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are common English and Indonesian words that carry no meaning for similarity
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true, "you": true,
+	"all": true, "can": true, "was": true, "one": true, "our": true, "has": true, "have": true,
+	"this": true, "that": true, "with": true, "from": true, "they": true, "will": true, "what": true,
+	"there": true, "their": true, "which": true, "when": true, "were": true, "been": true, "into": true,
+	"yang": true, "dan": true, "di": true, "ke": true, "dari": true, "ini": true, "itu": true,
+	"untuk": true, "dengan": true, "pada": true, "adalah": true, "dalam": true, "tidak": true,
+	"akan": true, "juga": true, "atau": true, "oleh": true, "sebagai": true, "karena": true,
+	"ada": true, "bisa": true, "lebih": true, "saat": true, "telah": true, "sudah": true,
+}
+
+// Tokenize splits text into lowercase terms, dropping short words and stop words
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len([]rune(field)) < 3 || stopWords[field] {
+			continue
+		}
+		terms = append(terms, field)
+	}
+	return terms
+}
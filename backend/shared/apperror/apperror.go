@@ -0,0 +1,122 @@
+// Package apperror defines the typed domain errors returned by usecases.
+// Errors carry a stable machine readable code, HTTP mapping is done by package problem.
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind classifies a domain error
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindUnprocessable
+	KindTooManyRequests
+)
+
+// Error is a domain error with a stable code and optional extension members
+type Error struct {
+	Kind       Kind
+	Code       string
+	Message    string
+	Fields     map[string]string      // per-field validation messages
+	Extensions map[string]interface{} // extra members added to the problem response
+	Err        error                  // wrapped cause, never exposed to clients
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches errors of the same kind and code, so a fresh error matches a sentinel
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+// With returns a copy of the error with an extra member added to the problem response
+func (e *Error) With(key string, value interface{}) *Error {
+	clone := *e
+	clone.Extensions = make(map[string]interface{}, len(e.Extensions)+1)
+	for k, v := range e.Extensions {
+		clone.Extensions[k] = v
+	}
+	clone.Extensions[key] = value
+	return &clone
+}
+
+// Wrap returns a copy of the error that wraps the given cause
+func (e *Error) Wrap(err error) *Error {
+	clone := *e
+	clone.Err = err
+	return &clone
+}
+
+func newError(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Validation reports invalid input, fields holds an optional message per field
+func Validation(code, message string, fields map[string]string) *Error {
+	err := newError(KindValidation, code, message)
+	err.Fields = fields
+	return err
+}
+
+// Unauthorized reports missing or invalid credentials
+func Unauthorized(code, message string) *Error {
+	return newError(KindUnauthorized, code, message)
+}
+
+// Forbidden reports an authenticated caller that is not allowed to perform the action
+func Forbidden(code, message string) *Error {
+	return newError(KindForbidden, code, message)
+}
+
+// NotFound reports a missing resource
+func NotFound(code, message string) *Error {
+	return newError(KindNotFound, code, message)
+}
+
+// Conflict reports a request that conflicts with the current state of a resource
+func Conflict(code, message string) *Error {
+	return newError(KindConflict, code, message)
+}
+
+// Unprocessable reports well-formed input that breaks a business rule
+func Unprocessable(code, message string) *Error {
+	return newError(KindUnprocessable, code, message)
+}
+
+// TooManyRequests reports a caller that has to wait before trying again
+func TooManyRequests(code, message string) *Error {
+	return newError(KindTooManyRequests, code, message)
+}
+
+// Internal wraps an unexpected error
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Code: "internal_error", Message: "internal server error", Err: err}
+}
+
+// KindOf returns the kind of err, KindInternal when it is not an *Error
+func KindOf(err error) Kind {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Kind
+	}
+	return KindInternal
+}
@@ -0,0 +1,42 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var errNotFound = NotFound("article_not_found", "article not found")
+
+func TestCopiesMatchTheSentinel(t *testing.T) {
+	cause := errors.New("connection refused")
+	tests := []error{
+		errNotFound.With("id", 7),
+		errNotFound.Wrap(cause),
+		fmt.Errorf("loading article: %w", errNotFound),
+	}
+	for _, err := range tests {
+		if !errors.Is(err, errNotFound) {
+			t.Errorf("got %v not matching the sentinel", err)
+		}
+	}
+
+	if errors.Is(Conflict("article_not_found", "article not found"), errNotFound) {
+		t.Error("got an error of another kind matching the sentinel")
+	}
+	if !errors.Is(errNotFound.Wrap(cause), cause) {
+		t.Error("got the wrapped cause lost")
+	}
+	if errNotFound.With("id", 7); errNotFound.Extensions != nil {
+		t.Errorf("got extensions %v added to the sentinel, want them on the copy only", errNotFound.Extensions)
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	if got := KindOf(fmt.Errorf("loading article: %w", errNotFound)); got != KindNotFound {
+		t.Errorf("got kind %v, want KindNotFound", got)
+	}
+	if got := KindOf(errors.New("connection refused")); got != KindInternal {
+		t.Errorf("got kind %v, want KindInternal", got)
+	}
+}
@@ -0,0 +1,27 @@
+package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+// Claims are the claims of an access token issued by the user service
+type Claims struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+	RoleID uint   `json:"role_id"`
+	// Role and Permissions are empty when the user's role no longer exists
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	// TokenVersion is the user's token version at issue time, the token is refused once it was raised
+	TokenVersion uint `json:"ver"`
+	jwt.RegisteredClaims
+}
+
+// Principal returns the user the token was issued to
+func (c *Claims) Principal() *Principal {
+	return &Principal{
+		UserID:      c.UserID,
+		Email:       c.Email,
+		RoleID:      c.RoleID,
+		Role:        c.Role,
+		Permissions: c.Permissions,
+	}
+}
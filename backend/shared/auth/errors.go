@@ -0,0 +1,11 @@
+package auth
+
+import "github.com/yuhari7/backend_supervision/shared/apperror"
+
+// Errors returned by the middleware, rendered by the problem error handler
+var (
+	ErrMissingToken           = apperror.Unauthorized("missing_token", "missing authorization header")
+	ErrInvalidTokenFormat     = apperror.Unauthorized("invalid_token_format", "invalid token format")
+	ErrInvalidToken           = apperror.Unauthorized("invalid_token", "invalid or expired token")
+	ErrInsufficientPrivileges = apperror.Forbidden("insufficient_privileges", "insufficient privileges")
+)
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/shared/apperror"
+	"github.com/yuhari7/backend_supervision/shared/jwks"
+	userv1 "github.com/yuhari7/backend_supervision/shared/proto/user/v1"
+)
+
+// JWKSConfig configures NewJWKSAuthenticator, zero durations fall back to the defaults
+type JWKSConfig struct {
+	Verifier *jwks.Verifier
+	Users    userv1.UserServiceClient // answers ValidateToken, the connection must send a service token
+
+	CacheTTL time.Duration // how long an answer of the user service is reused, defaults to 5s like the session cache of the user service
+	Timeout  time.Duration // per ValidateToken call, defaults to 2s
+}
+
+type validation struct {
+	valid     bool
+	expiresAt time.Time
+}
+
+// NewJWKSAuthenticator verifies tokens with the public keys served by the user service, for services that only consume tokens.
+// A valid signature is not enough: the user service confirms the user is active and the token version current,
+// its answers are cached for a short time so revocations apply within CacheTTL.
+func NewJWKSAuthenticator(config JWKSConfig) Authenticator {
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = 5 * time.Second
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 2 * time.Second
+	}
+
+	var mu sync.Mutex
+	cache := make(map[string]validation)
+
+	return AuthenticatorFunc(func(token string) (*Claims, error) {
+		claims := &Claims{}
+		if _, err := config.Verifier.Parse(token, claims); err != nil {
+			return nil, ErrInvalidToken.Wrap(err)
+		}
+
+		now := time.Now()
+		mu.Lock()
+		entry, ok := cache[token]
+		mu.Unlock()
+		if !ok || !now.Before(entry.expiresAt) {
+			ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+			defer cancel()
+			res, err := config.Users.ValidateToken(ctx, &userv1.ValidateTokenRequest{AccessToken: token})
+			if err != nil {
+				// Without an answer the token may have been revoked, so it is not accepted
+				return nil, apperror.Internal(fmt.Errorf("validating token with the user service: %w", err))
+			}
+
+			entry = validation{valid: res.GetValid(), expiresAt: now.Add(config.CacheTTL)}
+			mu.Lock()
+			// Expired answers are dropped on the way so the cache does not grow with every token seen
+			for cached, e := range cache {
+				if !now.Before(e.expiresAt) {
+					delete(cache, cached)
+				}
+			}
+			cache[token] = entry
+			mu.Unlock()
+		}
+
+		if !entry.valid {
+			return nil, ErrInvalidToken
+		}
+		return claims, nil
+	})
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+
+	"github.com/yuhari7/backend_supervision/shared/apperror"
+	"github.com/yuhari7/backend_supervision/shared/jwks"
+	userv1 "github.com/yuhari7/backend_supervision/shared/proto/user/v1"
+)
+
+// fakeUserService answers ValidateToken from a set of revoked tokens, counting the calls
+type fakeUserService struct {
+	userv1.UserServiceClient
+	revoked map[string]bool
+	err     error
+	calls   int
+}
+
+func (s *fakeUserService) ValidateToken(ctx context.Context, in *userv1.ValidateTokenRequest, opts ...grpc.CallOption) (*userv1.ValidateTokenResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &userv1.ValidateTokenResponse{Valid: !s.revoked[in.GetAccessToken()]}, nil
+}
+
+// newJWKSAuthenticator serves a single key and returns an authenticator backed by users, with a signing function
+func newJWKSAuthenticator(t *testing.T, users *fakeUserService) (Authenticator, func(userID uint) string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jwks.Set{Keys: []jwks.Key{jwks.NewKey("test", public)}})
+	}))
+	t.Cleanup(server.Close)
+
+	authenticator := NewJWKSAuthenticator(JWKSConfig{
+		Verifier: jwks.NewVerifier(jwks.VerifierConfig{URL: server.URL}),
+		Users:    users,
+		CacheTTL: time.Minute,
+	})
+	sign := func(userID uint) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, &Claims{
+			UserID:           userID,
+			TokenVersion:     1,
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+		})
+		token.Header["kid"] = "test"
+		signed, err := token.SignedString(private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	return authenticator, sign
+}
+
+func TestJWKSAuthenticatorAsksUserService(t *testing.T) {
+	users := &fakeUserService{revoked: map[string]bool{}}
+	authenticator, sign := newJWKSAuthenticator(t, users)
+	valid, revoked := sign(1), sign(2)
+	users.revoked[revoked] = true
+
+	claims, err := authenticator.Authenticate(valid)
+	if err != nil || claims.UserID != 1 {
+		t.Fatalf("got claims %+v and error %v, want the claims of user 1", claims, err)
+	}
+	if _, err := authenticator.Authenticate(revoked); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("got error %v for a revoked token, want %v", err, ErrInvalidToken)
+	}
+	if _, err := authenticator.Authenticate("forged"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("got error %v for a forged token, want %v", err, ErrInvalidToken)
+	}
+	if users.calls != 2 {
+		t.Errorf("got %d calls to the user service, want 2 as forged tokens are refused first", users.calls)
+	}
+
+	// Answers are cached, a revocation is noticed once the cache expires
+	users.revoked[valid] = true
+	if _, err := authenticator.Authenticate(valid); err != nil || users.calls != 2 {
+		t.Errorf("got error %v and %d calls, want the cached answer", err, users.calls)
+	}
+}
+
+func TestJWKSAuthenticatorFailsClosed(t *testing.T) {
+	users := &fakeUserService{err: errors.New("connection refused")}
+	authenticator, sign := newJWKSAuthenticator(t, users)
+
+	_, err := authenticator.Authenticate(sign(1))
+	if err == nil || apperror.KindOf(err) != apperror.KindInternal {
+		t.Errorf("got error %v while the user service is down, want an internal error", err)
+	}
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"log"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Authenticator verifies an access token and returns its claims.
+// Tokens that cannot be used are reported with ErrInvalidToken or another *apperror.Error.
+type Authenticator interface {
+	Authenticate(token string) (*Claims, error)
+}
+
+// AuthenticatorFunc adapts a function to an Authenticator
+type AuthenticatorFunc func(token string) (*Claims, error)
+
+func (f AuthenticatorFunc) Authenticate(token string) (*Claims, error) {
+	return f(token)
+}
+
+// BearerToken extracts the token of an "Authorization: Bearer <token>" header
+func BearerToken(header string) (string, error) {
+	if header == "" {
+		return "", ErrMissingToken
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	token = strings.TrimSpace(token)
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", ErrInvalidTokenFormat
+	}
+	return token, nil
+}
+
+// Middleware authenticates requests with the bearer token and stores the principal in the request context,
+// where handlers find it with PrincipalFrom
+func Middleware(authenticator Authenticator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, err := BearerToken(c.Request().Header.Get(echo.HeaderAuthorization))
+			if err != nil {
+				return err
+			}
+
+			claims, err := authenticator.Authenticate(token)
+			if err != nil {
+				log.Printf("Rejected token %s: %v", Redact(token), err)
+				return err
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(WithPrincipal(req.Context(), claims.Principal())))
+			return next(c)
+		}
+	}
+}
+
+// RequireRole lets through principals having one of the roles, it must run after Middleware
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return require(func(principal *Principal) bool {
+		return principal.HasRole(roles...)
+	})
+}
+
+// RequirePermission lets through principals granted every one of the permissions, it must run after Middleware
+func RequirePermission(permissions ...string) echo.MiddlewareFunc {
+	return require(func(principal *Principal) bool {
+		for _, permission := range permissions {
+			if !principal.Can(permission) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func require(allowed func(*Principal) bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, ok := PrincipalFrom(c.Request().Context())
+			if !ok {
+				return ErrMissingToken
+			}
+			if !allowed(principal) {
+				return ErrInsufficientPrivileges
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Package auth authenticates requests with access tokens of the user service and checks what the caller may do
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// Principal is the user a request is made on behalf of
+type Principal struct {
+	UserID      uint
+	Email       string
+	RoleID      uint
+	Role        string
+	Permissions []string
+}
+
+// HasRole reports whether the principal has one of the roles
+func (p *Principal) HasRole(roles ...string) bool {
+	return slices.Contains(roles, p.Role)
+}
+
+// Can reports whether the principal was granted the permission
+func (p *Principal) Can(permission string) bool {
+	return slices.Contains(p.Permissions, permission)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal of an authenticated request, false when the request was not authenticated
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
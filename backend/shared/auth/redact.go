@@ -0,0 +1,11 @@
+package auth
+
+import "strconv"
+
+// Redact shortens a token for logs, keeping enough of its start to tell tokens apart but not to use one
+func Redact(token string) string {
+	if len(token) <= 16 {
+		return "[redacted]"
+	}
+	return token[:8] + "…[" + strconv.Itoa(len(token)) + " chars]"
+}
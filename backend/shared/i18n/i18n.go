@@ -0,0 +1,192 @@
+// Package i18n translates validation errors and common messages using catalog files per locale.
+package i18n
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed locales/*.yaml
+var embeddedLocales embed.FS
+
+// indexSuffix matches the index of a slice element in a field name, such as tags[0]
+var indexSuffix = regexp.MustCompile(`\[\d+\]$`)
+
+// Catalog holds the messages of a single locale
+type Catalog struct {
+	Fields     map[string]string `yaml:"fields"`
+	Validation map[string]string `yaml:"validation"`
+	Messages   map[string]string `yaml:"messages"`
+}
+
+// Translator resolves messages from the catalog matching the request locale
+type Translator struct {
+	catalogs map[string]Catalog
+	fallback string
+}
+
+// New creates a translator from the catalogs embedded in this package
+func New(fallback string) (*Translator, error) {
+	locales, err := fs.Sub(embeddedLocales, "locales")
+	if err != nil {
+		return nil, err
+	}
+	return NewFromFS(locales, fallback)
+}
+
+// NewFromFS creates a translator from every <locale>.yaml file at the root of fsys
+func NewFromFS(fsys fs.FS, fallback string) (*Translator, error) {
+	files, err := fs.Glob(fsys, "*.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	catalogs := make(map[string]Catalog, len(files))
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		var catalog Catalog
+		if err := yaml.Unmarshal(data, &catalog); err != nil {
+			return nil, fmt.Errorf("failed to parse catalog %s: %w", file, err)
+		}
+		catalogs[strings.TrimSuffix(path.Base(file), ".yaml")] = catalog
+	}
+
+	if _, ok := catalogs[fallback]; !ok {
+		return nil, fmt.Errorf("no catalog for fallback locale %q", fallback)
+	}
+	return &Translator{catalogs: catalogs, fallback: fallback}, nil
+}
+
+// MustNew is like New but panics when the embedded catalogs cannot be loaded
+func MustNew(fallback string) *Translator {
+	translator, err := New(fallback)
+	if err != nil {
+		panic(err)
+	}
+	return translator
+}
+
+// Negotiate picks the best supported locale from an Accept-Language header value
+func (t *Translator) Negotiate(acceptLanguage string) string {
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if tag == "" {
+			continue
+		}
+
+		tag = strings.ToLower(tag)
+		if _, ok := t.catalogs[tag]; ok {
+			return tag
+		}
+
+		// Match "en-US" against the "en" catalog
+		base := strings.SplitN(tag, "-", 2)[0]
+		if _, ok := t.catalogs[base]; ok {
+			return base
+		}
+	}
+	return t.fallback
+}
+
+// FromRequest returns the locale requested through the Accept-Language header
+func (t *Translator) FromRequest(r *http.Request) string {
+	return t.Negotiate(r.Header.Get("Accept-Language"))
+}
+
+// Message returns the translated message for key, or the key itself when it is unknown
+func (t *Translator) Message(locale, key string) string {
+	if message, ok := t.lookup(locale, func(c Catalog) map[string]string { return c.Messages }, key); ok {
+		return message
+	}
+	return key
+}
+
+// MessageOr returns the translated message for key, or fallback when no catalog defines it
+func (t *Translator) MessageOr(locale, key, fallback string) string {
+	if message, ok := t.lookup(locale, func(c Catalog) map[string]string { return c.Messages }, key); ok {
+		return message
+	}
+	return fallback
+}
+
+// ValidationErrors translates validator errors into a message per field.
+// Errors that are not validator.ValidationErrors are returned under the "error" key.
+func (t *Translator) ValidationErrors(locale string, err error) map[string]string {
+	messages := make(map[string]string)
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		messages["error"] = err.Error()
+		return messages
+	}
+
+	for _, fieldErr := range validationErrors {
+		field := fieldErr.Field()
+		if _, exists := messages[field]; exists {
+			continue
+		}
+		messages[field] = t.validationMessage(locale, fieldErr)
+	}
+	return messages
+}
+
+func (t *Translator) validationMessage(locale string, fieldErr validator.FieldError) string {
+	tag := fieldErr.Tag()
+	switch fieldErr.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if tag == "min" || tag == "max" {
+			tag += "_items"
+		}
+	}
+	return t.FieldMessage(locale, fieldErr.Field(), tag, fieldErr.Param())
+}
+
+// FieldMessage translates a failed validation rule of a field with the validation templates.
+// A field specific message such as "title.min" wins over the generic "min" message.
+func (t *Translator) FieldMessage(locale, field, rule, param string) string {
+	fieldKey := indexSuffix.ReplaceAllString(field, "")
+	validation := func(c Catalog) map[string]string { return c.Validation }
+
+	template, ok := t.lookup(locale, validation, fieldKey+"."+rule)
+	if !ok {
+		template, ok = t.lookup(locale, validation, rule)
+	}
+	if !ok {
+		template, _ = t.lookup(locale, validation, "default")
+	}
+
+	label, ok := t.lookup(locale, func(c Catalog) map[string]string { return c.Fields }, fieldKey)
+	if !ok {
+		label = field
+	}
+
+	return strings.NewReplacer("{field}", label, "{param}", param).Replace(template)
+}
+
+// lookup finds key in the locale catalog, falling back to the fallback locale catalog
+func (t *Translator) lookup(locale string, section func(Catalog) map[string]string, key string) (string, bool) {
+	for _, candidate := range []string{locale, t.fallback} {
+		catalog, ok := t.catalogs[candidate]
+		if !ok {
+			continue
+		}
+		if message, ok := section(catalog)[key]; ok {
+			return message, true
+		}
+	}
+	return "", false
+}
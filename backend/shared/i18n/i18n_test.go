@@ -0,0 +1,78 @@
+package i18n
+
+import (
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func newTranslator(t *testing.T) *Translator {
+	t.Helper()
+	translator, err := NewFromFS(fstest.MapFS{
+		"id.yaml":    {Data: []byte("messages:\n  article_not_found: Artikel tidak ditemukan\n  invalid_request: Permintaan tidak valid\n")},
+		"en.yaml":    {Data: []byte("messages:\n  article_not_found: Article not found\n")},
+		"pt-br.yaml": {Data: []byte("messages:\n  article_not_found: Artigo não encontrado\n")},
+	}, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return translator
+}
+
+func TestNegotiate(t *testing.T) {
+	translator := newTranslator(t)
+
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"", "id"},
+		{"en", "en"},
+		{"EN-us", "en"},
+		{"pt-BR", "pt-br"},
+		{"pt-PT", "id"},
+		{"fr-FR, fr;q=0.9, en;q=0.8", "en"},
+		{" , ;q=0.5, en-GB;q=0.7", "en"},
+		{"de, ja", "id"},
+	}
+	for _, tt := range tests {
+		if got := translator.Negotiate(tt.acceptLanguage); got != tt.want {
+			t.Errorf("got locale %q for %q, want %q", got, tt.acceptLanguage, tt.want)
+		}
+	}
+
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	if got := translator.FromRequest(request); got != "en" {
+		t.Errorf("got locale %q from the request, want en", got)
+	}
+}
+
+func TestMessageFallsBack(t *testing.T) {
+	translator := newTranslator(t)
+
+	if got := translator.Message("en", "article_not_found"); got != "Article not found" {
+		t.Errorf("got %q, want the English message", got)
+	}
+	if got := translator.Message("en", "invalid_request"); got != "Permintaan tidak valid" {
+		t.Errorf("got %q, want the message of the fallback locale", got)
+	}
+	if got := translator.Message("en", "unknown_key"); got != "unknown_key" {
+		t.Errorf("got %q, want the key itself", got)
+	}
+	if got := translator.MessageOr("en", "unknown_key", "fallback"); got != "fallback" {
+		t.Errorf("got %q, want the given fallback", got)
+	}
+}
+
+func TestFallbackLocaleIsRequired(t *testing.T) {
+	if _, err := NewFromFS(fstest.MapFS{"en.yaml": {Data: []byte("messages: {}\n")}}, "id"); err == nil {
+		t.Error("expected an error without a catalog for the fallback locale")
+	}
+	// The embedded catalogs have one for both locales the services use
+	for _, fallback := range []string{"id", "en"} {
+		if _, err := New(fallback); err != nil {
+			t.Errorf("unexpected error for %s: %v", fallback, err)
+		}
+	}
+}
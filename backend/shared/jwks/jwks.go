@@ -0,0 +1,57 @@
+// Package jwks publishes and consumes the public keys access tokens are signed with, as a JSON Web Key Set
+package jwks
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// Algorithm is the JWT signing algorithm of every published key
+const Algorithm = "EdDSA"
+
+// Key is an Ed25519 public key in JWK form (RFC 8037)
+type Key struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+}
+
+// Set is a JSON Web Key Set, as served at /.well-known/jwks.json
+type Set struct {
+	Keys []Key `json:"keys"`
+}
+
+// NewKey describes an Ed25519 public key identified by kid
+func NewKey(kid string, public ed25519.PublicKey) Key {
+	return Key{
+		KeyType:   "OKP",
+		Curve:     "Ed25519",
+		X:         base64.RawURLEncoding.EncodeToString(public),
+		KeyID:     kid,
+		Algorithm: Algorithm,
+		Use:       "sig",
+	}
+}
+
+// PublicKey decodes the key, failing for anything but an Ed25519 signing key
+func (k Key) PublicKey() (ed25519.PublicKey, error) {
+	if k.KeyType != "OKP" || k.Curve != "Ed25519" {
+		return nil, fmt.Errorf("unsupported key type %s %s", k.KeyType, k.Curve)
+	}
+	if k.Use != "" && k.Use != "sig" {
+		return nil, fmt.Errorf("key %s is not a signing key", k.KeyID)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key %s: %w", k.KeyID, err)
+	}
+	if len(x) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid key " + k.KeyID + ": wrong size")
+	}
+	return ed25519.PublicKey(x), nil
+}
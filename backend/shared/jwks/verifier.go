@@ -0,0 +1,124 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrUnknownKey is returned for tokens signed with a key the key set does not contain
+var ErrUnknownKey = errors.New("token signed with an unknown key")
+
+// VerifierConfig configures a Verifier, zero values fall back to the defaults
+type VerifierConfig struct {
+	URL        string        // where the key set is served, e.g. http://localhost:8080/.well-known/jwks.json
+	CacheTTL   time.Duration // how long the key set is used before it is fetched again, defaults to 5m
+	MinRefresh time.Duration // shortest time between two fetches triggered by an unknown key, defaults to 10s
+
+	HTTPClient *http.Client
+}
+
+// Verifier checks access tokens against the public keys fetched from a JWKS endpoint.
+// A token signed with a key it does not know yet makes it fetch the set again, so rotated keys are picked up.
+type Verifier struct {
+	config VerifierConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]ed25519.PublicKey
+	fetchedAt time.Time
+	triedAt   time.Time
+}
+
+// NewVerifier creates a verifier for the key set at config.URL, nothing is fetched before the first token
+func NewVerifier(config VerifierConfig) *Verifier {
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = 5 * time.Minute
+	}
+	if config.MinRefresh <= 0 {
+		config.MinRefresh = 10 * time.Second
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &Verifier{config: config, client: client}
+}
+
+// Parse verifies the signature and expiry of an EdDSA signed token and decodes its claims
+func (v *Verifier) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, v.Keyfunc, jwt.WithValidMethods([]string{Algorithm}))
+}
+
+// Keyfunc looks up the public key named by the kid header of a token
+func (v *Verifier) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrUnknownKey
+	}
+	return v.Key(kid)
+}
+
+// Key returns the public key with the given ID, fetching the key set when it is stale or lacks the key
+func (v *Verifier) Key(kid string) (ed25519.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	key, ok := v.keys[kid]
+	stale := time.Since(v.fetchedAt) >= v.config.CacheTTL
+	if ok && !stale {
+		return key, nil
+	}
+	if !stale && time.Since(v.triedAt) < v.config.MinRefresh {
+		return nil, ErrUnknownKey
+	}
+
+	// Keys already known keep working while the endpoint cannot be reached
+	v.triedAt = time.Now()
+	if err := v.fetch(); err != nil {
+		log.Println("Failed to fetch JWKS:", err)
+		if ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUnknownKey, err)
+	}
+
+	if key, ok = v.keys[kid]; !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// fetch replaces the cached keys with the current key set, keys that cannot be decoded are skipped
+func (v *Verifier) fetch() error {
+	res, err := v.client.Get(v.config.URL)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("JWKS endpoint responded with status %d", res.StatusCode)
+	}
+
+	var set Set
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]ed25519.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if key, err := jwk.PublicKey(); err == nil {
+			keys[jwk.KeyID] = key
+		}
+	}
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	return nil
+}
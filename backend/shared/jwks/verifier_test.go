@@ -0,0 +1,114 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// keyServer serves a key set that tests can rotate, counting the fetches
+type keyServer struct {
+	mu      sync.Mutex
+	set     Set
+	fetches int
+}
+
+func (s *keyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetches++
+	_ = json.NewEncoder(w).Encode(s.set)
+}
+
+func (s *keyServer) publish(keys ...Key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set = Set{Keys: keys}
+}
+
+func newKey(t *testing.T, kid string) (Key, ed25519.PrivateKey) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewKey(kid, public), private
+}
+
+func sign(t *testing.T, kid string, key ed25519.PrivateKey) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{"sub": "1", "exp": time.Now().Add(time.Minute).Unix()})
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestVerifierFollowsKeyRotation(t *testing.T) {
+	first, firstPrivate := newKey(t, "first")
+	second, secondPrivate := newKey(t, "second")
+	keys := &keyServer{}
+	keys.publish(first)
+	server := httptest.NewServer(keys)
+	defer server.Close()
+	verifier := NewVerifier(VerifierConfig{URL: server.URL, MinRefresh: time.Nanosecond})
+
+	for i := 0; i < 2; i++ {
+		if _, err := verifier.Parse(sign(t, "first", firstPrivate), jwt.MapClaims{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if keys.fetches != 1 {
+		t.Errorf("got %d fetches, want the key set cached", keys.fetches)
+	}
+
+	// A token signed with the next key makes the verifier fetch the rotated set, the old key stays valid during the overlap
+	keys.publish(first, second)
+	for kid, key := range map[string]ed25519.PrivateKey{"second": secondPrivate, "first": firstPrivate} {
+		if _, err := verifier.Parse(sign(t, kid, key), jwt.MapClaims{}); err != nil {
+			t.Errorf("unexpected error for key %s: %v", kid, err)
+		}
+	}
+	if keys.fetches != 2 {
+		t.Errorf("got %d fetches, want one more for the new key", keys.fetches)
+	}
+}
+
+func TestVerifierRejectsForeignTokens(t *testing.T) {
+	known, _ := newKey(t, "known")
+	_, otherPrivate := newKey(t, "other")
+	keys := &keyServer{}
+	keys.publish(known)
+	server := httptest.NewServer(keys)
+	defer server.Close()
+	verifier := NewVerifier(VerifierConfig{URL: server.URL})
+
+	// A key under a published kid but not matching it, and a token signed with a shared secret
+	forged := sign(t, "known", otherPrivate)
+	hmac, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "1"}).SignedString([]byte("secret"))
+	for name, token := range map[string]string{"forged": forged, "hmac": hmac} {
+		if _, err := verifier.Parse(token, jwt.MapClaims{}); err == nil {
+			t.Errorf("expected the %s token to be rejected", name)
+		}
+	}
+
+	// Unknown keys do not trigger another fetch within MinRefresh of the last one
+	for i := 0; i < 3; i++ {
+		if _, err := verifier.Parse(sign(t, "unknown", otherPrivate), jwt.MapClaims{}); !errors.Is(err, ErrUnknownKey) {
+			t.Errorf("got error %v, want %v", err, ErrUnknownKey)
+		}
+	}
+	if keys.fetches != 1 {
+		t.Errorf("got %d fetches, want unknown keys rate limited", keys.fetches)
+	}
+}
@@ -0,0 +1,281 @@
+// Package openapi builds OpenAPI 3.1 documents from the routes and DTO structs of a service.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yuhari7/backend_supervision/shared/problem"
+)
+
+// Version is the OpenAPI version of the generated documents
+const Version = "3.1.0"
+
+// BearerAuth is the name of the JWT bearer security scheme
+const BearerAuth = "bearerAuth"
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+
+	pathParams map[string]Parameter
+	types      map[string]reflect.Type
+}
+
+// Info describes the documented API
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Components holds the reusable schemas and security schemes
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how requests authenticate
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// PathItem maps lowercase HTTP methods to the operations of a path
+type PathItem map[string]*Operation
+
+// Operation describes a single route
+type Operation struct {
+	OperationID string                `json:"operationId,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter describes a path or query parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the JSON body of a request
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response describes a response of an operation
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a request or response body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Route documents a route, the request and response values are only used for their types
+type Route struct {
+	Summary  string
+	Tags     []string
+	Query    []Parameter
+	Body     interface{}
+	Response interface{}
+	Status   int   // success status, defaults to 200
+	Errors   []int // documented problem responses
+	Secured  bool
+}
+
+// New creates an empty document
+func New(title, version string) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: title, Version: version},
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+		},
+		pathParams: map[string]Parameter{},
+		types:      map[string]reflect.Type{},
+	}
+	// Every error response is an RFC 7807 problem
+	doc.SchemaOf(problem.Problem{})
+	return doc
+}
+
+// PathParam sets the schema used for every path parameter with the given name
+func (d *Document) PathParam(name, description string, schema *Schema) {
+	d.pathParams[name] = Parameter{Name: name, In: "path", Description: description, Required: true, Schema: schema}
+}
+
+// Add documents the route registered with Echo under method and path
+func (d *Document) Add(method, path string, route Route) {
+	op := &Operation{
+		OperationID: operationID(method, path),
+		Summary:     route.Summary,
+		Tags:        route.Tags,
+		Responses:   map[string]*Response{},
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		name := segment[1:]
+		param, ok := d.pathParams[name]
+		if !ok {
+			param = Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}}
+		}
+		op.Parameters = append(op.Parameters, param)
+	}
+	op.Parameters = append(op.Parameters, route.Query...)
+
+	if route.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: d.SchemaOf(route.Body)}},
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	if route.Response != nil {
+		success.Content = map[string]*MediaType{"application/json": {Schema: d.SchemaOf(route.Response)}}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+
+	errorStatuses := route.Errors
+	if route.Secured {
+		op.Security = []map[string][]string{{BearerAuth: {}}}
+		errorStatuses = append(errorStatuses, http.StatusUnauthorized, http.StatusForbidden)
+		d.Components.SecuritySchemes = map[string]*SecurityScheme{
+			BearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		}
+	}
+	for _, errorStatus := range append(errorStatuses, http.StatusInternalServerError) {
+		op.Responses[strconv.Itoa(errorStatus)] = &Response{
+			Description: http.StatusText(errorStatus),
+			Content:     map[string]*MediaType{problem.ContentType: {Schema: Ref("Problem")}},
+		}
+	}
+
+	key := ToOpenAPIPath(path)
+	item, ok := d.Paths[key]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[key] = item
+	}
+	(*item)[strings.ToLower(method)] = op
+}
+
+// Operation returns the operation documented for method and Echo path, or nil
+func (d *Document) Operation(method, path string) *Operation {
+	item, ok := d.Paths[ToOpenAPIPath(path)]
+	if !ok {
+		return nil
+	}
+	return (*item)[strings.ToLower(method)]
+}
+
+// Operations returns the method and Echo path of every documented operation, sorted
+func (d *Document) Operations() []string {
+	var operations []string
+	for path, item := range d.Paths {
+		for method := range *item {
+			operations = append(operations, strings.ToUpper(method)+" "+ToEchoPath(path))
+		}
+	}
+	sort.Strings(operations)
+	return operations
+}
+
+// JSON returns the document encoded as JSON
+func (d *Document) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// QueryParam describes an optional query parameter
+func QueryParam(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// RequiredQueryParam describes a query parameter that must be present
+func RequiredQueryParam(name, description string, schema *Schema) Parameter {
+	param := QueryParam(name, description, schema)
+	param.Required = true
+	return param
+}
+
+// ToOpenAPIPath converts an Echo path such as /articles/:id to /articles/{id}
+func ToOpenAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// ToEchoPath converts an OpenAPI path such as /articles/{id} to /articles/:id
+func ToEchoPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = ":" + segment[1:len(segment)-1]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID builds an identifier such as getApiArticlesById from the method and path
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, ":") {
+			b.WriteString("By")
+			segment = segment[1:]
+		}
+		b.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+	return b.String()
+}
+
+// schemaName returns the component name of a named type, flattening generic type arguments
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+	open := strings.Index(name, "[")
+	if open < 0 {
+		return name
+	}
+
+	base := name[:open]
+	for _, arg := range strings.Split(strings.TrimSuffix(name[open+1:], "]"), ",") {
+		if dot := strings.LastIndex(arg, "."); dot >= 0 {
+			arg = arg[dot+1:]
+		}
+		base += "Of" + arg
+	}
+	return base
+}
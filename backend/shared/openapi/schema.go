@@ -0,0 +1,292 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bcp47Pattern is a loose pattern for the language tags accepted by the bcp47_language_tag validation
+const bcp47Pattern = `^[A-Za-z]{2,8}(-[A-Za-z0-9]{1,8})*$`
+
+// Schema is the subset of JSON Schema used by the generated documents
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Nullable             bool               `json:"-"` // rendered as a "null" type in OpenAPI 3.1
+	OmitEmpty            bool               `json:"-"` // the zero value skips every other constraint
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// MarshalJSON renders nullable schemas the OpenAPI 3.1 way
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	if s.Nullable && s.Ref != "" {
+		return json.Marshal(map[string]interface{}{
+			"anyOf": []interface{}{map[string]string{"$ref": s.Ref}, map[string]string{"type": "null"}},
+		})
+	}
+
+	out := struct {
+		plain
+		Type interface{} `json:"type,omitempty"`
+	}{plain: plain(s)}
+	if s.Type != "" {
+		out.Type = s.Type
+		if s.Nullable {
+			out.Type = []string{s.Type, "null"}
+		}
+	}
+	return json.Marshal(out)
+}
+
+// Ref returns a schema referring to a component schema
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// Integer returns an integer schema with an optional minimum
+func Integer(minimum ...float64) *Schema {
+	s := &Schema{Type: "integer"}
+	if len(minimum) > 0 {
+		s.Minimum = &minimum[0]
+	}
+	return s
+}
+
+// String returns a string schema
+func String() *Schema {
+	return &Schema{Type: "string"}
+}
+
+// SchemaOf returns the schema of the type of v, registering named structs as components
+func (d *Document) SchemaOf(v interface{}) *Schema {
+	return d.schemaFor(reflect.TypeOf(v))
+}
+
+// QueryParams describes the fields of a struct bound from the query string through `query` tags
+func (d *Document) QueryParams(v interface{}) []Parameter {
+	t := reflect.TypeOf(v)
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("query")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		schema := d.schemaFor(field.Type)
+		param := QueryParam(name, "", schema)
+		param.Required = applyRules(schema, field.Type, field.Tag.Get("validate"))
+		params = append(params, param)
+	}
+	return params
+}
+
+func (d *Document) schemaFor(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		schema := *d.schemaFor(t.Elem())
+		schema.Nullable = true
+		return &schema
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.objectSchema(t)
+		}
+		return d.component(t)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: d.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaFor(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Integer(0)
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{}
+	}
+}
+
+// component registers a named struct under components/schemas and returns a reference to it.
+// Types from different packages sharing a name are prefixed with their package name.
+func (d *Document) component(t reflect.Type) *Schema {
+	name := schemaName(t)
+	if existing, ok := d.types[name]; ok && existing != t {
+		pkg := path.Base(t.PkgPath())
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	if _, ok := d.types[name]; ok {
+		return Ref(name)
+	}
+
+	// Register the type before building its schema so recursive types terminate
+	d.types[name] = t
+	d.Components.Schemas[name] = d.objectSchema(t)
+	return Ref(name)
+}
+
+// objectSchema builds the object schema of a struct from its json and validate tags,
+// only fields with a required validation are listed as required.
+// Embedded structs without a json name have their fields flattened like encoding/json does.
+func (d *Document) objectSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := jsonName(field)
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := d.objectSchema(field.Type)
+			for key, property := range embedded.Properties {
+				schema.Properties[key] = property
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := d.schemaFor(field.Type)
+		if applyRules(property, field.Type, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+	return schema
+}
+
+// jsonName returns the name of a field in its json tag
+func jsonName(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("json"), ",")[0]
+}
+
+// applyRules translates go-playground/validator rules into schema constraints
+// and reports whether the field is required
+func applyRules(schema *Schema, t reflect.Type, tag string) bool {
+	if tag == "" || schema.Ref != "" {
+		return false
+	}
+	// Like the validator, omitempty on a pointer only skips nil, a non-nil empty value is still checked
+	pointer := t.Kind() == reflect.Ptr
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	required := false
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		name, param := rule, ""
+		if eq := strings.Index(rule, "="); eq >= 0 {
+			name, param = rule[:eq], rule[eq+1:]
+		}
+
+		switch name {
+		case "dive":
+			if schema.Items != nil {
+				applyRules(schema.Items, t.Elem(), strings.Join(rules[i+1:], ","))
+			}
+			return required
+		case "omitempty":
+			schema.OmitEmpty = !pointer
+		case "required":
+			required = true
+			switch t.Kind() {
+			case reflect.String:
+				if schema.MinLength == nil {
+					schema.MinLength = intPtr(1)
+				}
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				schema.Minimum = floatPtr(1)
+			}
+		case "min", "gte":
+			setBound(schema, t, param, true)
+		case "max", "lte":
+			setBound(schema, t, param, false)
+		case "len":
+			setBound(schema, t, param, true)
+			setBound(schema, t, param, false)
+		case "email":
+			schema.Format = "email"
+		case "url":
+			schema.Format = "uri"
+		case "oneof":
+			schema.Enum = strings.Fields(param)
+		case "bcp47_language_tag":
+			schema.Pattern = bcp47Pattern
+		}
+	}
+	return required
+}
+
+// setBound sets the lower or upper bound matching the kind of the field
+func setBound(schema *Schema, t reflect.Type, param string, lower bool) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		if lower {
+			schema.MinLength = intPtr(int(value))
+		} else {
+			schema.MaxLength = intPtr(int(value))
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if lower {
+			schema.MinItems = intPtr(int(value))
+		} else {
+			schema.MaxItems = intPtr(int(value))
+		}
+	default:
+		if lower {
+			schema.Minimum = floatPtr(value)
+		} else {
+			schema.Maximum = floatPtr(value)
+		}
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
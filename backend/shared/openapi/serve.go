@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Paths of the routes serving the document and its Swagger UI
+const (
+	SpecPath = "/openapi.json"
+	DocsPath = "/docs"
+)
+
+// docsPage loads Swagger UI from a CDN and points it at the served document
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>API documentation</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "` + SpecPath + `", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>`
+
+// Register serves the document at /openapi.json and the Swagger UI at /docs
+func Register(e *echo.Echo, doc *Document) error {
+	spec, err := doc.JSON()
+	if err != nil {
+		return err
+	}
+
+	e.GET(SpecPath, func(c echo.Context) error {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, spec)
+	})
+	e.GET(DocsPath, func(c echo.Context) error {
+		return c.HTML(http.StatusOK, docsPage)
+	})
+	return nil
+}
+
+// Undocumented returns the registered routes that have no operation in the document,
+// as sorted "METHOD path" entries. The documentation routes themselves and the not found
+// routes Echo adds for groups with middleware are ignored.
+func (d *Document) Undocumented(routes []*echo.Route) []string {
+	var missing []string
+	for _, route := range routes {
+		if route.Path == SpecPath || route.Path == DocsPath || route.Method == echo.RouteNotFound {
+			continue
+		}
+		if d.Operation(route.Method, route.Path) == nil {
+			missing = append(missing, route.Method+" "+route.Path)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Unregistered returns the documented operations that no registered route serves
+func (d *Document) Unregistered(routes []*echo.Route) []string {
+	registered := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	var stale []string
+	for _, operation := range d.Operations() {
+		if !registered[operation] {
+			stale = append(stale, operation)
+		}
+	}
+	return stale
+}
@@ -0,0 +1,398 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/shared/apperror"
+	"github.com/yuhari7/backend_supervision/shared/i18n"
+)
+
+var errInvalidBody = apperror.Validation("invalid_request", "request body is not valid JSON", nil)
+
+// ValidatorConfig configures the validation middleware
+type ValidatorConfig struct {
+	Translator *i18n.Translator
+	// ValidateResponses also checks JSON responses against the document, meant for tests and local development
+	ValidateResponses bool
+}
+
+// violation is a value that breaks a schema constraint, the rule names match the validation message catalog
+type violation struct {
+	field string
+	rule  string
+	param string
+}
+
+func (v violation) String() string {
+	if v.param == "" {
+		return v.field + ": " + v.rule
+	}
+	return v.field + ": " + v.rule + "=" + v.param
+}
+
+// Validator returns a middleware that validates path parameters, query parameters and JSON bodies
+// of documented routes before the handler runs. Invalid requests fail with a validation_failed error
+// listing a translated message per field. Routes missing from the document are passed through.
+func (d *Document) Validator(config ValidatorConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			op := d.Operation(c.Request().Method, c.Path())
+			if op == nil {
+				return next(c)
+			}
+
+			violations, err := d.validateRequest(c, op)
+			if err != nil {
+				return err
+			}
+			if len(violations) > 0 {
+				locale := config.Translator.FromRequest(c.Request())
+				fields := make(map[string]string, len(violations))
+				for _, v := range violations {
+					if _, exists := fields[v.field]; !exists {
+						fields[v.field] = config.Translator.FieldMessage(locale, v.field, v.rule, v.param)
+					}
+				}
+				return apperror.Validation("validation_failed", "validation failed", fields)
+			}
+
+			if config.ValidateResponses {
+				return d.validateResponse(c, op, next)
+			}
+			return next(c)
+		}
+	}
+}
+
+func (d *Document) validateRequest(c echo.Context, op *Operation) ([]violation, error) {
+	var violations []violation
+	for _, param := range op.Parameters {
+		var raw string
+		switch param.In {
+		case "path":
+			raw = c.Param(param.Name)
+		case "query":
+			raw = c.QueryParam(param.Name)
+		}
+
+		// Empty values are treated as absent, handlers fall back to their defaults
+		if raw == "" {
+			if param.Required {
+				violations = append(violations, violation{field: param.Name, rule: "required"})
+			}
+			continue
+		}
+
+		value, ok := coerce(raw, param.Schema)
+		if !ok {
+			violations = append(violations, violation{field: param.Name, rule: "type", param: param.Schema.Type})
+			continue
+		}
+		d.validate(param.Schema, value, param.Name, &violations)
+	}
+
+	if op.RequestBody == nil {
+		return violations, nil
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+	// Put the body back so the handler can still bind it
+	c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			violations = append(violations, violation{field: "body", rule: "required"})
+		}
+		return violations, nil
+	}
+
+	media, ok := op.RequestBody.Content[echo.MIMEApplicationJSON]
+	if !ok {
+		return violations, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, errInvalidBody.Wrap(err)
+	}
+	d.validate(media.Schema, value, "", &violations)
+	return violations, nil
+}
+
+// validateResponse buffers the response written by the handler and only sends it
+// when its status and body match the document, otherwise the handler fails with an internal error
+func (d *Document) validateResponse(c echo.Context, op *Operation, next echo.HandlerFunc) error {
+	res := c.Response()
+	original := res.Writer
+	recorder := &responseRecorder{ResponseWriter: original}
+	res.Writer = recorder
+
+	err := next(c)
+	res.Writer = original
+	if !recorder.written {
+		return err
+	}
+
+	if violations := d.checkResponse(op, recorder); len(violations) > 0 {
+		res.Committed = false
+		res.Size = 0
+		return fmt.Errorf("response of %s %s does not match the OpenAPI document: %s", c.Request().Method, c.Path(), strings.Join(violations, "; "))
+	}
+
+	original.WriteHeader(recorder.status)
+	if _, writeErr := original.Write(recorder.body.Bytes()); writeErr != nil && err == nil {
+		err = writeErr
+	}
+	return err
+}
+
+func (d *Document) checkResponse(op *Operation, recorder *responseRecorder) []string {
+	response, ok := op.Responses[strconv.Itoa(recorder.status)]
+	if !ok {
+		return []string{fmt.Sprintf("status %d is not documented", recorder.status)}
+	}
+	if len(response.Content) == 0 {
+		return nil
+	}
+
+	contentType, _, _ := mime.ParseMediaType(recorder.Header().Get(echo.HeaderContentType))
+	media, ok := response.Content[contentType]
+	if !ok {
+		return []string{fmt.Sprintf("content type %q is not documented for status %d", contentType, recorder.status)}
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(recorder.body.Bytes(), &value); err != nil {
+		return []string{"body is not valid JSON"}
+	}
+
+	var violations []violation
+	d.validate(media.Schema, value, "", &violations)
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		messages = append(messages, v.String())
+	}
+	return messages
+}
+
+// validate checks a decoded JSON value against a schema, adding a violation for every broken constraint
+func (d *Document) validate(schema *Schema, value interface{}, field string, violations *[]violation) {
+	if value == nil {
+		if !schema.Nullable && (schema.Type != "" || schema.Ref != "") {
+			*violations = append(*violations, violation{field: fieldName(field), rule: "required"})
+		}
+		return
+	}
+	if schema.Ref != "" {
+		if resolved, ok := d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]; ok {
+			d.validate(resolved, value, field, violations)
+		}
+		return
+	}
+
+	if schema.OmitEmpty && isZero(value) {
+		return
+	}
+
+	add := func(rule, param string) {
+		*violations = append(*violations, violation{field: fieldName(field), rule: rule, param: param})
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			add("type", schema.Type)
+			return
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				*violations = append(*violations, violation{field: joinField(field, name), rule: "required"})
+			}
+		}
+		for name, property := range object {
+			if propertySchema, ok := schema.Properties[name]; ok {
+				d.validate(propertySchema, property, joinField(field, name), violations)
+			} else if schema.AdditionalProperties != nil {
+				d.validate(schema.AdditionalProperties, property, joinField(field, name), violations)
+			}
+		}
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			add("type", schema.Type)
+			return
+		}
+		if schema.MinItems != nil && len(items) < *schema.MinItems {
+			add("min_items", strconv.Itoa(*schema.MinItems))
+		}
+		if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+			add("max_items", strconv.Itoa(*schema.MaxItems))
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				d.validate(schema.Items, item, fmt.Sprintf("%s[%d]", fieldName(field), i), violations)
+			}
+		}
+
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			add("type", schema.Type)
+			return
+		}
+		length := utf8.RuneCountInString(text)
+		if schema.MinLength != nil && length < *schema.MinLength {
+			if *schema.MinLength == 1 {
+				add("required", "")
+			} else {
+				add("min", strconv.Itoa(*schema.MinLength))
+			}
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			add("max", strconv.Itoa(*schema.MaxLength))
+		}
+		if schema.Format == "email" && !isEmail(text) {
+			add("email", "")
+		}
+		if schema.Pattern != "" && !matches(schema.Pattern, text) {
+			add("pattern", schema.Pattern)
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, text) {
+			add("oneof", strings.Join(schema.Enum, " "))
+		}
+
+	case "integer", "number":
+		number, ok := value.(float64)
+		if !ok || (schema.Type == "integer" && number != math.Trunc(number)) {
+			add("type", schema.Type)
+			return
+		}
+		if schema.Minimum != nil && number < *schema.Minimum {
+			add("minimum", strconv.FormatFloat(*schema.Minimum, 'f', -1, 64))
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			add("maximum", strconv.FormatFloat(*schema.Maximum, 'f', -1, 64))
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			add("type", schema.Type)
+		}
+	}
+}
+
+// isZero reports whether a decoded JSON value is the zero value of its Go field
+func isZero(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// coerce converts a path or query parameter to the JSON type of its schema
+func coerce(raw string, schema *Schema) (interface{}, bool) {
+	switch schema.Type {
+	case "integer":
+		n, err := strconv.ParseInt(raw, 10, 64)
+		return float64(n), err == nil
+	case "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		return n, err == nil
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		return b, err == nil
+	default:
+		return raw, true
+	}
+}
+
+func fieldName(field string) string {
+	if field == "" {
+		return "body"
+	}
+	return field
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func isEmail(text string) bool {
+	address, err := mail.ParseAddress(text)
+	return err == nil && address.Address == text
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// patterns caches compiled schema patterns
+var patterns sync.Map
+
+func matches(pattern, text string) bool {
+	compiled, ok := patterns.Load(pattern)
+	if !ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return true
+		}
+		compiled, _ = patterns.LoadOrStore(pattern, re)
+	}
+	return compiled.(*regexp.Regexp).MatchString(text)
+}
+
+// responseRecorder holds back the response so it can be checked before it is sent
+type responseRecorder struct {
+	http.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.written {
+		r.status = status
+		r.written = true
+	}
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if !r.written {
+		r.WriteHeader(http.StatusOK)
+	}
+	return r.body.Write(data)
+}
@@ -0,0 +1,131 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/shared/i18n"
+	"github.com/yuhari7/backend_supervision/shared/problem"
+)
+
+type noteRequest struct {
+	Title string   `json:"title" validate:"required,min=5"`
+	Email string   `json:"email" validate:"omitempty,email"`
+	Tags  []string `json:"tags" validate:"max=2,dive,min=2"`
+
+	Code     string   `json:"code" validate:"omitempty,len=6"`
+	Secret   string   `json:"secret" validate:"omitempty,min=16"`
+	Kind     string   `json:"kind" validate:"omitempty,oneof=draft publish"`
+	Locale   string   `json:"locale" validate:"omitempty,bcp47_language_tag"`
+	Priority int      `json:"priority" validate:"omitempty,min=1"`
+	Labels   []string `json:"labels" validate:"omitempty,min=1,dive,min=2"`
+	Nickname *string  `json:"nickname" validate:"omitempty,min=3"`
+}
+
+type noteResponse struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+func newTestServer(validateResponses bool, handler echo.HandlerFunc) *echo.Echo {
+	doc := New("Notes", "1.0.0")
+	doc.PathParam("id", "Note ID", Integer(1))
+	doc.Add(http.MethodPut, "/notes/:id", Route{
+		Query:    []Parameter{QueryParam("limit", "", Integer(1))},
+		Body:     noteRequest{},
+		Response: noteResponse{},
+		Errors:   []int{http.StatusBadRequest},
+	})
+
+	translator := i18n.MustNew("en")
+	e := echo.New()
+	e.HTTPErrorHandler = problem.NewHTTPErrorHandler(translator)
+	e.Use(doc.Validator(ValidatorConfig{Translator: translator, ValidateResponses: validateResponses}))
+	e.PUT("/notes/:id", handler)
+	return e
+}
+
+func TestValidatorRequests(t *testing.T) {
+	ok := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, noteResponse{ID: 1, Title: "A valid title"})
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		wantStatus int
+		wantFields []string
+	}{
+		{"valid", "/notes/1?limit=5", `{"title":"A valid title","tags":["go"]}`, http.StatusOK, nil},
+		{"path parameter type", "/notes/abc", `{"title":"A valid title"}`, http.StatusBadRequest, []string{"id"}},
+		{"path parameter minimum", "/notes/0", `{"title":"A valid title"}`, http.StatusBadRequest, []string{"id"}},
+		{"query parameter type", "/notes/1?limit=many", `{"title":"A valid title"}`, http.StatusBadRequest, []string{"limit"}},
+		{"missing body", "/notes/1", ``, http.StatusBadRequest, []string{"body"}},
+		{"malformed body", "/notes/1", `{"title":`, http.StatusBadRequest, nil},
+		{"missing required field", "/notes/1", `{}`, http.StatusBadRequest, []string{"title"}},
+		{"empty optional values", "/notes/1", `{"title":"A valid title","email":"","code":"","secret":"","kind":"","locale":"","priority":0,"labels":[],"nickname":null}`, http.StatusOK, nil},
+		{"optional values", "/notes/1", `{"title":"A valid title","code":"12345","secret":"short","kind":"other","locale":"not a tag!","priority":-1,"labels":["a"]}`, http.StatusBadRequest, []string{"code", "secret", "kind", "locale", "priority", "labels[0]"}},
+		{"empty optional pointer", "/notes/1", `{"title":"A valid title","nickname":""}`, http.StatusBadRequest, []string{"nickname"}},
+		{"body constraints", "/notes/1", `{"title":"abc","email":"nope","tags":["a","bb","cc"]}`, http.StatusBadRequest, []string{"title", "email", "tags", "tags[0]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, tt.target, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			newTestServer(false, ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			var p problem.Problem
+			_ = json.Unmarshal(rec.Body.Bytes(), &p)
+			for _, field := range tt.wantFields {
+				if _, ok := p.Errors[field]; !ok {
+					t.Errorf("missing error for field %q in %v", field, p.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatorResponses(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    echo.HandlerFunc
+		wantStatus int
+	}{
+		{"documented response", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, noteResponse{ID: 1, Title: "A valid title"})
+		}, http.StatusOK},
+		{"undocumented status", func(c echo.Context) error {
+			return c.JSON(http.StatusCreated, noteResponse{ID: 1})
+		}, http.StatusInternalServerError},
+		{"body not matching schema", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, map[string]interface{}{"id": "one"})
+		}, http.StatusInternalServerError},
+		{"handler error", func(c echo.Context) error {
+			return echo.NewHTTPError(http.StatusBadRequest, "bad")
+		}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/notes/1", strings.NewReader(`{"title":"A valid title"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			newTestServer(true, tt.handler).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package outbox
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// BrokerFromEnv returns the broker selected by OUTBOX_BROKER.
+// "redis" appends to the OUTBOX_STREAM stream of the server at REDIS_URL, "memory" or no value returns memory.
+func BrokerFromEnv(memory *MemoryBroker) (Broker, error) {
+	switch kind := os.Getenv("OUTBOX_BROKER"); kind {
+	case "", "memory":
+		return memory, nil
+	case "redis":
+		options, err := redis.ParseURL(os.Getenv("REDIS_URL"))
+		if err != nil {
+			return nil, fmt.Errorf("parsing REDIS_URL: %w", err)
+		}
+		return NewRedisBroker(redis.NewClient(options), os.Getenv("OUTBOX_STREAM"), 0), nil
+	default:
+		return nil, fmt.Errorf("unknown OUTBOX_BROKER %q", kind)
+	}
+}
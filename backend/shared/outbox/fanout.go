@@ -0,0 +1,21 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+)
+
+// Fanout publishes every event to all of its brokers, e.g. in-process subscribers and a Redis stream.
+// When one broker fails the event is published again to all of them.
+type Fanout []Broker
+
+// Publish hands the event to every broker
+func (f Fanout) Publish(ctx context.Context, event Event) error {
+	var errs []error
+	for _, broker := range f {
+		if err := broker.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
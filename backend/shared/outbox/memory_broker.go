@@ -0,0 +1,48 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// AllEvents subscribes a handler to every event type
+const AllEvents = "*"
+
+// Handler consumes an event, returning an error makes the relay publish it again later
+type Handler func(ctx context.Context, event Event) error
+
+// MemoryBroker hands events to handlers in the same process
+type MemoryBroker struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewMemoryBroker creates a broker without subscribers, events are dropped until one subscribes
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe registers a handler for one event type or for AllEvents
+func (b *MemoryBroker) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish calls every matching handler. All handlers run even when one fails,
+// so handlers see an event again after a failure of another handler.
+func (b *MemoryBroker) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	handlers := append(append([]Handler(nil), b.handlers[event.Type]...), b.handlers[AllEvents]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("handling %s %s: %w", event.Type, event.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,46 @@
+// Package outbox records domain events in the same database transaction as the change they describe
+// and relays them to a message broker afterwards.
+//
+// Delivery is at-least-once: an event is only marked delivered after the broker accepted it, so a crash
+// in between publishes it again. Consumers deduplicate on the event ID.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is a domain event, e.g. an article being published
+type Event struct {
+	ID            string          `json:"id"`             // unique, used by consumers to drop duplicates
+	Type          string          `json:"type"`           // e.g. article.published
+	AggregateType string          `json:"aggregate_type"` // e.g. article
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// NewEvent creates an event with a new ID, payload is encoded as JSON
+func NewEvent(eventType, aggregateType string, aggregateID any, payload any) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("encoding %s payload: %w", eventType, err)
+	}
+	return Event{
+		ID:            uuid.NewString(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   fmt.Sprint(aggregateID),
+		Payload:       data,
+		OccurredAt:    time.Now().UTC(),
+	}, nil
+}
+
+// Broker delivers events to their consumers
+type Broker interface {
+	Publish(ctx context.Context, event Event) error
+}
@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultStream is the Redis stream events are appended to when none is configured
+const DefaultStream = "events"
+
+// RedisBroker appends events to a Redis stream. Each entry carries the event ID in its event_id field,
+// stream entry IDs differ between redeliveries of the same event.
+type RedisBroker struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+// NewRedisBroker creates a broker writing to stream, keeping roughly the last maxLen entries when maxLen > 0
+func NewRedisBroker(client *redis.Client, stream string, maxLen int64) *RedisBroker {
+	if stream == "" {
+		stream = DefaultStream
+	}
+	return &RedisBroker{client: client, stream: stream, maxLen: maxLen}
+}
+
+// Publish appends the event to the stream
+func (b *RedisBroker) Publish(ctx context.Context, event Event) error {
+	args := &redis.XAddArgs{
+		Stream: b.stream,
+		Values: map[string]interface{}{
+			"event_id":       event.ID,
+			"type":           event.Type,
+			"aggregate_type": event.AggregateType,
+			"aggregate_id":   event.AggregateID,
+			"payload":        string(event.Payload),
+			"occurred_at":    event.OccurredAt.Format(time.RFC3339Nano),
+		},
+	}
+	if b.maxLen > 0 {
+		args.MaxLen = b.maxLen
+		args.Approx = true
+	}
+	if err := b.client.XAdd(ctx, args).Err(); err != nil {
+		return fmt.Errorf("adding %s %s to stream %s: %w", event.Type, event.ID, b.stream, err)
+	}
+	return nil
+}
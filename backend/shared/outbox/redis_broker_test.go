@@ -0,0 +1,49 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisBrokerAppendsToStream(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	broker := NewRedisBroker(client, "", 0)
+
+	event := newEvents(t, "article.published")[0]
+	// Publishing twice stands in for a redelivery, consumers tell them apart by event_id
+	for i := 0; i < 2; i++ {
+		if err := broker.Publish(context.Background(), event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	entries, err := client.XRange(context.Background(), DefaultStream, "-", "+").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d stream entries, want 2", len(entries))
+	}
+	values := entries[0].Values
+	if values["event_id"] != event.ID || values["type"] != "article.published" || values["aggregate_id"] != "1" || values["payload"] != `{"id":1}` {
+		t.Errorf("got entry %v, want the fields of event %s", values, event.ID)
+	}
+	if entries[1].Values["event_id"] != event.ID {
+		t.Errorf("got event_id %v for the redelivery, want %s", entries[1].Values["event_id"], event.ID)
+	}
+}
+
+func TestRedisBrokerReportsFailures(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	broker := NewRedisBroker(client, "events", 0)
+	server.Close()
+
+	if err := broker.Publish(context.Background(), newEvents(t, "article.published")[0]); err == nil {
+		t.Error("expected an error while redis is down")
+	}
+}
@@ -0,0 +1,111 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RelayConfig configures a relay, zero values fall back to the defaults
+type RelayConfig struct {
+	Interval   time.Duration // how often the outbox is polled, defaults to 1s
+	BatchSize  int           // events claimed per poll, defaults to 100
+	Lease      time.Duration // how long claimed events are hidden from other relays, defaults to 1m
+	MaxBackoff time.Duration // longest wait between retries of an event, defaults to 1h
+}
+
+// Relay moves events from the store to the broker
+type Relay struct {
+	store  Store
+	broker Broker
+	config RelayConfig
+
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+// NewRelay creates a relay publishing the events of store to broker
+func NewRelay(store Store, broker Broker, config RelayConfig) *Relay {
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.Lease <= 0 {
+		config.Lease = time.Minute
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = time.Hour
+	}
+	return &Relay{store: store, broker: broker, config: config, doneCh: make(chan struct{})}
+}
+
+// Start runs the background polling loop
+func (r *Relay) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	go func() {
+		defer close(r.doneCh)
+
+		ticker := time.NewTicker(r.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				// Keep going while full batches come back so a backlog drains quickly
+				for {
+					claimed, err := r.Flush(ctx)
+					if err != nil {
+						log.Println("Failed to relay outbox events:", err)
+					}
+					if err != nil || claimed < r.config.BatchSize {
+						break
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background loop, events still in the outbox are published after the next start
+func (r *Relay) Stop() {
+	r.cancel()
+	<-r.doneCh
+}
+
+// Flush publishes one batch of due events and returns how many were claimed.
+// Failed events are retried with exponential backoff.
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	pending, err := r.store.Claim(ctx, r.config.BatchSize, r.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, p := range pending {
+		if err := r.broker.Publish(ctx, p.Event); err != nil {
+			log.Printf("Failed to publish %s event %s (attempt %d): %v", p.Type, p.ID, p.Attempts+1, err)
+			if err := r.store.MarkFailed(ctx, p.ID, err, time.Now().Add(r.backoff(p.Attempts))); err != nil {
+				return len(pending), err
+			}
+			continue
+		}
+		if err := r.store.MarkDelivered(ctx, p.ID); err != nil {
+			return len(pending), err
+		}
+	}
+	return len(pending), nil
+}
+
+// backoff doubles the wait after every failed attempt, starting at one second
+func (r *Relay) backoff(attempts int) time.Duration {
+	wait := time.Second
+	for i := 0; i < attempts && wait < r.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, r.config.MaxBackoff)
+}
@@ -0,0 +1,186 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStore is an in-memory Store with the same claim semantics as the outbox table
+type memoryStore struct {
+	mu        sync.Mutex
+	events    []Pending
+	due       map[string]time.Time
+	delivered map[string]bool
+}
+
+func newMemoryStore(events ...Event) *memoryStore {
+	s := &memoryStore{due: map[string]time.Time{}, delivered: map[string]bool{}}
+	for _, event := range events {
+		s.events = append(s.events, Pending{Event: event})
+		s.due[event.ID] = event.OccurredAt
+	}
+	return s
+}
+
+func (s *memoryStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]Pending, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var claimed []Pending
+	now := time.Now()
+	for _, p := range s.events {
+		if len(claimed) == limit {
+			break
+		}
+		if !s.delivered[p.ID] && !s.due[p.ID].After(now) {
+			s.due[p.ID] = now.Add(lease)
+			claimed = append(claimed, p)
+		}
+	}
+	return claimed, nil
+}
+
+func (s *memoryStore) MarkDelivered(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delivered[id] = true
+	return nil
+}
+
+func (s *memoryStore) MarkFailed(ctx context.Context, id string, cause error, retryAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.events {
+		if s.events[i].ID == id {
+			s.events[i].Attempts++
+		}
+	}
+	s.due[id] = retryAt
+	return nil
+}
+
+func (s *memoryStore) pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events) - len(s.delivered)
+}
+
+func newEvents(t *testing.T, types ...string) []Event {
+	t.Helper()
+	var events []Event
+	for i, eventType := range types {
+		event, err := NewEvent(eventType, "article", i+1, map[string]int{"id": i + 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestRelayDeliversToSubscribers(t *testing.T) {
+	events := newEvents(t, "article.published", "user.deactivated", "article.published")
+	store := newMemoryStore(events...)
+	broker := NewMemoryBroker()
+
+	var published, all []string
+	broker.Subscribe("article.published", func(ctx context.Context, event Event) error {
+		published = append(published, event.ID)
+		return nil
+	})
+	broker.Subscribe(AllEvents, func(ctx context.Context, event Event) error {
+		all = append(all, event.Type)
+		return nil
+	})
+
+	relay := NewRelay(store, broker, RelayConfig{BatchSize: 2})
+	for {
+		claimed, err := relay.Flush(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if claimed == 0 {
+			break
+		}
+	}
+
+	if want := []string{events[0].ID, events[2].ID}; !reflect.DeepEqual(published, want) {
+		t.Errorf("got published events %v, want %v", published, want)
+	}
+	if want := []string{"article.published", "user.deactivated", "article.published"}; !reflect.DeepEqual(all, want) {
+		t.Errorf("got events %v, want %v in order", all, want)
+	}
+	if store.pending() != 0 {
+		t.Errorf("got %d undelivered events, want none", store.pending())
+	}
+}
+
+func TestRelayRetriesFailedEvents(t *testing.T) {
+	events := newEvents(t, "user.role_changed")
+	store := newMemoryStore(events...)
+	broker := NewMemoryBroker()
+
+	// The handler fails once, the event is delivered again with the same ID
+	var seen []string
+	broker.Subscribe(AllEvents, func(ctx context.Context, event Event) error {
+		seen = append(seen, event.ID)
+		if len(seen) == 1 {
+			return errors.New("consumer unavailable")
+		}
+		return nil
+	})
+
+	relay := NewRelay(store, broker, RelayConfig{})
+	if _, err := relay.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.pending() != 1 || store.events[0].Attempts != 1 {
+		t.Fatalf("got %d pending events after %d attempts, want the failed event kept", store.pending(), store.events[0].Attempts)
+	}
+
+	// Not due before the backoff has passed
+	if claimed, _ := relay.Flush(context.Background()); claimed != 0 {
+		t.Errorf("got %d events claimed during the backoff, want none", claimed)
+	}
+	store.due[events[0].ID] = time.Now()
+	if _, err := relay.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{events[0].ID, events[0].ID}; !reflect.DeepEqual(seen, want) || store.pending() != 0 {
+		t.Errorf("got deliveries %v with %d pending, want the event delivered twice", seen, store.pending())
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	relay := NewRelay(newMemoryStore(), NewMemoryBroker(), RelayConfig{MaxBackoff: 10 * time.Second})
+	for attempts, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		if got := relay.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	store := newMemoryStore(newEvents(t, "article.published")...)
+	delivered := make(chan struct{}, 1)
+	broker := NewMemoryBroker()
+	broker.Subscribe(AllEvents, func(ctx context.Context, event Event) error {
+		delivered <- struct{}{}
+		return nil
+	})
+
+	relay := NewRelay(store, broker, RelayConfig{Interval: 10 * time.Millisecond})
+	relay.Start()
+	defer relay.Stop()
+
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("event was not relayed")
+	}
+}
@@ -0,0 +1,126 @@
+package outbox
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Pending is an event waiting to be delivered
+type Pending struct {
+	Event
+	Attempts int // failed deliveries so far
+}
+
+// Store keeps the events that still have to be delivered
+type Store interface {
+	// Claim returns up to limit events that are due, hiding them from other relays for the lease duration
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]Pending, error)
+	MarkDelivered(ctx context.Context, id string) error
+	MarkFailed(ctx context.Context, id string, cause error, retryAt time.Time) error
+}
+
+// record is a row of the outbox table
+type record struct {
+	ID            string `gorm:"primaryKey;type:uuid"`
+	EventType     string
+	AggregateType string
+	AggregateID   string
+	Payload       string `gorm:"type:jsonb"`
+	CreatedAt     time.Time
+	Attempts      int
+	LastError     *string
+	NextAttemptAt time.Time
+	DeliveredAt   *time.Time
+}
+
+func (record) TableName() string {
+	return "outbox"
+}
+
+// Add writes events to the outbox table, tx should be the transaction that saves the change they describe
+func Add(tx *gorm.DB, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	records := make([]record, 0, len(events))
+	for _, event := range events {
+		records = append(records, record{
+			ID:            event.ID,
+			EventType:     event.Type,
+			AggregateType: event.AggregateType,
+			AggregateID:   event.AggregateID,
+			Payload:       string(event.Payload),
+			CreatedAt:     event.OccurredAt,
+			NextAttemptAt: event.OccurredAt,
+		})
+	}
+	return tx.Create(&records).Error
+}
+
+// GormStore is the Store backed by the outbox table
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore creates a store reading the outbox table of db
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{db: db}
+}
+
+// Claim pushes the next attempt of due events back by the lease, rows locked by another relay are skipped
+func (s *GormStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]Pending, error) {
+	now := time.Now().UTC()
+	var records []record
+	err := s.db.WithContext(ctx).Raw(`
+		UPDATE outbox SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE delivered_at IS NULL AND next_attempt_at <= ?
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, aggregate_type, aggregate_id, payload, created_at, attempts`,
+		now.Add(lease), now, limit,
+	).Scan(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the order of the subquery
+	sort.Slice(records, func(i, j int) bool { return records[i].CreatedAt.Before(records[j].CreatedAt) })
+
+	pending := make([]Pending, 0, len(records))
+	for _, r := range records {
+		pending = append(pending, Pending{
+			Event: Event{
+				ID:            r.ID,
+				Type:          r.EventType,
+				AggregateType: r.AggregateType,
+				AggregateID:   r.AggregateID,
+				Payload:       []byte(r.Payload),
+				OccurredAt:    r.CreatedAt.UTC(),
+			},
+			Attempts: r.Attempts,
+		})
+	}
+	return pending, nil
+}
+
+// MarkDelivered records that the broker accepted the event
+func (s *GormStore) MarkDelivered(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Model(&record{}).Where("id = ?", id).
+		Update("delivered_at", time.Now().UTC()).Error
+}
+
+// MarkFailed records a failed delivery, the event is claimed again at retryAt
+func (s *GormStore) MarkFailed(ctx context.Context, id string, cause error, retryAt time.Time) error {
+	return s.db.WithContext(ctx).Model(&record{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      cause.Error(),
+		"next_attempt_at": retryAt.UTC(),
+	}).Error
+}
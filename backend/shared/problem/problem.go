@@ -0,0 +1,167 @@
+// Package problem renders errors as RFC 7807 application/problem+json responses.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/shared/apperror"
+	"github.com/yuhari7/backend_supervision/shared/i18n"
+)
+
+// ContentType is the media type of problem responses
+const ContentType = "application/problem+json"
+
+// TypePrefix is prepended to the error code to build the stable problem type URI
+const TypePrefix = "urn:supervision:problem:"
+
+// Problem is the RFC 7807 problem details object
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     string            `json:"code"`
+	Errors   map[string]string `json:"errors,omitempty"`
+
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON adds the extension members next to the standard members
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type standard Problem
+	data, err := json.Marshal(standard(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+
+	members := make(map[string]interface{})
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for key, value := range p.Extensions {
+		if _, reserved := members[key]; !reserved {
+			members[key] = value
+		}
+	}
+	return json.Marshal(members)
+}
+
+// StatusOf maps an error kind to its HTTP status code
+func StatusOf(kind apperror.Kind) int {
+	switch kind {
+	case apperror.KindValidation:
+		return http.StatusBadRequest
+	case apperror.KindUnauthorized:
+		return http.StatusUnauthorized
+	case apperror.KindForbidden:
+		return http.StatusForbidden
+	case apperror.KindNotFound:
+		return http.StatusNotFound
+	case apperror.KindConflict:
+		return http.StatusConflict
+	case apperror.KindUnprocessable:
+		return http.StatusUnprocessableEntity
+	case apperror.KindTooManyRequests:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// FromError converts any error into a problem, translating messages into locale
+func FromError(err error, translator *i18n.Translator, locale string) Problem {
+	var (
+		appErr        *apperror.Error
+		httpErr       *echo.HTTPError
+		validationErr validator.ValidationErrors
+	)
+
+	switch {
+	case errors.As(err, &validationErr):
+		appErr = apperror.Validation("validation_failed", "validation failed", translator.ValidationErrors(locale, validationErr))
+	case errors.As(err, &appErr):
+	case errors.As(err, &httpErr):
+		appErr = fromHTTPError(httpErr)
+	default:
+		appErr = apperror.Internal(err)
+	}
+
+	status := StatusOf(appErr.Kind)
+	if httpErr != nil && appErr.Kind == apperror.KindInternal {
+		status = httpErr.Code
+	}
+
+	return Problem{
+		Type:       TypePrefix + appErr.Code,
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     translator.MessageOr(locale, appErr.Code, appErr.Message),
+		Code:       appErr.Code,
+		Errors:     appErr.Fields,
+		Extensions: appErr.Extensions,
+	}
+}
+
+// NewHTTPErrorHandler returns an Echo error handler that writes every error as problem+json
+func NewHTTPErrorHandler(translator *i18n.Translator) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		p := FromError(err, translator, translator.FromRequest(c.Request()))
+		p.Instance = c.Request().URL.Path
+		if p.Status >= http.StatusInternalServerError {
+			log.Printf("%s %s: %v", c.Request().Method, c.Request().URL.Path, err)
+		}
+
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(p.Status)
+		} else {
+			err = Write(c, p)
+		}
+		if err != nil {
+			log.Println("Failed to write problem response:", err)
+		}
+	}
+}
+
+// Write sends the problem as the response body
+func Write(c echo.Context, p Problem) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return c.Blob(p.Status, ContentType, data)
+}
+
+// fromHTTPError converts errors raised by Echo itself, such as unknown routes or bind failures
+func fromHTTPError(httpErr *echo.HTTPError) *apperror.Error {
+	message := http.StatusText(httpErr.Code)
+	if text, ok := httpErr.Message.(string); ok && text != "" {
+		message = text
+	}
+	code := strings.ToLower(strings.ReplaceAll(http.StatusText(httpErr.Code), " ", "_"))
+
+	switch httpErr.Code {
+	case http.StatusBadRequest:
+		return apperror.Validation("invalid_request", message, nil)
+	case http.StatusUnauthorized:
+		return apperror.Unauthorized(code, message)
+	case http.StatusForbidden:
+		return apperror.Forbidden(code, message)
+	case http.StatusNotFound:
+		return apperror.NotFound(code, message)
+	case http.StatusConflict:
+		return apperror.Conflict(code, message)
+	default:
+		return &apperror.Error{Kind: apperror.KindInternal, Code: code, Message: message, Err: httpErr}
+	}
+}
@@ -0,0 +1,112 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/shared/apperror"
+	"github.com/yuhari7/backend_supervision/shared/i18n"
+)
+
+func newTranslator(t *testing.T) *i18n.Translator {
+	t.Helper()
+	translator, err := i18n.NewFromFS(fstest.MapFS{
+		"id.yaml": {Data: []byte("messages:\n  article_not_found: Artikel tidak ditemukan\n")},
+		"en.yaml": {Data: []byte("messages:\n  article_not_found: Article not found\n")},
+	}, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return translator
+}
+
+func TestFromErrorMapsKinds(t *testing.T) {
+	translator := newTranslator(t)
+
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{apperror.Validation("invalid_title", "title is required", nil), http.StatusBadRequest, "invalid_title"},
+		{apperror.Unauthorized("invalid_token", "invalid token"), http.StatusUnauthorized, "invalid_token"},
+		{apperror.Forbidden("forbidden", "not allowed"), http.StatusForbidden, "forbidden"},
+		{apperror.NotFound("article_not_found", "article not found"), http.StatusNotFound, "article_not_found"},
+		{apperror.Conflict("duplicate_content", "duplicate content"), http.StatusConflict, "duplicate_content"},
+		{apperror.Unprocessable("policy_violation", "policy violated"), http.StatusUnprocessableEntity, "policy_violation"},
+		{apperror.TooManyRequests("too_many_attempts", "try again later"), http.StatusTooManyRequests, "too_many_attempts"},
+		{fmt.Errorf("saving article: %w", apperror.NotFound("article_not_found", "article not found")), http.StatusNotFound, "article_not_found"},
+		{echo.NewHTTPError(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed, "method_not_allowed"},
+	}
+	for _, tt := range tests {
+		p := FromError(tt.err, translator, "en")
+		if p.Status != tt.status || p.Code != tt.code || p.Type != TypePrefix+tt.code || p.Title != http.StatusText(tt.status) {
+			t.Errorf("got %+v for %v, want status %d and code %s", p, tt.err, tt.status, tt.code)
+		}
+	}
+}
+
+func TestFromErrorTranslatesDetail(t *testing.T) {
+	translator := newTranslator(t)
+	err := apperror.NotFound("article_not_found", "article not found")
+
+	if got := FromError(err, translator, "id").Detail; got != "Artikel tidak ditemukan" {
+		t.Errorf("got detail %q, want the Indonesian message", got)
+	}
+	if got := FromError(err, translator, "en").Detail; got != "Article not found" {
+		t.Errorf("got detail %q, want the English message", got)
+	}
+	// Codes without a message in the catalog keep the message of the error
+	if got := FromError(apperror.Conflict("email_taken", "email already registered"), translator, "en").Detail; got != "email already registered" {
+		t.Errorf("got detail %q, want the message of the error", got)
+	}
+}
+
+func TestExtensionsAreMembers(t *testing.T) {
+	err := apperror.Conflict("duplicate_content", "duplicate content").With("duplicates", []uint{3}).With("status", "ignored")
+	data, marshalErr := json.Marshal(FromError(err, newTranslator(t), "en"))
+	if marshalErr != nil {
+		t.Fatal(marshalErr)
+	}
+
+	var members map[string]interface{}
+	if err := json.Unmarshal(data, &members); err != nil {
+		t.Fatal(err)
+	}
+	if duplicates, _ := members["duplicates"].([]interface{}); len(duplicates) != 1 || duplicates[0] != float64(3) {
+		t.Errorf("got duplicates %v, want [3]", members["duplicates"])
+	}
+	// Extensions cannot replace standard members
+	if members["status"] != float64(http.StatusConflict) {
+		t.Errorf("got status %v, want %d", members["status"], http.StatusConflict)
+	}
+}
+
+func TestUnknownErrorsHideDetails(t *testing.T) {
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/api/articles", nil)
+	recorder := httptest.NewRecorder()
+
+	NewHTTPErrorHandler(newTranslator(t))(errors.New("pq: password authentication failed"), e.NewContext(request, recorder))
+
+	if recorder.Code != http.StatusInternalServerError || recorder.Header().Get(echo.HeaderContentType) != ContentType {
+		t.Fatalf("got status %d with %q, want 500 as problem+json", recorder.Code, recorder.Header().Get(echo.HeaderContentType))
+	}
+	if body := recorder.Body.String(); strings.Contains(body, "pq:") || strings.Contains(body, "password") {
+		t.Errorf("got body %s, want the cause hidden", body)
+	}
+	var p Problem
+	if err := json.Unmarshal(recorder.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Code != "internal_error" || p.Detail != "internal server error" || p.Instance != "/api/articles" {
+		t.Errorf("got %+v, want an internal error for the request path", p)
+	}
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+)
+
+// Email verification
+
+// VerifyEmail verifies the email of a user with the token of a verification link
+func (h *UserController) VerifyEmail(c echo.Context) error {
+	var input dto.VerifyEmailRequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	if _, err := h.Verification.Verify(input); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.MessageResponse{Message: "email verified, you can now log in"})
+}
+
+// ResendVerification mails a new verification link, the response is the same whether or not the email needs one
+func (h *UserController) ResendVerification(c echo.Context) error {
+	var input dto.ResendVerificationRequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	if err := h.Verification.Resend(input, h.Translator.FromRequest(c.Request())); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, dto.MessageResponse{Message: "if the email is waiting for verification, a new link has been sent"})
+}
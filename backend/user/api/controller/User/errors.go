@@ -0,0 +1,11 @@
+package controller
+
+import "github.com/yuhari7/backend_supervision/shared/apperror"
+
+// Errors returned by handlers for malformed requests, rendered by the problem error handler
+var (
+	errInvalidRequest  = apperror.Validation("invalid_request", "invalid request", nil)
+	errInvalidUserID   = apperror.Validation("invalid_user_id", "invalid user id", nil)
+	errInvalidRoleID   = apperror.Validation("invalid_role_id", "invalid role id", nil)
+	errInvalidInviteID = apperror.Validation("invalid_invite_id", "invalid invite id", nil)
+)
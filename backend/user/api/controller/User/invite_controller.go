@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/usecase/registration"
+	"github.com/yuhari7/backend_supervision/shared/auth"
+)
+
+// InviteController handles the registration invite endpoints
+type InviteController struct {
+	Usecase registration.RegistrationUsecase
+}
+
+func NewInviteController(u registration.RegistrationUsecase) *InviteController {
+	return &InviteController{Usecase: u}
+}
+
+// CreateInvite issues an invite for a role, the response holds the only copy of its token
+func (h *InviteController) CreateInvite(c echo.Context) error {
+	principal, _ := auth.PrincipalFrom(c.Request().Context())
+
+	var input dto.CreateInviteRequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	invite, err := h.Usecase.CreateInvite(input, principal.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, invite)
+}
+
+func (h *InviteController) ListInvites(c echo.Context) error {
+	invites, err := h.Usecase.ListInvites()
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.InvitesResponse{Invites: invites})
+}
+
+func (h *InviteController) RevokeInvite(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("invite_id"))
+	if err != nil {
+		return errInvalidInviteID
+	}
+
+	if err := h.Usecase.RevokeInvite(uint(id)); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.MessageResponse{Message: "invite revoked"})
+}
@@ -0,0 +1,18 @@
+package controller
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/api/middleware"
+	"github.com/yuhari7/backend_supervision/internal/usecase/registration"
+	"github.com/yuhari7/backend_supervision/shared/auth"
+)
+
+// RegisterInviteRoutes registers the invite routes, open to callers with the users:manage permission
+func RegisterInviteRoutes(e *echo.Group, usecase registration.RegistrationUsecase, sessions auth.Authenticator) {
+	handler := NewInviteController(usecase)
+
+	invites := e.Group("/invites", middleware.AuthMiddleware(sessions), middleware.RequirePermission("users:manage"))
+	invites.GET("", handler.ListInvites)
+	invites.POST("", handler.CreateInvite)
+	invites.DELETE("/:invite_id", handler.RevokeInvite)
+}
@@ -0,0 +1,16 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/internal/usecase/keyring"
+)
+
+// RegisterKeyRoutes serves the public keys access tokens are signed with as a JSON Web Key Set
+func RegisterKeyRoutes(e *echo.Echo, keys keyring.Keyring) {
+	e.GET("/.well-known/jwks.json", func(c echo.Context) error {
+		c.Response().Header().Set("Cache-Control", "public, max-age=300")
+		return c.JSON(http.StatusOK, keys.JWKS())
+	})
+}
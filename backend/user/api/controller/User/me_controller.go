@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/shared/auth"
+)
+
+// Me
+
+// GetMe returns the account of the logged in user
+func (h *UserController) GetMe(c echo.Context) error {
+	principal, _ := auth.PrincipalFrom(c.Request().Context())
+
+	user, err := h.Usecase.GetUserByID(principal.UserID)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.profileResponse(user)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// UpdateMe changes the name, email or avatar of the logged in user, the role and active state cannot be changed here.
+// A new email gets a verification link and replaces the current one once the link is opened.
+func (h *UserController) UpdateMe(c echo.Context) error {
+	principal, _ := auth.PrincipalFrom(c.Request().Context())
+
+	var input dto.UpdateProfileRequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	user, err := h.Usecase.UpdateProfile(principal.UserID, input)
+	if err != nil {
+		return err
+	}
+	// The profile is saved either way, a failed mail can be sent again by submitting the email once more
+	if input.Email != nil && user.PendingEmail != nil {
+		if err := h.Verification.Send(user, h.Translator.FromRequest(c.Request())); err != nil {
+			log.Printf("Failed to send the verification mail of user %d: %v", user.ID, err)
+		}
+	}
+
+	response, err := h.profileResponse(user)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.UserMessageResponse[dto.ProfileResponse]{
+		Message: "profile updated successfully",
+		User:    response,
+	})
+}
+
+// ChangeMyPassword replaces the password of the logged in user, ending their other sessions.
+// The response carries the tokens of a new session so the caller stays logged in.
+func (h *UserController) ChangeMyPassword(c echo.Context) error {
+	principal, _ := auth.PrincipalFrom(c.Request().Context())
+
+	var input dto.ChangePasswordRequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	user, err := h.Usecase.ChangePassword(principal.UserID, input)
+	if err != nil {
+		return err
+	}
+	h.Sessions.Forget(user.ID)
+
+	tokens, err := h.Sessions.Start(user)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.RefreshTokenResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	})
+}
+
+func (h *UserController) profileResponse(user *entity.User) (dto.ProfileResponse, error) {
+	mfaEnabled, err := h.MFA.Enabled(user.ID)
+	if err != nil {
+		return dto.ProfileResponse{}, err
+	}
+
+	return dto.ProfileResponse{
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		Role:          user.RoleID,
+		AvatarURL:     user.AvatarURL(),
+		EmailVerified: user.EmailVerifiedAt != nil,
+		PendingEmail:  pendingEmail(user),
+		MFAEnabled:    mfaEnabled,
+		CreatedAt:     user.CreatedAt,
+	}, nil
+}
+
+func pendingEmail(user *entity.User) string {
+	if user.PendingEmail == nil {
+		return ""
+	}
+	return *user.PendingEmail
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/shared/auth"
+)
+
+// Second step of a login
+
+// VerifyLoginMFA finishes a login with the mfa_token it returned and a code of the app or a recovery code.
+// It also confirms two-factor authentication set up during the login, the response then lists the recovery codes.
+func (h *UserController) VerifyLoginMFA(c echo.Context) error {
+	var input dto.MFALoginRequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	user, recoveryCodes, err := h.MFA.VerifyChallenge(input)
+	if err != nil {
+		return err
+	}
+
+	return h.startSession(c, user, recoveryCodes)
+}
+
+// SetupLoginMFA starts setting up the two-factor authentication the role of the user requires during login
+func (h *UserController) SetupLoginMFA(c echo.Context) error {
+	var input dto.MFATokenRequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	setup, err := h.MFA.SetupChallenge(input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, setup)
+}
+
+// Two-factor authentication of the logged in user
+
+// SetupMyMFA returns a new secret for an authenticator app, it is used once confirmed with a code
+func (h *UserController) SetupMyMFA(c echo.Context) error {
+	principal, _ := auth.PrincipalFrom(c.Request().Context())
+
+	setup, err := h.MFA.Setup(principal.UserID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, setup)
+}
+
+// ConfirmMyMFA enables two-factor authentication with a code of the app and returns the recovery codes
+func (h *UserController) ConfirmMyMFA(c echo.Context) error {
+	principal, _ := auth.PrincipalFrom(c.Request().Context())
+
+	var input dto.MFACodeRequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	codes, err := h.MFA.Confirm(principal.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// RegenerateMyRecoveryCodes replaces the recovery codes of the logged in user
+func (h *UserController) RegenerateMyRecoveryCodes(c echo.Context) error {
+	principal, _ := auth.PrincipalFrom(c.Request().Context())
+
+	var input dto.MFACodeRequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	codes, err := h.MFA.RegenerateRecoveryCodes(principal.UserID, input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableMyMFA turns two-factor authentication off, unless the role of the user requires it
+func (h *UserController) DisableMyMFA(c echo.Context) error {
+	principal, _ := auth.PrincipalFrom(c.Request().Context())
+
+	var input dto.DisableMFARequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	if err := h.MFA.Disable(principal.UserID, input); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.MessageResponse{Message: "two-factor authentication disabled"})
+}
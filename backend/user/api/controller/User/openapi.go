@@ -0,0 +1,301 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/shared/jwks"
+	"github.com/yuhari7/backend_supervision/shared/openapi"
+)
+
+// Spec documents every route registered by RegisterUserRoutes, RegisterRoleRoutes, RegisterPasswordRoutes
+// and RegisterInviteRoutes under /api and by RegisterKeyRoutes.
+// Request and response schemas are generated from the DTO structs the handlers use.
+func Spec() *openapi.Document {
+	doc := openapi.New("User Service", "1.0.0")
+	doc.PathParam("id", "User ID", openapi.Integer(1))
+	doc.PathParam("role_id", "Role ID", openapi.Integer(1))
+	doc.PathParam("invite_id", "Invite ID", openapi.Integer(1))
+
+	// Authentication
+	auth := []string{"auth"}
+	doc.Add(http.MethodPost, "/api/register", openapi.Route{
+		Summary:  "Register a new user with the default role or the role of an invite, who has to verify their email before logging in",
+		Tags:     auth,
+		Body:     dto.RegisterRequest{},
+		Response: dto.UserMessageResponse[dto.UserResponse]{},
+		Status:   http.StatusCreated,
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusConflict},
+	})
+	doc.Add(http.MethodPost, "/api/email/verify", openapi.Route{
+		Summary:  "Verify an email with the single-use token of a verification link, a pending email replaces the current one",
+		Tags:     auth,
+		Body:     dto.VerifyEmailRequest{},
+		Response: dto.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusConflict},
+	})
+	doc.Add(http.MethodPost, "/api/email/resend", openapi.Route{
+		Summary:  "Mail a new verification link, rate limited per email",
+		Tags:     auth,
+		Body:     dto.ResendVerificationRequest{},
+		Response: dto.MessageResponse{},
+		Status:   http.StatusAccepted,
+		Errors:   []int{http.StatusBadRequest, http.StatusTooManyRequests},
+	})
+	doc.Add(http.MethodPost, "/api/login", openapi.Route{
+		Summary:  "Log in with email and password, with two-factor authentication only an mfa_token is returned",
+		Tags:     auth,
+		Body:     dto.LoginRequest{},
+		Response: dto.LoginResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden},
+	})
+	doc.Add(http.MethodPost, "/api/login/mfa", openapi.Route{
+		Summary:  "Finish a login with its mfa_token and a code of the authenticator app or a recovery code",
+		Tags:     auth,
+		Body:     dto.MFALoginRequest{},
+		Response: dto.LoginResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusUnprocessableEntity},
+	})
+	doc.Add(http.MethodPost, "/api/login/mfa/setup", openapi.Route{
+		Summary:  "Set up the two-factor authentication the role requires during login, confirmed through /api/login/mfa",
+		Tags:     auth,
+		Body:     dto.MFATokenRequest{},
+		Response: dto.MFASetupResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusConflict},
+	})
+	doc.Add(http.MethodPost, "/api/refresh", openapi.Route{
+		Summary:  "Exchange a refresh token for new tokens, the refresh token is rotated",
+		Tags:     auth,
+		Body:     dto.RefreshTokenRequest{},
+		Response: dto.RefreshTokenResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized},
+	})
+	doc.Add(http.MethodPost, "/api/logout", openapi.Route{
+		Summary:  "End the session of a refresh token",
+		Tags:     auth,
+		Body:     dto.RefreshTokenRequest{},
+		Response: dto.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest},
+	})
+	doc.Add(http.MethodPost, "/api/logout-all", openapi.Route{
+		Summary:  "End every session of the logged in user",
+		Tags:     auth,
+		Response: dto.MessageResponse{},
+		Secured:  true,
+	})
+
+	doc.Add(http.MethodPost, "/api/password/forgot", openapi.Route{
+		Summary:  "Mail a password reset link, rate limited per email, the response does not tell whether the email is registered",
+		Tags:     auth,
+		Body:     dto.ForgotPasswordRequest{},
+		Response: dto.MessageResponse{},
+		Status:   http.StatusAccepted,
+		Errors:   []int{http.StatusBadRequest, http.StatusTooManyRequests},
+	})
+	doc.Add(http.MethodPost, "/api/password/reset", openapi.Route{
+		Summary:  "Set a new password with the single-use token of a reset link, every session of the user ends",
+		Tags:     auth,
+		Body:     dto.ResetPasswordRequest{},
+		Response: dto.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest},
+	})
+
+	doc.Add(http.MethodGet, "/.well-known/jwks.json", openapi.Route{
+		Summary:  "Public keys access tokens are signed with, including the next key before it signs",
+		Tags:     auth,
+		Response: jwks.Set{},
+	})
+
+	// Account of the logged in user
+	me := []string{"me"}
+	doc.Add(http.MethodGet, "/api/me", openapi.Route{
+		Summary:  "Get the account of the logged in user",
+		Tags:     me,
+		Response: dto.ProfileResponse{},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPatch, "/api/me", openapi.Route{
+		Summary:  "Change the name, email or avatar of the logged in user, a new email requires the current password and stays pending until verified",
+		Tags:     me,
+		Body:     dto.UpdateProfileRequest{},
+		Response: dto.UserMessageResponse[dto.ProfileResponse]{},
+		Errors:   []int{http.StatusBadRequest, http.StatusConflict},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPut, "/api/me/password", openapi.Route{
+		Summary:  "Change the password of the logged in user, other sessions end and new tokens are returned",
+		Tags:     me,
+		Body:     dto.ChangePasswordRequest{},
+		Response: dto.RefreshTokenResponse{},
+		Errors:   []int{http.StatusBadRequest},
+		Secured:  true,
+	})
+
+	doc.Add(http.MethodPost, "/api/me/mfa/setup", openapi.Route{
+		Summary:  "Get a new secret for an authenticator app, two-factor authentication starts once a code confirms it",
+		Tags:     me,
+		Response: dto.MFASetupResponse{},
+		Errors:   []int{http.StatusConflict},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPost, "/api/me/mfa/confirm", openapi.Route{
+		Summary:  "Enable two-factor authentication with a code of the app, the recovery codes are only returned once",
+		Tags:     me,
+		Body:     dto.MFACodeRequest{},
+		Response: dto.RecoveryCodesResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPost, "/api/me/mfa/recovery-codes", openapi.Route{
+		Summary:  "Replace every recovery code after checking a code of the app",
+		Tags:     me,
+		Body:     dto.MFACodeRequest{},
+		Response: dto.RecoveryCodesResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPost, "/api/me/mfa/disable", openapi.Route{
+		Summary:  "Turn two-factor authentication off with the password and a second factor, unless the role requires it",
+		Tags:     me,
+		Body:     dto.DisableMFARequest{},
+		Response: dto.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusForbidden, http.StatusUnprocessableEntity},
+		Secured:  true,
+	})
+
+	// User management, requires the users:manage permission
+	users := []string{"users"}
+	doc.Add(http.MethodGet, "/api/users", openapi.Route{
+		Summary:  "List users",
+		Tags:     users,
+		Query:    doc.QueryParams(dto.PaginationQuery{}),
+		Response: dto.PaginatedResponse[dto.UserResponse]{},
+		Secured:  true,
+	})
+	doc.Add(http.MethodGet, "/api/users/:id", openapi.Route{
+		Summary:  "Get a user",
+		Tags:     users,
+		Response: entity.User{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPost, "/api/users", openapi.Route{
+		Summary:  "Create a user",
+		Tags:     users,
+		Body:     dto.CreateUserRequest{},
+		Response: dto.UserMessageResponse[*entity.User]{},
+		Status:   http.StatusCreated,
+		Errors:   []int{http.StatusBadRequest, http.StatusConflict},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPut, "/api/users/:id", openapi.Route{
+		Summary:  "Update a user",
+		Tags:     users,
+		Body:     dto.UpdateUserRequest{},
+		Response: dto.UserMessageResponse[dto.UserResponse]{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+	doc.Add(http.MethodDelete, "/api/users/:id", openapi.Route{
+		Summary:  "Delete a user",
+		Tags:     users,
+		Response: dto.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPut, "/api/users/:id/deactivate", openapi.Route{
+		Summary:  "Deactivate a user",
+		Tags:     users,
+		Response: dto.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPut, "/api/users/:id/activate", openapi.Route{
+		Summary:  "Activate a user",
+		Tags:     users,
+		Response: dto.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+
+	// Registration invites, requires the users:manage permission
+	doc.Add(http.MethodGet, "/api/invites", openapi.Route{
+		Summary:  "List the invites that can still be used",
+		Tags:     users,
+		Response: dto.InvitesResponse{},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPost, "/api/invites", openapi.Route{
+		Summary:  "Issue an invite for a role, the token is only returned once",
+		Tags:     users,
+		Body:     dto.CreateInviteRequest{},
+		Response: dto.InviteCreatedResponse{},
+		Status:   http.StatusCreated,
+		Errors:   []int{http.StatusBadRequest},
+		Secured:  true,
+	})
+	doc.Add(http.MethodDelete, "/api/invites/:invite_id", openapi.Route{
+		Summary:  "Revoke an invite",
+		Tags:     users,
+		Response: dto.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+
+	// Role management, requires the roles:manage permission
+	roles := []string{"roles"}
+	doc.Add(http.MethodGet, "/api/roles", openapi.Route{
+		Summary:  "List roles with their permissions",
+		Tags:     roles,
+		Response: dto.RolesResponse{},
+		Secured:  true,
+	})
+	doc.Add(http.MethodGet, "/api/roles/:role_id", openapi.Route{
+		Summary:  "Get a role",
+		Tags:     roles,
+		Response: dto.RoleResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPost, "/api/roles", openapi.Route{
+		Summary:  "Create a role",
+		Tags:     roles,
+		Body:     dto.RoleRequest{},
+		Response: dto.RoleMessageResponse{},
+		Status:   http.StatusCreated,
+		Errors:   []int{http.StatusBadRequest, http.StatusConflict},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPut, "/api/roles/:role_id", openapi.Route{
+		Summary:  "Rename a role and replace its permissions, tokens carry them from their next refresh",
+		Tags:     roles,
+		Body:     dto.RoleRequest{},
+		Response: dto.RoleMessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+		Secured:  true,
+	})
+	doc.Add(http.MethodDelete, "/api/roles/:role_id", openapi.Route{
+		Summary:  "Delete a role no user has",
+		Tags:     roles,
+		Response: dto.MessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+		Secured:  true,
+	})
+	doc.Add(http.MethodPut, "/api/roles/:role_id/mfa", openapi.Route{
+		Summary:  "Require two-factor authentication for the users of a role or stop requiring it, the admin role included, requiring it ends their sessions",
+		Tags:     roles,
+		Body:     dto.RoleMFARequest{},
+		Response: dto.RoleMessageResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		Secured:  true,
+	})
+	doc.Add(http.MethodGet, "/api/permissions", openapi.Route{
+		Summary:  "List the permissions roles can be granted",
+		Tags:     roles,
+		Response: dto.PermissionsResponse{},
+		Secured:  true,
+	})
+
+	return doc
+}
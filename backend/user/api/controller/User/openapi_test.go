@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestSpecCoversRoutes(t *testing.T) {
+	e := echo.New()
+	RegisterUserRoutes(e.Group("/api"), nil, nil, nil, nil, nil, nil)
+	RegisterRoleRoutes(e.Group("/api"), nil, nil)
+	RegisterPasswordRoutes(e.Group("/api"), nil, nil, nil)
+	RegisterInviteRoutes(e.Group("/api"), nil, nil)
+	RegisterKeyRoutes(e, nil)
+
+	doc := Spec()
+	for _, route := range doc.Undocumented(e.Routes()) {
+		t.Errorf("route %s is registered without an OpenAPI operation", route)
+	}
+	for _, operation := range doc.Unregistered(e.Routes()) {
+		t.Errorf("operation %s is documented but no route is registered", operation)
+	}
+
+	if _, err := doc.JSON(); err != nil {
+		t.Fatalf("encoding spec: %v", err)
+	}
+}
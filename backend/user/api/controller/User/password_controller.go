@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/usecase/password"
+	"github.com/yuhari7/backend_supervision/internal/usecase/session"
+	"github.com/yuhari7/backend_supervision/shared/i18n"
+)
+
+// PasswordController handles the password reset endpoints, mails are written in the language of the request
+type PasswordController struct {
+	Usecase    password.PasswordUsecase
+	Sessions   session.SessionUsecase
+	Translator *i18n.Translator
+}
+
+func NewPasswordController(u password.PasswordUsecase, sessions session.SessionUsecase, translator *i18n.Translator) *PasswordController {
+	return &PasswordController{Usecase: u, Sessions: sessions, Translator: translator}
+}
+
+// ForgotPassword mails a reset link, the response is the same whether or not the email is registered
+func (h *PasswordController) ForgotPassword(c echo.Context) error {
+	var input dto.ForgotPasswordRequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	if err := h.Usecase.Forgot(input, h.Translator.FromRequest(c.Request())); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusAccepted, dto.MessageResponse{Message: "if the email is registered, a password reset link has been sent"})
+}
+
+// ResetPassword sets a new password with the token of a reset link and ends every session of the user
+func (h *PasswordController) ResetPassword(c echo.Context) error {
+	var input dto.ResetPasswordRequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	user, err := h.Usecase.Reset(input)
+	if err != nil {
+		return err
+	}
+	h.Sessions.Forget(user.ID)
+
+	return c.JSON(http.StatusOK, dto.MessageResponse{Message: "password has been reset, sign in with the new password"})
+}
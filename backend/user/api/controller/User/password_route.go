@@ -0,0 +1,16 @@
+package controller
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/internal/usecase/password"
+	"github.com/yuhari7/backend_supervision/internal/usecase/session"
+	"github.com/yuhari7/backend_supervision/shared/i18n"
+)
+
+// RegisterPasswordRoutes registers the public password reset routes
+func RegisterPasswordRoutes(e *echo.Group, usecase password.PasswordUsecase, sessions session.SessionUsecase, translator *i18n.Translator) {
+	handler := NewPasswordController(usecase, sessions, translator)
+
+	e.POST("/password/forgot", handler.ForgotPassword)
+	e.POST("/password/reset", handler.ResetPassword)
+}
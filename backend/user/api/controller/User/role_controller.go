@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/usecase/role"
+)
+
+// RoleController handles the role and permission endpoints
+type RoleController struct {
+	Usecase role.RoleUsecase
+}
+
+func NewRoleController(u role.RoleUsecase) *RoleController {
+	return &RoleController{Usecase: u}
+}
+
+func (h *RoleController) ListRoles(c echo.Context) error {
+	roles, err := h.Usecase.ListRoles()
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.RolesResponse{Roles: roles})
+}
+
+func (h *RoleController) GetRole(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("role_id"))
+	if err != nil {
+		return errInvalidRoleID
+	}
+
+	role, err := h.Usecase.GetRole(uint(id))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, role)
+}
+
+func (h *RoleController) CreateRole(c echo.Context) error {
+	var input dto.RoleRequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	role, err := h.Usecase.CreateRole(input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, dto.RoleMessageResponse{Message: "role created successfully", Role: *role})
+}
+
+// UpdateRole renames a role and replaces its permissions, tokens pick them up when they are next refreshed
+func (h *RoleController) UpdateRole(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("role_id"))
+	if err != nil {
+		return errInvalidRoleID
+	}
+
+	var input dto.RoleRequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	role, err := h.Usecase.UpdateRole(uint(id), input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.RoleMessageResponse{Message: "role updated successfully", Role: *role})
+}
+
+// SetRoleMFA turns the two-factor authentication requirement of a role on or off, the admin role included
+func (h *RoleController) SetRoleMFA(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("role_id"))
+	if err != nil {
+		return errInvalidRoleID
+	}
+
+	var input dto.RoleMFARequest
+	if err := c.Bind(&input); err != nil {
+		return errInvalidRequest
+	}
+
+	role, err := h.Usecase.SetRequireMFA(uint(id), input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.RoleMessageResponse{Message: "role updated successfully", Role: *role})
+}
+
+func (h *RoleController) DeleteRole(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("role_id"))
+	if err != nil {
+		return errInvalidRoleID
+	}
+
+	if err := h.Usecase.DeleteRole(uint(id)); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.MessageResponse{Message: "role deleted successfully"})
+}
+
+func (h *RoleController) ListPermissions(c echo.Context) error {
+	permissions, err := h.Usecase.ListPermissions()
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.PermissionsResponse{Permissions: permissions})
+}
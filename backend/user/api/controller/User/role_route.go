@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/yuhari7/backend_supervision/api/middleware"
+	"github.com/yuhari7/backend_supervision/internal/usecase/role"
+	"github.com/yuhari7/backend_supervision/shared/auth"
+)
+
+// RegisterRoleRoutes registers the role management routes, open to callers with the roles:manage permission
+func RegisterRoleRoutes(e *echo.Group, usecase role.RoleUsecase, sessions auth.Authenticator) {
+	handler := NewRoleController(usecase)
+	manage := []echo.MiddlewareFunc{middleware.AuthMiddleware(sessions), middleware.RequirePermission("roles:manage")}
+
+	roles := e.Group("/roles", manage...)
+	roles.GET("", handler.ListRoles)
+	roles.GET("/:role_id", handler.GetRole)
+	roles.POST("", handler.CreateRole)
+	roles.PUT("/:role_id", handler.UpdateRole)
+	roles.DELETE("/:role_id", handler.DeleteRole)
+	roles.PUT("/:role_id/mfa", handler.SetRoleMFA)
+
+	e.GET("/permissions", handler.ListPermissions, manage...)
+}
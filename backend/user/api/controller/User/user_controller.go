@@ -1,40 +1,53 @@
 package controller
 
 import (
+	"log"
 	"net/http"
 	"strconv"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 
 	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/usecase/mfa"
+	"github.com/yuhari7/backend_supervision/internal/usecase/registration"
+	"github.com/yuhari7/backend_supervision/internal/usecase/session"
 	"github.com/yuhari7/backend_supervision/internal/usecase/user"
-	jwtutil "github.com/yuhari7/backend_supervision/pkg/jwt"
+	"github.com/yuhari7/backend_supervision/internal/usecase/verification"
+	"github.com/yuhari7/backend_supervision/shared/auth"
+	"github.com/yuhari7/backend_supervision/shared/i18n"
 )
 
+// UserController handles the user endpoints, request bodies are validated
+// against the OpenAPI document by middleware before they reach the handlers
 type UserController struct {
-	Usecase user.UserUsecase
+	Usecase      user.UserUsecase
+	Sessions     session.SessionUsecase
+	Verification verification.VerificationUsecase
+	Registration registration.RegistrationUsecase
+	MFA          mfa.MFAUsecase
+	Translator   *i18n.Translator // picks the language of mails from the request
 }
 
-func NewUserController(u user.UserUsecase) *UserController {
-	return &UserController{Usecase: u}
+func NewUserController(u user.UserUsecase, sessions session.SessionUsecase, verification verification.VerificationUsecase, registration registration.RegistrationUsecase, mfa mfa.MFAUsecase, translator *i18n.Translator) *UserController {
+	return &UserController{Usecase: u, Sessions: sessions, Verification: verification, Registration: registration, MFA: mfa, Translator: translator}
 }
 
+// Register creates a self-registered account, with the role of its invite or the default role
 func (h *UserController) Register(c echo.Context) error {
-	var input dto.CreateUserRequest
+	var input dto.RegisterRequest
 	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request"})
+		return errInvalidRequest
 	}
 
-	// Validasi dengan validator
-	validate := validator.New()
-	if err := validate.Struct(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	newUser, err := h.Registration.Register(input)
+	if err != nil {
+		return err
 	}
 
-	newUser, err := h.Usecase.Register(input)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	// The account exists either way, a failed mail can be sent again through the resend endpoint
+	if err := h.Verification.Send(newUser, h.Translator.FromRequest(c.Request())); err != nil {
+		log.Printf("Failed to send the verification mail of user %d: %v", newUser.ID, err)
 	}
 
 	response := dto.UserResponse{
@@ -44,79 +57,104 @@ func (h *UserController) Register(c echo.Context) error {
 		Role:  newUser.RoleID,
 	}
 
-	return c.JSON(http.StatusCreated, echo.Map{
-		"message": "user registered successfully",
-		"user":    response,
+	return c.JSON(http.StatusCreated, dto.UserMessageResponse[dto.UserResponse]{
+		Message: "user registered successfully, check your email to verify it",
+		User:    response,
 	})
 }
 
-// Login handler
+// Login checks the password. When a second factor is needed the response only carries an mfa_token
+// to finish the login with, otherwise the tokens of a new session.
 func (h *UserController) Login(c echo.Context) error {
 	var input dto.LoginRequest
 	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request"})
-	}
-
-	// Validasi input login
-	validate := validator.New()
-	if err := validate.Struct(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return errInvalidRequest
 	}
 
 	// Call usecase login
 	user, err := h.Usecase.Login(input)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, echo.Map{"error": err.Error()})
+		return err
 	}
 
-	// Generate tokens (AccessToken and RefreshToken)
-	accessToken, err := jwtutil.GenerateAccessToken(user.ID, user.Email, user.RoleID)
+	challenge, err := h.MFA.Challenge(user)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "failed to generate access token"})
+		return err
+	}
+	if challenge != nil {
+		return c.JSON(http.StatusOK, dto.LoginResponse{
+			MFARequired:      true,
+			MFASetupRequired: challenge.SetupRequired,
+			MFAToken:         challenge.Token,
+		})
 	}
 
-	refreshToken, err := jwtutil.GenerateRefreshToken(user.ID, user.Email, user.RoleID)
+	return h.startSession(c, user, nil)
+}
+
+// startSession responds with the tokens of a new session (AccessToken and RefreshToken)
+func (h *UserController) startSession(c echo.Context, user *entity.User, recoveryCodes []string) error {
+	tokens, err := h.Sessions.Start(user)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "failed to generate refresh token"})
+		return err
 	}
 
 	response := dto.LoginResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		User: dto.UserResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		User: &dto.UserResponse{
 			ID:    user.ID,
 			Name:  user.Name,
 			Email: user.Email,
 			Role:  user.RoleID,
 		},
+		RecoveryCodes: recoveryCodes,
 	}
 
 	return c.JSON(http.StatusOK, response)
 }
 
+// RefreshToken rotates the refresh token, the one sent cannot be used again
 func (h *UserController) RefreshToken(c echo.Context) error {
-	var body struct {
-		RefreshToken string `json:"refresh_token"`
+	var body dto.RefreshTokenRequest
+	if err := c.Bind(&body); err != nil {
+		return errInvalidRequest
+	}
+
+	tokens, err := h.Sessions.Refresh(body.RefreshToken)
+	if err != nil {
+		return err
 	}
 
+	return c.JSON(http.StatusOK, dto.RefreshTokenResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	})
+}
+
+// Logout ends the session of a refresh token
+func (h *UserController) Logout(c echo.Context) error {
+	var body dto.RefreshTokenRequest
 	if err := c.Bind(&body); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request"})
+		return errInvalidRequest
 	}
 
-	claims, err := jwtutil.ParseRefreshToken(body.RefreshToken)
-	if err != nil {
-		return c.JSON(http.StatusUnauthorized, echo.Map{"error": "invalid or expired refresh token"})
+	if err := h.Sessions.Logout(body.RefreshToken); err != nil {
+		return err
 	}
 
-	// generate new access token
-	newAccessToken, err := jwtutil.GenerateAccessToken(claims.UserID, claims.Email, claims.RoleID)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "failed to generate access token"})
+	return c.JSON(http.StatusOK, dto.MessageResponse{Message: "logged out"})
+}
+
+// LogoutAll ends every session of the logged in user
+func (h *UserController) LogoutAll(c echo.Context) error {
+	principal, _ := auth.PrincipalFrom(c.Request().Context())
+
+	if err := h.Sessions.LogoutAll(principal.UserID); err != nil {
+		return err
 	}
 
-	return c.JSON(http.StatusOK, echo.Map{
-		"access_token": newAccessToken,
-	})
+	return c.JSON(http.StatusOK, dto.MessageResponse{Message: "logged out of all sessions"})
 }
 
 // Users
@@ -129,7 +167,7 @@ func (h *UserController) GetAllUsers(c echo.Context) error {
 
 	result, err := h.Usecase.GetAllUsers(pagination)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, result)
@@ -139,12 +177,12 @@ func (h *UserController) GetUserByID(c echo.Context) error {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid user id"})
+		return errInvalidUserID
 	}
 
 	user, err := h.Usecase.GetUserByID(uint(id))
 	if err != nil {
-		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(http.StatusOK, user)
@@ -153,24 +191,17 @@ func (h *UserController) GetUserByID(c echo.Context) error {
 func (h *UserController) CreateUser(c echo.Context) error {
 	var input dto.CreateUserRequest
 	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request"})
-	}
-
-	// Validasi dengan validator
-	validate := validator.New()
-	if err := validate.Struct(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return errInvalidRequest
 	}
 
-	// Bisa reuse usecase Register karena logikanya sama
-	newUser, err := h.Usecase.Register(input)
+	newUser, err := h.Usecase.CreateUser(input)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return err
 	}
 
-	return c.JSON(http.StatusCreated, echo.Map{
-		"message": "user created successfully",
-		"user":    newUser,
+	return c.JSON(http.StatusCreated, dto.UserMessageResponse[*entity.User]{
+		Message: "user created successfully",
+		User:    newUser,
 	})
 }
 
@@ -178,23 +209,19 @@ func (h *UserController) UpdateUser(c echo.Context) error {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid user id"})
+		return errInvalidUserID
 	}
 
 	var input dto.UpdateUserRequest
 	if err := c.Bind(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request"})
-	}
-
-	validate := validator.New()
-	if err := validate.Struct(&input); err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return errInvalidRequest
 	}
 
 	updatedUser, err := h.Usecase.UpdateUser(uint(id), input)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return err
 	}
+	h.Sessions.Forget(updatedUser.ID)
 
 	response := dto.UserResponse{
 		ID:    updatedUser.ID,
@@ -203,9 +230,9 @@ func (h *UserController) UpdateUser(c echo.Context) error {
 		Role:  updatedUser.RoleID,
 	}
 
-	return c.JSON(http.StatusOK, echo.Map{
-		"message": "user updated successfully",
-		"user":    response,
+	return c.JSON(http.StatusOK, dto.UserMessageResponse[dto.UserResponse]{
+		Message: "user updated successfully",
+		User:    response,
 	})
 }
 
@@ -213,47 +240,44 @@ func (h *UserController) DeleteUser(c echo.Context) error {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid user id"})
+		return errInvalidUserID
 	}
 
 	err = h.Usecase.DeleteUser(uint(id))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return err
 	}
+	h.Sessions.Forget(uint(id))
 
-	return c.JSON(http.StatusOK, echo.Map{"message": "user deleted successfully"})
+	return c.JSON(http.StatusOK, dto.MessageResponse{Message: "user deleted successfully"})
 }
 
 func (h *UserController) DeactivateUser(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid user id"})
+		return errInvalidUserID
 	}
 
 	err = h.Usecase.ToggleUserActive(uint(id), false)
 	if err != nil {
-		if err.Error() == "user not found" {
-			return c.JSON(http.StatusNotFound, echo.Map{"error": "user not found"})
-		}
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return err
 	}
+	h.Sessions.Forget(uint(id))
 
-	return c.JSON(http.StatusOK, echo.Map{"message": "user deactivated"})
+	return c.JSON(http.StatusOK, dto.MessageResponse{Message: "user deactivated"})
 }
 
 func (h *UserController) ActivateUser(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid user id"})
+		return errInvalidUserID
 	}
 
 	err = h.Usecase.ToggleUserActive(uint(id), true)
 	if err != nil {
-		if err.Error() == "user not found" {
-			return c.JSON(http.StatusNotFound, echo.Map{"error": "user not found"})
-		}
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		return err
 	}
+	h.Sessions.Forget(uint(id))
 
-	return c.JSON(http.StatusOK, echo.Map{"message": "user activated"})
+	return c.JSON(http.StatusOK, dto.MessageResponse{Message: "user activated"})
 }
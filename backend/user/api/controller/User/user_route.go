@@ -3,17 +3,38 @@ package controller
 import (
 	"github.com/labstack/echo/v4"
 	"github.com/yuhari7/backend_supervision/api/middleware"
+	"github.com/yuhari7/backend_supervision/internal/usecase/mfa"
+	"github.com/yuhari7/backend_supervision/internal/usecase/registration"
+	"github.com/yuhari7/backend_supervision/internal/usecase/session"
 	"github.com/yuhari7/backend_supervision/internal/usecase/user"
+	"github.com/yuhari7/backend_supervision/internal/usecase/verification"
+	"github.com/yuhari7/backend_supervision/shared/i18n"
 )
 
-func RegisterUserRoutes(e *echo.Group, usecase user.UserUsecase) {
-	handler := NewUserController(usecase)
+func RegisterUserRoutes(e *echo.Group, usecase user.UserUsecase, sessions session.SessionUsecase, verification verification.VerificationUsecase, registration registration.RegistrationUsecase, mfa mfa.MFAUsecase, translator *i18n.Translator) {
+	handler := NewUserController(usecase, sessions, verification, registration, mfa, translator)
 
 	e.POST("/register", handler.Register)
+	e.POST("/email/verify", handler.VerifyEmail)
+	e.POST("/email/resend", handler.ResendVerification)
 	e.POST("/login", handler.Login)
+	e.POST("/login/mfa", handler.VerifyLoginMFA)
+	e.POST("/login/mfa/setup", handler.SetupLoginMFA)
 	e.POST("/refresh", handler.RefreshToken)
+	e.POST("/logout", handler.Logout)
+	auth := middleware.AuthMiddleware(sessions)
+	e.POST("/logout-all", handler.LogoutAll, auth)
 
-	protected := e.Group("/users", middleware.AuthMiddleware, middleware.AdminOnlyMiddleware)
+	me := e.Group("/me", auth)
+	me.GET("", handler.GetMe)
+	me.PATCH("", handler.UpdateMe)
+	me.PUT("/password", handler.ChangeMyPassword)
+	me.POST("/mfa/setup", handler.SetupMyMFA)
+	me.POST("/mfa/confirm", handler.ConfirmMyMFA)
+	me.POST("/mfa/recovery-codes", handler.RegenerateMyRecoveryCodes)
+	me.POST("/mfa/disable", handler.DisableMyMFA)
+
+	protected := e.Group("/users", auth, middleware.RequirePermission("users:manage"))
 	protected.GET("", handler.GetAllUsers)
 	protected.GET("/:id", handler.GetUserByID)
 	protected.POST("", handler.CreateUser)
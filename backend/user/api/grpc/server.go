@@ -0,0 +1,56 @@
+// Package grpcapi serves the internal gRPC API of the user service.
+package grpcapi
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/yuhari7/backend_supervision/internal/usecase/user"
+	jwtutil "github.com/yuhari7/backend_supervision/pkg/jwt"
+	"github.com/yuhari7/backend_supervision/pkg/servicetoken"
+	userv1 "github.com/yuhari7/backend_supervision/shared/proto/user/v1"
+)
+
+// NewServer creates a gRPC server exposing the UserService and the standard health service.
+// Calls to the UserService must present one of the service tokens, access tokens are validated with keys.
+func NewServer(usecase user.UserUsecase, keys jwtutil.Keys, serviceTokens []string) *grpc.Server {
+	if len(serviceTokens) == 0 {
+		log.Println("No service tokens configured, every UserService call will be rejected")
+	}
+
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(serviceAuth(serviceTokens)))
+	userv1.RegisterUserServiceServer(server, &userService{usecase: usecase, keys: keys})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(userv1.UserService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	return server
+}
+
+// serviceAuth checks the "authorization: Bearer <token>" metadata of every call except health checks
+func serviceAuth(tokens []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, value := range md.Get("authorization") {
+			token, ok := strings.CutPrefix(value, "Bearer ")
+			if ok && servicetoken.Valid(tokens, token) {
+				return handler(ctx, req)
+			}
+		}
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid service token")
+	}
+}
@@ -0,0 +1,234 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/testutil"
+	"github.com/yuhari7/backend_supervision/internal/usecase/user"
+	jwtutil "github.com/yuhari7/backend_supervision/pkg/jwt"
+	"github.com/yuhari7/backend_supervision/shared/apperror"
+	userv1 "github.com/yuhari7/backend_supervision/shared/proto/user/v1"
+)
+
+const serviceToken = "article-service-token"
+
+// staticKeys signs with a single key, standing in for the keyring
+type staticKeys struct {
+	kid string
+	key ed25519.PrivateKey
+}
+
+func newStaticKeys() staticKeys {
+	_, key, _ := ed25519.GenerateKey(nil)
+	return staticKeys{kid: "test", key: key}
+}
+
+func (k staticKeys) SigningKey() (string, ed25519.PrivateKey, error) {
+	return k.kid, k.key, nil
+}
+
+func (k staticKeys) PublicKey(kid string) (ed25519.PublicKey, error) {
+	if kid != k.kid {
+		return nil, jwtutil.ErrUnknownKey
+	}
+	return k.key.Public().(ed25519.PublicKey), nil
+}
+
+// dial serves the API over an in-memory connection and returns a connection to it
+func dial(t *testing.T, users *testutil.UserRepository, keys jwtutil.Keys) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(user.NewUserUsecase(users, &testutil.Transactor{Users: users}), keys, []string{serviceToken})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// withToken adds the service token to the outgoing metadata
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func newUsers() *testutil.UserRepository {
+	return testutil.NewUserRepository(
+		entity.User{ID: 1, Name: "Budi", Email: "budi@example.com", RoleID: 2, IsActive: true, TokenVersion: 1},
+		entity.User{ID: 2, Name: "Sari", Email: "sari@example.com", RoleID: 2, IsActive: true, TokenVersion: 1},
+		entity.User{ID: 3, Name: "Dewi", Email: "dewi@example.com", RoleID: 2, IsActive: false, TokenVersion: 1},
+		entity.User{ID: 4, Name: "Admin", Email: "admin@example.com", RoleID: 1, IsActive: true, TokenVersion: 1},
+	)
+}
+
+func TestServiceTokenIsRequired(t *testing.T) {
+	conn := dial(t, newUsers(), newStaticKeys())
+	client := userv1.NewUserServiceClient(conn)
+
+	for name, ctx := range map[string]context.Context{
+		"missing":   context.Background(),
+		"invalid":   withToken("made-up"),
+		"no bearer": metadata.AppendToOutgoingContext(context.Background(), "authorization", serviceToken),
+	} {
+		if _, err := client.GetUser(ctx, &userv1.GetUserRequest{Id: 1}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("got error %v for a %s token, want Unauthenticated", err, name)
+		}
+	}
+	if _, err := client.GetUser(withToken(serviceToken), &userv1.GetUserRequest{Id: 1}); err != nil {
+		t.Errorf("unexpected error with the service token: %v", err)
+	}
+
+	// Health checks work without a token
+	health, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: userv1.UserService_ServiceDesc.ServiceName})
+	if err != nil || health.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("got health %v and error %v, want SERVING", health, err)
+	}
+}
+
+func TestValidateToken(t *testing.T) {
+	users := newUsers()
+	keys := newStaticKeys()
+	client := userv1.NewUserServiceClient(dial(t, users, keys))
+
+	token := func(userID, version uint, keys jwtutil.Keys) string {
+		t.Helper()
+		signed, err := jwtutil.GenerateAccessToken(keys, jwtutil.CustomClaims{UserID: userID, TokenVersion: version})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	valid, err := client.ValidateToken(withToken(serviceToken), &userv1.ValidateTokenRequest{AccessToken: token(1, 1, keys)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !valid.GetValid() || valid.GetUser().GetId() != 1 || valid.GetExpiresAt() == 0 {
+		t.Errorf("got %v, want a valid token of user 1 with its expiry", valid)
+	}
+
+	tests := map[string]string{
+		"malformed":         "not-a-token",
+		"other key":         token(1, 1, newStaticKeys()),
+		"inactive user":     token(3, 1, keys),
+		"unknown user":      token(99, 1, keys),
+		"old token version": token(2, 0, keys),
+	}
+	for name, accessToken := range tests {
+		response, err := client.ValidateToken(withToken(serviceToken), &userv1.ValidateTokenRequest{AccessToken: accessToken})
+		if err != nil || response.GetValid() || response.GetUser() != nil {
+			t.Errorf("got %v and error %v for the %s token, want it invalid", response, err, name)
+		}
+	}
+
+	// A broken database is reported rather than taken for an invalid token
+	users.Err = errors.New("connection reset")
+	if _, err := client.ValidateToken(withToken(serviceToken), &userv1.ValidateTokenRequest{AccessToken: token(1, 1, keys)}); status.Code(err) != codes.Internal {
+		t.Errorf("got error %v, want Internal", err)
+	}
+}
+
+func TestBatchGetUsers(t *testing.T) {
+	client := userv1.NewUserServiceClient(dial(t, newUsers(), newStaticKeys()))
+
+	response, err := client.BatchGetUsers(withToken(serviceToken), &userv1.BatchGetUsersRequest{Ids: []uint64{2, 99, 1, 2, 99}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []uint64
+	for _, u := range response.GetUsers() {
+		ids = append(ids, u.GetId())
+	}
+	if len(ids) != 2 || ids[0] != 2 || ids[1] != 1 {
+		t.Errorf("got users %v, want 2 and 1 once each", ids)
+	}
+	if missing := response.GetMissingIds(); len(missing) != 1 || missing[0] != 99 {
+		t.Errorf("got missing IDs %v, want 99 once", missing)
+	}
+
+	tooMany := make([]uint64, 501)
+	if _, err := client.BatchGetUsers(withToken(serviceToken), &userv1.BatchGetUsersRequest{Ids: tooMany}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got error %v for too many IDs, want InvalidArgument", err)
+	}
+}
+
+func TestListUsersByRolePages(t *testing.T) {
+	client := userv1.NewUserServiceClient(dial(t, newUsers(), newStaticKeys()))
+
+	var ids []uint64
+	request := &userv1.ListUsersByRoleRequest{RoleId: 2, PageSize: 2}
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("paging did not end")
+		}
+		response, err := client.ListUsersByRole(withToken(serviceToken), request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, u := range response.GetUsers() {
+			ids = append(ids, u.GetId())
+		}
+		if response.GetNextPageToken() == "" {
+			break
+		}
+		request.PageToken = response.GetNextPageToken()
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+		t.Errorf("got users %v, want every user of the role once in ID order", ids)
+	}
+
+	for _, token := range []string{"next", "-2"} {
+		_, err := client.ListUsersByRole(withToken(serviceToken), &userv1.ListUsersByRoleRequest{RoleId: 2, PageToken: token})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("got error %v for page token %q, want InvalidArgument", err, token)
+		}
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{apperror.Validation("invalid", "invalid", nil), codes.InvalidArgument},
+		{apperror.Unauthorized("unauthorized", "unauthorized"), codes.Unauthenticated},
+		{apperror.Forbidden("forbidden", "forbidden"), codes.PermissionDenied},
+		{user.ErrUserNotFound, codes.NotFound},
+		{apperror.Conflict("conflict", "conflict"), codes.AlreadyExists},
+		{apperror.TooManyRequests("limited", "limited"), codes.ResourceExhausted},
+		{errors.New("connection reset"), codes.Internal},
+	}
+	for _, tt := range tests {
+		got := status.Convert(toStatus(tt.err))
+		if got.Code() != tt.want {
+			t.Errorf("got code %v for %v, want %v", got.Code(), tt.err, tt.want)
+		}
+	}
+
+	// Internal errors do not leak their details
+	if got := status.Convert(toStatus(errors.New("password=secret"))); got.Message() != "internal error" {
+		t.Errorf("got message %q, want it hidden", got.Message())
+	}
+	if got := status.Convert(toStatus(user.ErrUserNotFound)); got.Message() != "user_not_found" {
+		t.Errorf("got message %q, want the error code", got.Message())
+	}
+}
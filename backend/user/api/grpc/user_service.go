@@ -0,0 +1,150 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/usecase/user"
+	jwtutil "github.com/yuhari7/backend_supervision/pkg/jwt"
+	"github.com/yuhari7/backend_supervision/shared/apperror"
+	userv1 "github.com/yuhari7/backend_supervision/shared/proto/user/v1"
+)
+
+// userService implements userv1.UserServiceServer on top of the UserUsecase
+type userService struct {
+	userv1.UnimplementedUserServiceServer
+	usecase user.UserUsecase
+	keys    jwtutil.Keys
+}
+
+func (s *userService) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*userv1.GetUserResponse, error) {
+	found, err := s.usecase.GetUserByID(uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &userv1.GetUserResponse{User: toProto(*found)}, nil
+}
+
+func (s *userService) BatchGetUsers(ctx context.Context, req *userv1.BatchGetUsersRequest) (*userv1.BatchGetUsersResponse, error) {
+	ids := make([]uint, 0, len(req.GetIds()))
+	for _, id := range req.GetIds() {
+		ids = append(ids, uint(id))
+	}
+
+	users, err := s.usecase.GetUsersByIDs(ids)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &userv1.BatchGetUsersResponse{Users: make([]*userv1.User, 0, len(users))}
+	found := make(map[uint64]bool, len(users))
+	for _, u := range users {
+		response.Users = append(response.Users, toProto(u))
+		found[uint64(u.ID)] = true
+	}
+	for _, id := range req.GetIds() {
+		if !found[id] {
+			response.MissingIds = append(response.MissingIds, id)
+			found[id] = true
+		}
+	}
+	return response, nil
+}
+
+func (s *userService) ValidateToken(ctx context.Context, req *userv1.ValidateTokenRequest) (*userv1.ValidateTokenResponse, error) {
+	claims, err := jwtutil.ParseAccessToken(s.keys, req.GetAccessToken())
+	if err != nil {
+		return &userv1.ValidateTokenResponse{Valid: false}, nil
+	}
+
+	// A valid signature is not enough, the user must still exist and be active and the token not revoked
+	found, err := s.usecase.GetUserByID(claims.UserID)
+	if errors.Is(err, user.ErrUserNotFound) {
+		return &userv1.ValidateTokenResponse{Valid: false}, nil
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if !found.IsActive || found.TokenVersion != claims.TokenVersion {
+		return &userv1.ValidateTokenResponse{Valid: false}, nil
+	}
+
+	response := &userv1.ValidateTokenResponse{Valid: true, User: toProto(*found)}
+	if claims.ExpiresAt != nil {
+		response.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	return response, nil
+}
+
+func (s *userService) ListUsersByRole(ctx context.Context, req *userv1.ListUsersByRoleRequest) (*userv1.ListUsersByRoleResponse, error) {
+	// The page token is the offset of the next page
+	offset := 0
+	if token := req.GetPageToken(); token != "" {
+		parsed, err := strconv.Atoi(token)
+		if err != nil || parsed < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+		offset = parsed
+	}
+
+	limit := user.PageSize(int(req.GetPageSize()))
+	users, err := s.usecase.ListUsersByRole(uint(req.GetRoleId()), limit, offset)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &userv1.ListUsersByRoleResponse{Users: make([]*userv1.User, 0, len(users))}
+	for _, u := range users {
+		response.Users = append(response.Users, toProto(u))
+	}
+	// A full page means there may be more users
+	if len(users) == limit {
+		response.NextPageToken = strconv.Itoa(offset + len(users))
+	}
+	return response, nil
+}
+
+func toProto(u entity.User) *userv1.User {
+	return &userv1.User{
+		Id:        uint64(u.ID),
+		Name:      u.Name,
+		Email:     u.Email,
+		RoleId:    uint64(u.RoleID),
+		IsActive:  u.IsActive,
+		AvatarUrl: u.AvatarURL(),
+	}
+}
+
+// toStatus maps domain errors to gRPC status codes, other errors are reported as internal
+func toStatus(err error) error {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		log.Println("gRPC internal error:", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	code := codes.Internal
+	switch appErr.Kind {
+	case apperror.KindValidation:
+		code = codes.InvalidArgument
+	case apperror.KindUnauthorized:
+		code = codes.Unauthenticated
+	case apperror.KindForbidden:
+		code = codes.PermissionDenied
+	case apperror.KindNotFound:
+		code = codes.NotFound
+	case apperror.KindConflict:
+		code = codes.AlreadyExists
+	case apperror.KindUnprocessable:
+		code = codes.FailedPrecondition
+	case apperror.KindTooManyRequests:
+		code = codes.ResourceExhausted
+	}
+	return status.Error(code, appErr.Code)
+}
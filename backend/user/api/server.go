@@ -1,31 +1,107 @@
 package api
 
 import (
+	"os"
+	"strconv"
+	"time"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
 	controller "github.com/yuhari7/backend_supervision/api/controller/User"
 	"github.com/yuhari7/backend_supervision/config"
 	"github.com/yuhari7/backend_supervision/internal/repository"
+	"github.com/yuhari7/backend_supervision/internal/usecase/keyring"
+	"github.com/yuhari7/backend_supervision/internal/usecase/mfa"
+	"github.com/yuhari7/backend_supervision/internal/usecase/password"
+	"github.com/yuhari7/backend_supervision/internal/usecase/registration"
+	"github.com/yuhari7/backend_supervision/internal/usecase/role"
+	"github.com/yuhari7/backend_supervision/internal/usecase/session"
 	"github.com/yuhari7/backend_supervision/internal/usecase/user"
+	"github.com/yuhari7/backend_supervision/internal/usecase/verification"
+	"github.com/yuhari7/backend_supervision/pkg/mailer"
+	"github.com/yuhari7/backend_supervision/shared/i18n"
+	"github.com/yuhari7/backend_supervision/shared/openapi"
+	"github.com/yuhari7/backend_supervision/shared/problem"
 )
 
-func NewServer() *echo.Echo {
+func NewServer(keys keyring.Keyring, mail mailer.Mailer) *echo.Echo {
+	// Validation and error messages follow the Accept-Language header, Indonesian by default
+	translator := i18n.MustNew("id")
+
 	e := echo.New()
+	e.HTTPErrorHandler = problem.NewHTTPErrorHandler(translator)
 
-	// Set up CORS middleware
+	// Set up CORS middleware first, so the frontend can also read the errors of the middlewares after it
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"http://localhost:3000"},
 		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept},
 	}))
 
+	// Requests are validated against the OpenAPI document, responses too when OPENAPI_VALIDATE_RESPONSES is set
+	spec := controller.Spec()
+	validateResponses, _ := strconv.ParseBool(os.Getenv("OPENAPI_VALIDATE_RESPONSES"))
+	e.Use(spec.Validator(openapi.ValidatorConfig{Translator: translator, ValidateResponses: validateResponses}))
+
 	// Dependency injection
+	// Other instances notice a deactivation or role change once their cached token version expires
+	sessionCacheTTL := 5 * time.Second
 	userRepo := repository.NewUserRepository(config.DB)
-	userUsecase := user.NewUserUsecase(userRepo)
+	roleRepo := repository.NewRoleRepository(config.DB)
+	transactor := repository.NewTransactor(config.DB)
+	userUsecase := user.NewUserUsecase(userRepo, transactor)
+	roleUsecase := role.NewRoleUsecase(roleRepo, transactor)
+	mfaRepo := repository.NewMFARepository(config.DB)
+	sessionUsecase := session.NewSessionUsecase(userRepo, roleRepo, mfaRepo, repository.NewRefreshTokenRepository(config.DB), transactor, keys, sessionCacheTTL)
+
+	// Password reset links open PASSWORD_RESET_URL on the frontend
+	passwordResetTTL, _ := time.ParseDuration(os.Getenv("PASSWORD_RESET_TTL"))
+	userTokenRepo := repository.NewUserTokenRepository(config.DB)
+	passwordUsecase := password.NewPasswordUsecase(userRepo, userTokenRepo, transactor, mail, password.Config{
+		ResetURL: os.Getenv("PASSWORD_RESET_URL"),
+		TokenTTL: passwordResetTTL,
+	})
+
+	// Verification links open EMAIL_VERIFY_URL on the frontend
+	verificationUsecase := verification.NewVerificationUsecase(userRepo, userTokenRepo, mail, verification.Config{
+		VerifyURL: os.Getenv("EMAIL_VERIFY_URL"),
+	})
+
+	// Authenticator apps list the accounts under MFA_ISSUER, their TOTP secrets are stored encrypted with MFA_SECRET_KEY
+	mfaUsecase, err := mfa.NewMFAUsecase(userRepo, roleRepo, mfaRepo, userTokenRepo, mfa.Config{
+		Issuer:    os.Getenv("MFA_ISSUER"),
+		SecretKey: os.Getenv("MFA_SECRET_KEY"),
+	})
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+
+	// Public registration follows REGISTRATION_MODE and gives REGISTRATION_DEFAULT_ROLE unless an invite says otherwise
+	inviteTTL, _ := time.ParseDuration(os.Getenv("INVITE_TTL"))
+	registrationUsecase, err := registration.NewRegistrationUsecase(roleRepo, repository.NewInviteRepository(config.DB), transactor, registration.Config{
+		Mode:        os.Getenv("REGISTRATION_MODE"),
+		DefaultRole: os.Getenv("REGISTRATION_DEFAULT_ROLE"),
+		InviteURL:   os.Getenv("INVITE_URL"),
+		InviteTTL:   inviteTTL,
+	})
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
 
 	// Register routes
 	api := e.Group("/api")
-	controller.RegisterUserRoutes(api, userUsecase)
+	controller.RegisterUserRoutes(api, userUsecase, sessionUsecase, verificationUsecase, registrationUsecase, mfaUsecase, translator)
+	controller.RegisterInviteRoutes(api, registrationUsecase, sessionUsecase)
+	controller.RegisterRoleRoutes(api, roleUsecase, sessionUsecase)
+	controller.RegisterPasswordRoutes(api, passwordUsecase, sessionUsecase, translator)
+
+	// Public keys of the access tokens, for other services verifying them
+	controller.RegisterKeyRoutes(e, keys)
+
+	// API documentation at /openapi.json and /docs
+	if err := openapi.Register(e, spec); err != nil {
+		e.Logger.Fatal(err)
+	}
 
 	return e
 }
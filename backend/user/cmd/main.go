@@ -1,13 +1,88 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/yuhari7/backend_supervision/api"
+	grpcapi "github.com/yuhari7/backend_supervision/api/grpc"
 	"github.com/yuhari7/backend_supervision/config"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	"github.com/yuhari7/backend_supervision/internal/usecase/keyring"
+	"github.com/yuhari7/backend_supervision/internal/usecase/user"
+	"github.com/yuhari7/backend_supervision/pkg/mailer"
+	"github.com/yuhari7/backend_supervision/pkg/servicetoken"
+	"github.com/yuhari7/backend_supervision/shared/outbox"
 )
 
 func main() {
 	config.InitDB()
 
-	e := api.NewServer()
-	e.Logger.Fatal(e.Start(":8080"))
+	// Domain events are written to the outbox with every change and relayed to the broker chosen by OUTBOX_BROKER
+	broker, err := outbox.BrokerFromEnv(outbox.NewMemoryBroker())
+	if err != nil {
+		log.Fatal(err)
+	}
+	outboxRelay := outbox.NewRelay(outbox.NewGormStore(config.DB), broker, outbox.RelayConfig{})
+	outboxRelay.Start()
+
+	// Access tokens are signed with Ed25519 keys replaced every JWT_KEY_ROTATION and published at /.well-known/jwks.json
+	keyRotation, _ := time.ParseDuration(os.Getenv("JWT_KEY_ROTATION"))
+	keys := keyring.NewKeyring(repository.NewSigningKeyRepository(config.DB), keyring.Config{RotateEvery: keyRotation})
+	if err := keys.Rotate(); err != nil {
+		log.Fatal(err)
+	}
+	keys.Start()
+
+	// Internal gRPC API for other services, served on its own port
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+	listener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.Fatalf("❌ Failed to listen on gRPC port %s: %v", grpcPort, err)
+	}
+	grpcServer := grpcapi.NewServer(user.NewUserUsecase(repository.NewUserRepository(config.DB), repository.NewTransactor(config.DB)), keys, servicetoken.FromEnv())
+	go func() {
+		log.Println("✅ Starting gRPC server on port " + grpcPort + "...")
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Fatalf("❌ gRPC server stopped: %v", err)
+		}
+	}()
+
+	// Mails go through the driver chosen by MAIL_DRIVER, which has to be set
+	mail, err := mailer.FromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	e := api.NewServer(keys, mail)
+
+	// The servers run until SIGINT or SIGTERM, requests in flight finish before the relay and the key rotation stop
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		if err := e.Start(":8080"); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+	<-ctx.Done()
+
+	log.Println("Shutting down the servers...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		log.Println("Failed to shut down the server:", err)
+	}
+	grpcServer.GracefulStop()
+	outboxRelay.Stop()
+	keys.Stop()
 }
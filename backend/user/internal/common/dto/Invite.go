@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// CreateInviteRequest issues an invite for role_id, bound to email when it is given
+type CreateInviteRequest struct {
+	Email  string `json:"email,omitempty" validate:"omitempty,email"`
+	RoleID uint   `json:"role_id" validate:"required"`
+}
+
+type InviteResponse struct {
+	ID        uint      `json:"id"`
+	Email     string    `json:"email,omitempty"`
+	RoleID    uint      `json:"role_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InviteCreatedResponse carries the invite token, it is only shown once
+type InviteCreatedResponse struct {
+	Invite InviteResponse `json:"invite"`
+	Token  string         `json:"token"`
+	URL    string         `json:"url"`
+}
+
+type InvitesResponse struct {
+	Invites []InviteResponse `json:"invites"`
+}
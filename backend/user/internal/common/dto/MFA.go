@@ -0,0 +1,37 @@
+package dto
+
+// MFALoginRequest finishes a login that returned an mfa_token, with a code of the authenticator app or a recovery code.
+// While setting up two-factor authentication during login only a code of the app is accepted.
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code,omitempty" validate:"omitempty,len=6"`
+	RecoveryCode string `json:"recovery_code,omitempty" validate:"omitempty,max=32"`
+}
+
+// MFATokenRequest starts setting up two-factor authentication during a login that requires it
+type MFATokenRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+// MFASetupResponse carries the secret to add to an authenticator app, directly or as a QR code of the otpauth URL
+type MFASetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+	MFAToken   string `json:"mfa_token,omitempty"` // replaces the one sent when setting up during login
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,len=6"`
+}
+
+// DisableMFARequest requires the password and a code of the app or a recovery code
+type DisableMFARequest struct {
+	Password     string `json:"password" validate:"required"`
+	Code         string `json:"code,omitempty" validate:"omitempty,len=6"`
+	RecoveryCode string `json:"recovery_code,omitempty" validate:"omitempty,max=32"`
+}
+
+// RecoveryCodesResponse lists recovery codes, they are only shown once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
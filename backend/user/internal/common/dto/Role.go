@@ -0,0 +1,37 @@
+package dto
+
+type RoleRequest struct {
+	Name        string   `json:"name" validate:"required,max=50"`
+	Permissions []string `json:"permissions" validate:"max=100"`
+}
+
+type RoleResponse struct {
+	ID          uint     `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	RequireMFA  bool     `json:"require_mfa"`
+}
+
+// RoleMFARequest turns the two-factor authentication requirement of a role on or off
+type RoleMFARequest struct {
+	RequireMFA bool `json:"require_mfa"`
+}
+
+type PermissionResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type RolesResponse struct {
+	Roles []RoleResponse `json:"roles"`
+}
+
+// RoleMessageResponse wraps the role affected by a write together with a confirmation message
+type RoleMessageResponse struct {
+	Message string       `json:"message"`
+	Role    RoleResponse `json:"role"`
+}
+
+type PermissionsResponse struct {
+	Permissions []PermissionResponse `json:"permissions"`
+}
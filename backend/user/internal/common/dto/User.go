@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type CreateUserRequest struct {
 	Name     string `json:"name" validate:"required"`
 	Email    string `json:"email" validate:"required,email"`
@@ -7,6 +9,14 @@ type CreateUserRequest struct {
 	RoleID   uint   `json:"role_id" validate:"required"`
 }
 
+// RegisterRequest is a public registration, the role comes from the invite or the default role
+type RegisterRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Email       string `json:"email" validate:"required,email"`
+	Password    string `json:"password" validate:"required,min=6"`
+	InviteToken string `json:"invite_token,omitempty"`
+}
+
 type UserResponse struct {
 	ID    uint   `json:"id"`
 	Name  string `json:"name"`
@@ -19,10 +29,16 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// LoginResponse carries the tokens of the new session, or only an mfa_token when a second factor is needed first.
+// Recovery codes are included once, when two-factor authentication was set up during the login.
 type LoginResponse struct {
-	AccessToken  string       `json:"access_token"`
-	RefreshToken string       `json:"refresh_token"`
-	User         UserResponse `json:"user"`
+	AccessToken      string        `json:"access_token,omitempty"`
+	RefreshToken     string        `json:"refresh_token,omitempty"`
+	User             *UserResponse `json:"user,omitempty"`
+	MFARequired      bool          `json:"mfa_required,omitempty"`
+	MFASetupRequired bool          `json:"mfa_setup_required,omitempty"`
+	MFAToken         string        `json:"mfa_token,omitempty"`
+	RecoveryCodes    []string      `json:"recovery_codes,omitempty"`
 }
 
 type UpdateUserRequest struct {
@@ -31,3 +47,59 @@ type UpdateUserRequest struct {
 	Password string `json:"password,omitempty"` // optional
 	RoleID   uint   `json:"role_id" validate:"required"`
 }
+
+// ProfileResponse is the account of the logged in user
+type ProfileResponse struct {
+	ID            uint      `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	Role          uint      `json:"role_id"`
+	AvatarURL     string    `json:"avatar_url"`
+	EmailVerified bool      `json:"email_verified"`
+	PendingEmail  string    `json:"pending_email,omitempty"` // new email waiting for verification
+	MFAEnabled    bool      `json:"mfa_enabled"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// UpdateProfileRequest changes the fields that are present, changing the email requires the current password.
+// An empty avatar_url goes back to Gravatar.
+type UpdateProfileRequest struct {
+	Name            *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Email           *string `json:"email,omitempty" validate:"omitempty,email"`
+	AvatarURL       *string `json:"avatar_url,omitempty" validate:"omitempty,max=500"`
+	CurrentPassword string  `json:"current_password,omitempty"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=6"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type RefreshTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// UserMessageResponse wraps the user affected by a write together with a confirmation message
+type UserMessageResponse[T any] struct {
+	Message string `json:"message"`
+	User    T      `json:"user"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+// UserEvent is the payload of the user domain events published through the outbox
+type UserEvent struct {
+	ID             uint   `json:"id"`
+	Name           string `json:"name"`
+	Email          string `json:"email"`
+	RoleID         uint   `json:"role_id"`
+	IsActive       bool   `json:"is_active"`
+	PreviousRoleID uint   `json:"previous_role_id,omitempty"` // only set on user.role_changed
+}
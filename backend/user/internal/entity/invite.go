@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Invite lets someone register with a preassigned role, also while public registration is closed.
+// Only the SHA-256 hash of the token is stored.
+type Invite struct {
+	ID        uint      `gorm:"primaryKey"`
+	TokenHash string    `gorm:"not null;unique"`
+	Email     string    `gorm:"not null;default:''"` // when set, only this email can register with the invite
+	RoleID    uint      `gorm:"not null"`
+	CreatedBy *uint     // the admin who issued it
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	UsedBy    *uint // the user who registered with it
+	CreatedAt time.Time
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// UserMFA is the TOTP enrollment of a user, EnabledAt stays nil until the user confirmed it with a code
+type UserMFA struct {
+	UserID    uint       `gorm:"primaryKey;autoIncrement:false"`
+	Secret    string     `gorm:"not null"` // base32 as shown to the authenticator app, encrypted with the MFA secret key
+	EnabledAt *time.Time // logins require a second factor once set
+	LastStep  int64      `gorm:"not null;default:0"` // time step of the last accepted code, so a code works only once
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// Enabled reports whether the enrollment was confirmed
+func (m UserMFA) Enabled() bool {
+	return m.EnabledAt != nil
+}
+
+// RecoveryCode replaces a code of the authenticator app once, only the SHA-256 hash of the code is stored
+type RecoveryCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null"`
+	CodeHash  string `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (RecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// RefreshToken is a server-side record of an issued refresh token, only the SHA-256 hash of the token is stored.
+// Tokens issued from the same login share a family, rotating a token replaces it with the next one in the family.
+type RefreshToken struct {
+	ID              uint       `gorm:"primaryKey"`
+	UserID          uint       `gorm:"not null"`
+	FamilyID        string     `gorm:"type:uuid;not null"`
+	TokenHash       string     `gorm:"not null;unique"`
+	TokenVersion    uint       `gorm:"not null"` // the user's token version when the session started
+	FamilyStartedAt time.Time  `gorm:"not null"` // when the login that started the family happened, kept through rotations
+	ExpiresAt       time.Time  `gorm:"not null"`
+	RotatedAt       *time.Time // set once the token was exchanged for the next one
+	RevokedAt       *time.Time // set on logout or when reuse was detected
+	CreatedAt       time.Time
+}
+
+// Usable reports whether the token can still be exchanged
+func (t RefreshToken) Usable(now time.Time) bool {
+	return t.RotatedAt == nil && t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -2,9 +2,28 @@ package entity
 
 import "time"
 
+// Role groups the permissions granted to the users having it
 type Role struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Name      string    `gorm:"unique;not null" json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	Name        string       `gorm:"unique;not null" json:"name"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+	RequireMFA  bool         `gorm:"column:require_mfa;not null;default:false" json:"require_mfa"` // users have to set up two-factor authentication
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// PermissionNames returns the names of the role's permissions, as embedded in access tokens
+func (r Role) PermissionNames() []string {
+	names := make([]string, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		names = append(names, permission.Name)
+	}
+	return names
+}
+
+// Permission allows an action, named "<resource>:<action>" such as articles:publish
+type Permission struct {
+	ID          uint   `gorm:"primaryKey" json:"-"`
+	Name        string `gorm:"unique;not null" json:"name"`
+	Description string `json:"description"`
 }
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// SigningKey is an Ed25519 key access tokens are signed with, identified in tokens by its KeyID.
+// A key is published before it signs and stays published after its successor took over,
+// so verifiers caching the key set know every key a valid token can carry.
+type SigningKey struct {
+	ID          uint      `gorm:"primaryKey"`
+	KeyID       string    `gorm:"column:kid;not null;unique"`
+	PrivateKey  string    `gorm:"not null"` // PKCS #8, PEM encoded
+	ActivatesAt time.Time `gorm:"not null"` // when the key starts signing
+	ExpiresAt   time.Time `gorm:"not null"` // when the key is no longer published
+	CreatedAt   time.Time
+}
@@ -1,6 +1,9 @@
 package entity
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -16,4 +19,26 @@ type User struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// TokenVersion is raised to end every session issued so far, tokens with an older version are refused
+	TokenVersion uint `gorm:"not null;default:1" json:"-"`
+
+	// Avatar is the image URL set by the user, empty to use Gravatar
+	Avatar string `gorm:"column:avatar_url;not null;default:''" json:"-"`
+
+	// EmailVerifiedAt is nil until the user opened the verification link mailed to their current email
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+
+	// PendingEmail is the new email asked for in the profile, it replaces Email once its verification link is opened
+	PendingEmail *string `json:"-"`
+}
+
+// AvatarURL returns the avatar set by the user, otherwise the Gravatar image of their email
+// which is an identicon when none is registered
+func (u User) AvatarURL() string {
+	if u.Avatar != "" {
+		return u.Avatar
+	}
+	hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(u.Email))))
+	return "https://www.gravatar.com/avatar/" + hex.EncodeToString(hash[:]) + "?d=identicon"
 }
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// Purposes of the single-use tokens handed to users
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposeMFAChallenge      = "mfa_challenge" // returned by a login that still needs a second factor
+)
+
+// UserToken is a single-use token handed to a user, such as a password reset link or a login MFA challenge.
+// Only the SHA-256 hash of the token is stored.
+type UserToken struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null"`
+	Purpose   string     `gorm:"not null"`
+	TokenHash string     `gorm:"not null;unique"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time // set once the token was used or replaced
+	CreatedAt time.Time
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"gorm.io/gorm"
+)
+
+// InviteRepository stores registration invites
+type InviteRepository interface {
+	Create(invite *entity.Invite) error
+	FindPending(now time.Time) ([]entity.Invite, error)
+	Claim(hash string, now time.Time) (*entity.Invite, error)
+	MarkUsedBy(id, userID uint) error
+	Delete(id uint) error
+}
+
+type inviteRepository struct {
+	db *gorm.DB
+}
+
+func NewInviteRepository(db *gorm.DB) InviteRepository {
+	return &inviteRepository{db: db}
+}
+
+func (r *inviteRepository) Create(invite *entity.Invite) error {
+	return r.db.Create(invite).Error
+}
+
+func (r *inviteRepository) FindPending(now time.Time) ([]entity.Invite, error) {
+	var invites []entity.Invite
+	err := r.db.Where("used_at IS NULL AND expires_at > ?", now).Order("created_at DESC").Find(&invites).Error
+	return invites, err
+}
+
+// Claim marks a pending invite as used and returns it, ErrNotFound otherwise.
+// Of two concurrent claims of the same invite only one succeeds.
+func (r *inviteRepository) Claim(hash string, now time.Time) (*entity.Invite, error) {
+	var invite entity.Invite
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Invite{}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hash, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return tx.Where("token_hash = ?", hash).First(&invite).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (r *inviteRepository) MarkUsedBy(id, userID uint) error {
+	return r.db.Model(&entity.Invite{}).Where("id = ?", id).Update("used_by", userID).Error
+}
+
+func (r *inviteRepository) Delete(id uint) error {
+	result := r.db.Delete(&entity.Invite{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
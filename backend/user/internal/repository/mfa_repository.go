@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MFARepository stores the TOTP enrollments of users and their recovery codes
+type MFARepository interface {
+	Find(userID uint) (*entity.UserMFA, error)
+	Save(mfa *entity.UserMFA) error
+	Enable(userID uint, now time.Time, codeHashes []string) error
+	Delete(userID uint) error
+	ClaimStep(userID uint, step int64) error
+	ReplaceRecoveryCodes(userID uint, codeHashes []string) error
+	UseRecoveryCode(userID uint, hash string, now time.Time) error
+}
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+func (r *mfaRepository) Find(userID uint) (*entity.UserMFA, error) {
+	var mfa entity.UserMFA
+	if err := r.db.First(&mfa, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+// Save stores an enrollment, replacing the one the user already had
+func (r *mfaRepository) Save(mfa *entity.UserMFA) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(mfa).Error
+}
+
+// Enable confirms an enrollment together with its first recovery codes, ErrNotFound when there is none to confirm
+func (r *mfaRepository) Enable(userID uint, now time.Time, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.UserMFA{}).
+			Where("user_id = ? AND enabled_at IS NULL", userID).
+			Update("enabled_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// Delete removes the enrollment and recovery codes of a user
+func (r *mfaRepository) Delete(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&entity.UserMFA{}).Error
+	})
+}
+
+// ClaimStep records the time step of an accepted code, ErrNotFound when that step or a later one was accepted already.
+// Of two concurrent uses of the same code only one succeeds.
+func (r *mfaRepository) ClaimStep(userID uint, step int64) error {
+	result := r.db.Model(&entity.UserMFA{}).
+		Where("user_id = ? AND last_step < ?", userID, step).
+		Update("last_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes drops the recovery codes of a user for new ones
+func (r *mfaRepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]entity.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, entity.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
+
+// UseRecoveryCode marks an unused recovery code of a user as used, ErrNotFound otherwise
+func (r *mfaRepository) UseRecoveryCode(userID uint, hash string, now time.Time) error {
+	result := r.db.Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTokenNotFound is returned when no refresh token has the given hash
+	ErrTokenNotFound = errors.New("refresh token not found")
+	// ErrTokenNotUsable is returned when a token was rotated or revoked before it could be rotated
+	ErrTokenNotUsable = errors.New("refresh token already rotated or revoked")
+)
+
+// RefreshTokenRepository stores issued refresh tokens
+type RefreshTokenRepository interface {
+	Create(token *entity.RefreshToken) error
+	FindByHash(hash string) (*entity.RefreshToken, error)
+	Rotate(current *entity.RefreshToken, next *entity.RefreshToken) error
+	RevokeFamily(familyID string) error
+	RevokeUser(userID uint) error
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(token *entity.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *refreshTokenRepository) FindByHash(hash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate marks current as rotated and stores next in one transaction.
+// Of two concurrent rotations of the same token only one succeeds, the other gets ErrTokenNotUsable.
+func (r *refreshTokenRepository) Rotate(current *entity.RefreshToken, next *entity.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.RefreshToken{}).
+			Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", current.ID).
+			Update("rotated_at", time.Now().UTC())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTokenNotUsable
+		}
+		return tx.Create(next).Error
+	})
+}
+
+func (r *refreshTokenRepository) RevokeFamily(familyID string) error {
+	return r.db.Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now().UTC()).Error
+}
+
+func (r *refreshTokenRepository) RevokeUser(userID uint) error {
+	return r.db.Model(&entity.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now().UTC()).Error
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"gorm.io/gorm"
+)
+
+// RoleRepository stores roles and the permissions granted to them
+type RoleRepository interface {
+	FindAll() ([]entity.Role, error)
+	FindByID(id uint) (*entity.Role, error)
+	FindByName(name string) (*entity.Role, error)
+	Create(role *entity.Role) error
+	Update(role *entity.Role) error
+	Delete(id uint) error
+	CountUsers(roleID uint) (int64, error)
+	FindPermissions() ([]entity.Permission, error)
+	FindPermissionsByName(names []string) ([]entity.Permission, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) FindAll() ([]entity.Role, error) {
+	var roles []entity.Role
+	err := r.db.Preload("Permissions").Order("id ASC").Find(&roles).Error
+	return roles, err
+}
+
+// FindByID finds a role with its permissions, returning ErrNotFound when it does not exist
+func (r *roleRepository) FindByID(id uint) (*entity.Role, error) {
+	var role entity.Role
+	err := r.db.Preload("Permissions").First(&role, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// FindByName finds a role by its name, returning ErrNotFound when it does not exist
+func (r *roleRepository) FindByName(name string) (*entity.Role, error) {
+	var role entity.Role
+	err := r.db.Where("name = ?", name).First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// Create stores a role together with its permissions, which must exist already
+func (r *roleRepository) Create(role *entity.Role) error {
+	return r.db.Omit("Permissions.*").Create(role).Error
+}
+
+// Update saves the name of a role and replaces its permissions in one transaction
+func (r *roleRepository) Update(role *entity.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			return err
+		}
+		return tx.Model(role).Omit("Permissions.*").Association("Permissions").Replace(role.Permissions)
+	})
+}
+
+// Delete removes a role and its grants, returning ErrNotFound when nothing was deleted
+func (r *roleRepository) Delete(id uint) error {
+	result := r.db.Delete(&entity.Role{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CountUsers counts the users having the role, deleted users included since they still reference it
+func (r *roleRepository) CountUsers(roleID uint) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&entity.User{}).Where("role_id = ?", roleID).Count(&count).Error
+	return count, err
+}
+
+func (r *roleRepository) FindPermissions() ([]entity.Permission, error) {
+	var permissions []entity.Permission
+	err := r.db.Order("name ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *roleRepository) FindPermissionsByName(names []string) ([]entity.Permission, error) {
+	var permissions []entity.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	err := r.db.Where("name IN ?", names).Find(&permissions).Error
+	return permissions, err
+}
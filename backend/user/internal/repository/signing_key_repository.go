@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"gorm.io/gorm"
+)
+
+// SigningKeyRepository stores the keys access tokens are signed with
+type SigningKeyRepository interface {
+	Create(key *entity.SigningKey) error
+	FindPublished(now time.Time) ([]entity.SigningKey, error)
+	DeleteExpired(now time.Time) error
+}
+
+type signingKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyRepository(db *gorm.DB) SigningKeyRepository {
+	return &signingKeyRepository{db: db}
+}
+
+func (r *signingKeyRepository) Create(key *entity.SigningKey) error {
+	return r.db.Create(key).Error
+}
+
+// FindPublished returns the keys that have not expired yet, the one activated last first
+func (r *signingKeyRepository) FindPublished(now time.Time) ([]entity.SigningKey, error) {
+	var keys []entity.SigningKey
+	err := r.db.Where("expires_at > ?", now).Order("activates_at DESC, id DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *signingKeyRepository) DeleteExpired(now time.Time) error {
+	return r.db.Where("expires_at <= ?", now).Delete(&entity.SigningKey{}).Error
+}
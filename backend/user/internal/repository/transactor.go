@@ -0,0 +1,50 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Repositories are the repositories a transaction writes through
+type Repositories struct {
+	Users         UserRepository
+	Roles         RoleRepository
+	Tokens        UserTokenRepository
+	Invites       InviteRepository
+	RefreshTokens RefreshTokenRepository
+}
+
+// Transactor runs work spanning several repositories, committing it together or not at all
+type Transactor interface {
+	Transaction(fn func(tx Repositories) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+// Transaction runs fn with repositories bound to one database transaction, rolled back when fn fails
+func (t *transactor) Transaction(fn func(tx Repositories) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Users:         NewUserRepository(tx),
+			Roles:         NewRoleRepository(tx),
+			Tokens:        NewUserTokenRepository(tx),
+			Invites:       NewInviteRepository(tx),
+			RefreshTokens: NewRefreshTokenRepository(tx),
+		})
+	})
+}
+
+// Joined returns a Transactor running fn with the repositories of a transaction already started,
+// so a usecase called from inside that transaction writes as part of it
+func Joined(tx Repositories) Transactor {
+	return joined(tx)
+}
+
+type joined Repositories
+
+func (j joined) Transaction(fn func(tx Repositories) error) error {
+	return fn(Repositories(j))
+}
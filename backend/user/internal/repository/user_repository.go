@@ -4,19 +4,27 @@ import (
 	"errors"
 
 	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/shared/outbox"
 	"gorm.io/gorm"
 )
 
+// ErrNotFound is returned when the requested user does not exist
+var ErrNotFound = errors.New("user not found")
+
 // Interface
 type UserRepository interface {
 	Create(user *entity.User) error
 	FindByEmail(email string) (*entity.User, error)
 	FindByID(id uint) (*entity.User, error)
+	FindByIDs(ids []uint) ([]entity.User, error)
+	FindByRole(roleID uint, limit, offset int) ([]entity.User, error)
 	FindAll() ([]entity.User, error)
 	Delete(id uint) error
 	Update(user *entity.User) error
+	IncrementTokenVersionByRole(roleID uint) error
 	FindWithPagination(search string, limit, offset int) ([]entity.User, error)
 	CountUsers(search string) (int, error)
+	AddEvents(events ...outbox.Event) error
 }
 
 // Implementation
@@ -36,18 +44,39 @@ func (r *userRepository) FindByEmail(email string) (*entity.User, error) {
 	var user entity.User
 	err := r.db.Where("email = ?", email).First(&user).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, nil
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
 	}
-	return &user, err
+	return &user, nil
 }
 
 func (r *userRepository) FindByID(id uint) (*entity.User, error) {
 	var user entity.User
 	err := r.db.First(&user, id).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, nil
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
 	}
-	return &user, err
+	return &user, nil
+}
+
+func (r *userRepository) FindByIDs(ids []uint) ([]entity.User, error) {
+	var users []entity.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) FindByRole(roleID uint, limit, offset int) ([]entity.User, error) {
+	var users []entity.User
+	err := r.db.Where("role_id = ?", roleID).Order("id ASC").Limit(limit).Offset(offset).Find(&users).Error
+	return users, err
 }
 
 func (r *userRepository) FindAll() ([]entity.User, error) {
@@ -61,13 +90,26 @@ func (r *userRepository) FindAll() ([]entity.User, error) {
 // }
 
 func (r *userRepository) Delete(id uint) error {
-	return r.db.Delete(&entity.User{}, id).Error
+	result := r.db.Delete(&entity.User{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
 
 func (r *userRepository) Update(user *entity.User) error {
 	return r.db.Save(user).Error
 }
 
+// IncrementTokenVersionByRole ends the sessions of every user of a role
+func (r *userRepository) IncrementTokenVersionByRole(roleID uint) error {
+	return r.db.Model(&entity.User{}).Where("role_id = ?", roleID).
+		Update("token_version", gorm.Expr("token_version + 1")).Error
+}
+
 func (r *userRepository) FindWithPagination(search string, limit, offset int) ([]entity.User, error) {
 	var users []entity.User
 	query := r.db.Model(&entity.User{})
@@ -93,3 +135,8 @@ func (r *userRepository) CountUsers(search string) (int, error) {
 	err := query.Count(&count).Error
 	return int(count), err
 }
+
+// AddEvents writes domain events to the outbox, inside a transaction they are only published if it commits
+func (r *userRepository) AddEvents(events ...outbox.Event) error {
+	return outbox.Add(r.db, events...)
+}
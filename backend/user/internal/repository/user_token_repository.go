@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"gorm.io/gorm"
+)
+
+// UserTokenRepository stores the single-use tokens mailed to users
+type UserTokenRepository interface {
+	Create(token *entity.UserToken) error
+	Consume(purpose, hash string, now time.Time) (*entity.UserToken, error)
+	RevokeUser(userID uint, purpose string) error
+}
+
+type userTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+func (r *userTokenRepository) Create(token *entity.UserToken) error {
+	return r.db.Create(token).Error
+}
+
+// Consume marks an unused and unexpired token as used and returns it, ErrNotFound otherwise.
+// Of two concurrent uses of the same token only one succeeds.
+func (r *userTokenRepository) Consume(purpose, hash string, now time.Time) (*entity.UserToken, error) {
+	var token entity.UserToken
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.UserToken{}).
+			Where("purpose = ? AND token_hash = ? AND used_at IS NULL AND expires_at > ?", purpose, hash, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return tx.Where("token_hash = ?", hash).First(&token).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeUser marks every unused token of a user for purpose as used
+func (r *userTokenRepository) RevokeUser(userID uint, purpose string) error {
+	return r.db.Model(&entity.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now().UTC()).Error
+}
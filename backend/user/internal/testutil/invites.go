@@ -0,0 +1,83 @@
+package testutil
+
+import (
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
+// InviteRepository is an in-memory repository.InviteRepository
+type InviteRepository struct {
+	Invites []*entity.Invite
+}
+
+func (r *InviteRepository) Create(invite *entity.Invite) error {
+	invite.ID = uint(len(r.Invites) + 1)
+	invite.CreatedAt = time.Now()
+	r.Invites = append(r.Invites, invite)
+	return nil
+}
+
+func (r *InviteRepository) FindPending(now time.Time) ([]entity.Invite, error) {
+	var pending []entity.Invite
+	for _, invite := range r.Invites {
+		if invite.UsedAt == nil && now.Before(invite.ExpiresAt) {
+			pending = append(pending, *invite)
+		}
+	}
+	return pending, nil
+}
+
+func (r *InviteRepository) Claim(hash string, now time.Time) (*entity.Invite, error) {
+	for _, invite := range r.Invites {
+		if invite.TokenHash == hash && invite.UsedAt == nil && now.Before(invite.ExpiresAt) {
+			invite.UsedAt = &now
+			found := *invite
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *InviteRepository) MarkUsedBy(id, userID uint) error {
+	if invite := r.find(id); invite != nil {
+		invite.UsedBy = &userID
+	}
+	return nil
+}
+
+func (r *InviteRepository) Delete(id uint) error {
+	for i, invite := range r.Invites {
+		if invite.ID == id {
+			r.Invites = append(r.Invites[:i], r.Invites[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (r *InviteRepository) find(id uint) *entity.Invite {
+	for _, invite := range r.Invites {
+		if invite.ID == id {
+			return invite
+		}
+	}
+	return nil
+}
+
+func (r *InviteRepository) snapshot() []entity.Invite {
+	invites := make([]entity.Invite, len(r.Invites))
+	for i, invite := range r.Invites {
+		invites[i] = *invite
+	}
+	return invites
+}
+
+// restore puts back the invites of a snapshot, dropping those created since
+func (r *InviteRepository) restore(invites []entity.Invite) {
+	r.Invites = r.Invites[:len(invites)]
+	for i := range invites {
+		*r.Invites[i] = invites[i]
+	}
+}
@@ -0,0 +1,13 @@
+package testutil
+
+import "github.com/yuhari7/backend_supervision/pkg/mailer"
+
+// Mailer keeps the messages it was asked to send
+type Mailer struct {
+	Sent []mailer.Message
+}
+
+func (m *Mailer) Send(msg mailer.Message) error {
+	m.Sent = append(m.Sent, msg)
+	return nil
+}
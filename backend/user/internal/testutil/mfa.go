@@ -0,0 +1,84 @@
+package testutil
+
+import (
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
+// MFARepository is an in-memory repository.MFARepository
+type MFARepository struct {
+	Enrollments map[uint]*entity.UserMFA
+	Codes       []*entity.RecoveryCode
+}
+
+func NewMFARepository(enrollments ...entity.UserMFA) *MFARepository {
+	r := &MFARepository{Enrollments: map[uint]*entity.UserMFA{}}
+	for _, enrollment := range enrollments {
+		r.Enrollments[enrollment.UserID] = &enrollment
+	}
+	return r
+}
+
+func (r *MFARepository) Find(userID uint) (*entity.UserMFA, error) {
+	enrollment, ok := r.Enrollments[userID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	found := *enrollment
+	return &found, nil
+}
+
+func (r *MFARepository) Save(mfa *entity.UserMFA) error {
+	saved := *mfa
+	r.Enrollments[mfa.UserID] = &saved
+	return nil
+}
+
+func (r *MFARepository) Enable(userID uint, now time.Time, codeHashes []string) error {
+	enrollment, ok := r.Enrollments[userID]
+	if !ok || enrollment.EnabledAt != nil {
+		return repository.ErrNotFound
+	}
+	enrollment.EnabledAt = &now
+	return r.ReplaceRecoveryCodes(userID, codeHashes)
+}
+
+func (r *MFARepository) Delete(userID uint) error {
+	delete(r.Enrollments, userID)
+	return r.ReplaceRecoveryCodes(userID, nil)
+}
+
+func (r *MFARepository) ClaimStep(userID uint, step int64) error {
+	enrollment, ok := r.Enrollments[userID]
+	if !ok || enrollment.LastStep >= step {
+		return repository.ErrNotFound
+	}
+	enrollment.LastStep = step
+	return nil
+}
+
+func (r *MFARepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	var kept []*entity.RecoveryCode
+	for _, code := range r.Codes {
+		if code.UserID != userID {
+			kept = append(kept, code)
+		}
+	}
+	for _, hash := range codeHashes {
+		kept = append(kept, &entity.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	r.Codes = kept
+	return nil
+}
+
+func (r *MFARepository) UseRecoveryCode(userID uint, hash string, now time.Time) error {
+	for _, code := range r.Codes {
+		if code.UserID == userID && code.CodeHash == hash && code.UsedAt == nil {
+			code.UsedAt = &now
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
@@ -0,0 +1,78 @@
+package testutil
+
+import (
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
+// RefreshTokenRepository is an in-memory repository.RefreshTokenRepository following the same contract as the real one.
+// RevokeErr makes revoking fail.
+type RefreshTokenRepository struct {
+	Tokens    []*entity.RefreshToken
+	RevokeErr error
+}
+
+func (r *RefreshTokenRepository) Create(token *entity.RefreshToken) error {
+	token.ID = uint(len(r.Tokens) + 1)
+	r.Tokens = append(r.Tokens, token)
+	return nil
+}
+
+func (r *RefreshTokenRepository) FindByHash(hash string) (*entity.RefreshToken, error) {
+	for _, token := range r.Tokens {
+		if token.TokenHash == hash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrTokenNotFound
+}
+
+func (r *RefreshTokenRepository) Rotate(current *entity.RefreshToken, next *entity.RefreshToken) error {
+	stored := r.Tokens[current.ID-1]
+	if stored.RotatedAt != nil || stored.RevokedAt != nil {
+		return repository.ErrTokenNotUsable
+	}
+	now := time.Now()
+	stored.RotatedAt = &now
+	return r.Create(next)
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(familyID string) error {
+	return r.revoke(func(token *entity.RefreshToken) bool { return token.FamilyID == familyID })
+}
+
+func (r *RefreshTokenRepository) RevokeUser(userID uint) error {
+	return r.revoke(func(token *entity.RefreshToken) bool { return token.UserID == userID })
+}
+
+func (r *RefreshTokenRepository) revoke(match func(*entity.RefreshToken) bool) error {
+	if r.RevokeErr != nil {
+		return r.RevokeErr
+	}
+	now := time.Now()
+	for _, token := range r.Tokens {
+		if match(token) && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *RefreshTokenRepository) snapshot() []entity.RefreshToken {
+	tokens := make([]entity.RefreshToken, len(r.Tokens))
+	for i, token := range r.Tokens {
+		tokens[i] = *token
+	}
+	return tokens
+}
+
+// restore puts back the tokens of a snapshot, dropping those created since
+func (r *RefreshTokenRepository) restore(tokens []entity.RefreshToken) {
+	r.Tokens = r.Tokens[:len(tokens)]
+	for i := range tokens {
+		*r.Tokens[i] = tokens[i]
+	}
+}
@@ -0,0 +1,114 @@
+package testutil
+
+import (
+	"maps"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
+// RoleRepository is an in-memory repository.RoleRepository knowing a few permissions.
+// UserCounts is how many users each role has.
+type RoleRepository struct {
+	Roles       map[uint]entity.Role
+	Permissions []entity.Permission
+	UserCounts  map[uint]int64
+
+	nextID uint
+}
+
+func NewRoleRepository(roles ...entity.Role) *RoleRepository {
+	r := &RoleRepository{
+		Roles: map[uint]entity.Role{},
+		Permissions: []entity.Permission{
+			{ID: 1, Name: "users:manage"},
+			{ID: 2, Name: "roles:manage"},
+			{ID: 3, Name: "articles:create"},
+			{ID: 4, Name: "articles:update"},
+		},
+		UserCounts: map[uint]int64{},
+		nextID:     1,
+	}
+	for _, role := range roles {
+		r.Roles[role.ID] = role
+		if role.ID >= r.nextID {
+			r.nextID = role.ID + 1
+		}
+	}
+	return r
+}
+
+func (r *RoleRepository) snapshot() map[uint]entity.Role {
+	return maps.Clone(r.Roles)
+}
+
+func (r *RoleRepository) FindAll() ([]entity.Role, error) {
+	roles := make([]entity.Role, 0, len(r.Roles))
+	for id := uint(1); id < r.nextID; id++ {
+		if role, ok := r.Roles[id]; ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+func (r *RoleRepository) FindByID(id uint) (*entity.Role, error) {
+	role, ok := r.Roles[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &role, nil
+}
+
+func (r *RoleRepository) FindByName(name string) (*entity.Role, error) {
+	for _, role := range r.Roles {
+		if role.Name == name {
+			return &role, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *RoleRepository) Create(role *entity.Role) error {
+	role.ID = r.nextID
+	r.nextID++
+	r.Roles[role.ID] = *role
+	return nil
+}
+
+func (r *RoleRepository) Update(role *entity.Role) error {
+	if _, ok := r.Roles[role.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	r.Roles[role.ID] = *role
+	return nil
+}
+
+func (r *RoleRepository) Delete(id uint) error {
+	if _, ok := r.Roles[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.Roles, id)
+	return nil
+}
+
+func (r *RoleRepository) CountUsers(roleID uint) (int64, error) {
+	return r.UserCounts[roleID], nil
+}
+
+func (r *RoleRepository) FindPermissions() ([]entity.Permission, error) {
+	return r.Permissions, nil
+}
+
+func (r *RoleRepository) FindPermissionsByName(names []string) ([]entity.Permission, error) {
+	var found []entity.Permission
+	for _, permission := range r.Permissions {
+		for _, name := range names {
+			if permission.Name == name {
+				found = append(found, permission)
+				break
+			}
+		}
+	}
+	return found, nil
+}
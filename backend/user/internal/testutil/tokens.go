@@ -0,0 +1,56 @@
+package testutil
+
+import (
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
+// UserTokenRepository is an in-memory repository.UserTokenRepository
+type UserTokenRepository struct {
+	Tokens []*entity.UserToken
+}
+
+func (r *UserTokenRepository) Create(token *entity.UserToken) error {
+	token.ID = uint(len(r.Tokens) + 1)
+	r.Tokens = append(r.Tokens, token)
+	return nil
+}
+
+func (r *UserTokenRepository) Consume(purpose, hash string, now time.Time) (*entity.UserToken, error) {
+	for _, token := range r.Tokens {
+		if token.Purpose == purpose && token.TokenHash == hash && token.UsedAt == nil && now.Before(token.ExpiresAt) {
+			token.UsedAt = &now
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *UserTokenRepository) RevokeUser(userID uint, purpose string) error {
+	now := time.Now()
+	for _, token := range r.Tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			token.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *UserTokenRepository) snapshot() []entity.UserToken {
+	tokens := make([]entity.UserToken, len(r.Tokens))
+	for i, token := range r.Tokens {
+		tokens[i] = *token
+	}
+	return tokens
+}
+
+// restore puts back the tokens of a snapshot, dropping those created since
+func (r *UserTokenRepository) restore(tokens []entity.UserToken) {
+	r.Tokens = r.Tokens[:len(tokens)]
+	for i := range tokens {
+		*r.Tokens[i] = tokens[i]
+	}
+}
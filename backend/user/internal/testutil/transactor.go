@@ -0,0 +1,51 @@
+package testutil
+
+import "github.com/yuhari7/backend_supervision/internal/repository"
+
+// Transactor runs transactions on the fake repositories, restoring them when the transaction fails.
+// Nil repositories are left out of the transaction.
+type Transactor struct {
+	Users         *UserRepository
+	Roles         *RoleRepository
+	Tokens        *UserTokenRepository
+	Invites       *InviteRepository
+	RefreshTokens *RefreshTokenRepository
+}
+
+func (t *Transactor) Transaction(fn func(tx repository.Repositories) error) error {
+	var tx repository.Repositories
+	var rollbacks []func()
+	if t.Users != nil {
+		tx.Users = t.Users
+		users, events := t.Users.snapshot()
+		rollbacks = append(rollbacks, func() { t.Users.Users, t.Users.Events = users, events })
+	}
+	if t.Roles != nil {
+		tx.Roles = t.Roles
+		roles := t.Roles.snapshot()
+		rollbacks = append(rollbacks, func() { t.Roles.Roles = roles })
+	}
+	if t.Tokens != nil {
+		tx.Tokens = t.Tokens
+		tokens := t.Tokens.snapshot()
+		rollbacks = append(rollbacks, func() { t.Tokens.restore(tokens) })
+	}
+	if t.Invites != nil {
+		tx.Invites = t.Invites
+		invites := t.Invites.snapshot()
+		rollbacks = append(rollbacks, func() { t.Invites.restore(invites) })
+	}
+	if t.RefreshTokens != nil {
+		tx.RefreshTokens = t.RefreshTokens
+		refreshTokens := t.RefreshTokens.snapshot()
+		rollbacks = append(rollbacks, func() { t.RefreshTokens.restore(refreshTokens) })
+	}
+
+	err := fn(tx)
+	if err != nil {
+		for _, rollback := range rollbacks {
+			rollback()
+		}
+	}
+	return err
+}
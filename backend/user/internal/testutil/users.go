@@ -0,0 +1,184 @@
+// Package testutil holds in-memory fakes of the repositories and the mailer for the usecase tests.
+// They follow the same contracts as the real ones, not found included.
+package testutil
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	"github.com/yuhari7/backend_supervision/shared/outbox"
+)
+
+// UserRepository is an in-memory repository.UserRepository.
+// When Err is set every call fails with it, standing in for a broken database.
+type UserRepository struct {
+	Users  map[uint]entity.User
+	Events []outbox.Event
+	Err    error
+
+	// UpdateErr only fails Update, EventsErr only fails writes to the outbox, to check that changes are rolled back
+	UpdateErr error
+	EventsErr error
+
+	nextID uint
+}
+
+func NewUserRepository(users ...entity.User) *UserRepository {
+	r := &UserRepository{Users: map[uint]entity.User{}, nextID: 1}
+	for _, user := range users {
+		r.Users[user.ID] = user
+		if user.ID >= r.nextID {
+			r.nextID = user.ID + 1
+		}
+	}
+	return r
+}
+
+func (r *UserRepository) Create(user *entity.User) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	user.ID = r.nextID
+	r.nextID++
+	// Like the is_active column default, which GORM applies to the zero value
+	user.IsActive = true
+	r.Users[user.ID] = *user
+	return nil
+}
+
+func (r *UserRepository) FindByEmail(email string) (*entity.User, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	for _, user := range r.Users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *UserRepository) FindByID(id uint) (*entity.User, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	user, ok := r.Users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) FindByIDs(ids []uint) ([]entity.User, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	var users []entity.User
+	for _, id := range ids {
+		if user, ok := r.Users[id]; ok {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (r *UserRepository) FindByRole(roleID uint, limit, offset int) ([]entity.User, error) {
+	users, err := r.FindWithPagination("", len(r.Users), 0)
+	if err != nil {
+		return nil, err
+	}
+	var matching []entity.User
+	for _, user := range users {
+		if user.RoleID == roleID {
+			matching = append(matching, user)
+		}
+	}
+	return page(matching, limit, offset), nil
+}
+
+func (r *UserRepository) FindAll() ([]entity.User, error) {
+	return r.FindWithPagination("", len(r.Users), 0)
+}
+
+func (r *UserRepository) Delete(id uint) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if _, ok := r.Users[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.Users, id)
+	return nil
+}
+
+func (r *UserRepository) Update(user *entity.User) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if r.UpdateErr != nil {
+		return r.UpdateErr
+	}
+	r.Users[user.ID] = *user
+	return nil
+}
+
+func (r *UserRepository) IncrementTokenVersionByRole(roleID uint) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	for id, user := range r.Users {
+		if user.RoleID == roleID {
+			user.TokenVersion++
+			r.Users[id] = user
+		}
+	}
+	return nil
+}
+
+func (r *UserRepository) FindWithPagination(search string, limit, offset int) ([]entity.User, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	var users []entity.User
+	for id := uint(1); id < r.nextID; id++ {
+		user, ok := r.Users[id]
+		if ok && (strings.Contains(user.Name, search) || strings.Contains(user.Email, search)) {
+			users = append(users, user)
+		}
+	}
+	return page(users, limit, offset), nil
+}
+
+func (r *UserRepository) CountUsers(search string) (int, error) {
+	users, err := r.FindWithPagination(search, len(r.Users), 0)
+	return len(users), err
+}
+
+func (r *UserRepository) AddEvents(events ...outbox.Event) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if r.EventsErr != nil {
+		return r.EventsErr
+	}
+	r.Events = append(r.Events, events...)
+	return nil
+}
+
+func (r *UserRepository) snapshot() (map[uint]entity.User, []outbox.Event) {
+	return maps.Clone(r.Users), r.Events
+}
+
+// page returns the items of a page like LIMIT and OFFSET
+func page[T any](items []T, limit, offset int) []T {
+	if offset > len(items) {
+		offset = len(items)
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,276 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	jwtutil "github.com/yuhari7/backend_supervision/pkg/jwt"
+	"github.com/yuhari7/backend_supervision/shared/jwks"
+)
+
+// reloadAfter is how soon a token signed with an unknown key may trigger another read of the keys,
+// another instance may have rotated them
+const reloadAfter = 5 * time.Second
+
+// Config configures the key rotation, zero values fall back to the defaults
+type Config struct {
+	RotateEvery   time.Duration // how long a key signs before its successor takes over, defaults to 30 days
+	PublishAhead  time.Duration // how long a key is published before it signs, defaults to 10m
+	Overlap       time.Duration // how long a key stays published after its successor took over, defaults to 1h
+	CheckInterval time.Duration // how often the keys are reloaded and rotated when due, defaults to 1m
+}
+
+// Keyring holds the keys access tokens are signed with and rotates them on schedule.
+// Keys live in the database so every instance signs with the same key and publishes the same set.
+type Keyring interface {
+	jwtutil.Keys
+	JWKS() jwks.Set
+	Rotate() error
+	Start()
+	Stop()
+}
+
+type loadedKey struct {
+	kid         string
+	private     ed25519.PrivateKey
+	activatesAt time.Time
+}
+
+type keyring struct {
+	repo   repository.SigningKeyRepository
+	config Config
+	now    func() time.Time
+
+	mu       sync.RWMutex
+	keys     []loadedKey // the key activated last first
+	loadedAt time.Time
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewKeyring creates a new instance of Keyring, Rotate must run once before tokens can be signed
+func NewKeyring(r repository.SigningKeyRepository, config Config) Keyring {
+	if config.RotateEvery <= 0 {
+		config.RotateEvery = 30 * 24 * time.Hour
+	}
+	if config.PublishAhead <= 0 {
+		config.PublishAhead = 10 * time.Minute
+	}
+	if config.Overlap <= 0 {
+		config.Overlap = time.Hour
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = time.Minute
+	}
+
+	return &keyring{
+		repo:   r,
+		config: config,
+		now:    time.Now,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+}
+
+// Rotate drops expired keys, creates the next key once the current one is due to be replaced and reloads the keys.
+// The next key is published PublishAhead before it signs, so verifiers caching the key set know it in time.
+func (k *keyring) Rotate() error {
+	now := k.now().UTC()
+	if err := k.repo.DeleteExpired(now); err != nil {
+		return err
+	}
+	keys, err := k.repo.FindPublished(now)
+	if err != nil {
+		return err
+	}
+
+	// Without a key, e.g. on the first start or after a long downtime, the new key signs at once
+	activatesAt := now
+	if len(keys) > 0 {
+		successorAt := keys[0].ActivatesAt.Add(k.config.RotateEvery)
+		if now.Before(successorAt.Add(-k.config.PublishAhead)) {
+			return k.load(keys)
+		}
+		activatesAt = maxTime(successorAt, now)
+	}
+
+	key, err := k.generate(activatesAt)
+	if err != nil {
+		return err
+	}
+	if err := k.repo.Create(key); err != nil {
+		return err
+	}
+	log.Printf("Created signing key %s, signing from %s", key.KeyID, key.ActivatesAt.Format(time.RFC3339))
+
+	keys, err = k.repo.FindPublished(now)
+	if err != nil {
+		return err
+	}
+	return k.load(keys)
+}
+
+// generate creates a key signing from activatesAt, published until Overlap after its successor took over
+func (k *keyring) generate(activatesAt time.Time) (*entity.SigningKey, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	return &entity.SigningKey{
+		KeyID:       hex.EncodeToString(id),
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		ActivatesAt: activatesAt,
+		ExpiresAt:   activatesAt.Add(k.config.RotateEvery + k.config.Overlap),
+	}, nil
+}
+
+// load replaces the keys held in memory
+func (k *keyring) load(keys []entity.SigningKey) error {
+	loaded := make([]loadedKey, 0, len(keys))
+	for _, key := range keys {
+		private, err := parsePrivateKey(key.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("signing key %s: %w", key.KeyID, err)
+		}
+		loaded = append(loaded, loadedKey{kid: key.KeyID, private: private, activatesAt: key.ActivatesAt})
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys = loaded
+	k.loadedAt = k.now()
+	return nil
+}
+
+func parsePrivateKey(encoded string) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(encoded))
+	if block == nil {
+		return nil, errors.New("invalid PEM")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an Ed25519 key")
+	}
+	return private, nil
+}
+
+// SigningKey returns the key activated last among those already active
+func (k *keyring) SigningKey() (string, ed25519.PrivateKey, error) {
+	now := k.now()
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	for _, key := range k.keys {
+		if !key.activatesAt.After(now) {
+			return key.kid, key.private, nil
+		}
+	}
+	return "", nil, errors.New("no active signing key")
+}
+
+// PublicKey returns a published key, reading the keys again when another instance may have added it
+func (k *keyring) PublicKey(kid string) (ed25519.PublicKey, error) {
+	if key, ok := k.find(kid); ok {
+		return key, nil
+	}
+
+	k.mu.RLock()
+	recent := k.now().Sub(k.loadedAt) < reloadAfter
+	k.mu.RUnlock()
+	if recent {
+		return nil, jwtutil.ErrUnknownKey
+	}
+
+	keys, err := k.repo.FindPublished(k.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if err := k.load(keys); err != nil {
+		return nil, err
+	}
+	if key, ok := k.find(kid); ok {
+		return key, nil
+	}
+	return nil, jwtutil.ErrUnknownKey
+}
+
+func (k *keyring) find(kid string) (ed25519.PublicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	for _, key := range k.keys {
+		if key.kid == kid {
+			return key.private.Public().(ed25519.PublicKey), true
+		}
+	}
+	return nil, false
+}
+
+// JWKS returns the public half of every published key, including the next key before it signs
+func (k *keyring) JWKS() jwks.Set {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set := jwks.Set{Keys: make([]jwks.Key, 0, len(k.keys))}
+	for _, key := range k.keys {
+		set.Keys = append(set.Keys, jwks.NewKey(key.kid, key.private.Public().(ed25519.PublicKey)))
+	}
+	return set
+}
+
+// Start runs the background loop reloading the keys and rotating them when due
+func (k *keyring) Start() {
+	go func() {
+		defer close(k.doneCh)
+
+		ticker := time.NewTicker(k.config.CheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := k.Rotate(); err != nil {
+					log.Println("Failed to rotate signing keys:", err)
+				}
+			case <-k.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background loop
+func (k *keyring) Stop() {
+	close(k.stopCh)
+	<-k.doneCh
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
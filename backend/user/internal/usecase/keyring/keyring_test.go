@@ -0,0 +1,142 @@
+package keyring
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	jwtutil "github.com/yuhari7/backend_supervision/pkg/jwt"
+)
+
+// fakeKeyRepository is an in-memory SigningKeyRepository following the same contract as the real one
+type fakeKeyRepository struct {
+	keys []entity.SigningKey
+}
+
+func (r *fakeKeyRepository) Create(key *entity.SigningKey) error {
+	key.ID = uint(len(r.keys) + 1)
+	r.keys = append(r.keys, *key)
+	return nil
+}
+
+func (r *fakeKeyRepository) FindPublished(now time.Time) ([]entity.SigningKey, error) {
+	var keys []entity.SigningKey
+	for _, key := range r.keys {
+		if key.ExpiresAt.After(now) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ActivatesAt.After(keys[j].ActivatesAt) })
+	return keys, nil
+}
+
+func (r *fakeKeyRepository) DeleteExpired(now time.Time) error {
+	var kept []entity.SigningKey
+	for _, key := range r.keys {
+		if key.ExpiresAt.After(now) {
+			kept = append(kept, key)
+		}
+	}
+	r.keys = kept
+	return nil
+}
+
+// clock is a time the test moves forward
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newTestKeyring() (*keyring, *fakeKeyRepository, *clock) {
+	repo := &fakeKeyRepository{}
+	now := &clock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	k := NewKeyring(repo, Config{RotateEvery: 24 * time.Hour, PublishAhead: 10 * time.Minute, Overlap: time.Hour}).(*keyring)
+	k.now = now.Now
+	return k, repo, now
+}
+
+func kids(k *keyring) []string {
+	var ids []string
+	for _, key := range k.JWKS().Keys {
+		ids = append(ids, key.KeyID)
+	}
+	return ids
+}
+
+func TestKeysRotateWithOverlap(t *testing.T) {
+	k, repo, now := newTestKeyring()
+
+	// The first key signs at once
+	if err := k.Rotate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first, _, err := k.SigningKey()
+	if err != nil || len(repo.keys) != 1 {
+		t.Fatalf("got key %q and error %v with %d keys, want one signing key", first, err, len(repo.keys))
+	}
+	token, err := jwtutil.GenerateAccessToken(k, jwtutil.CustomClaims{UserID: 1, Email: "budi@example.com", RoleID: 2, TokenVersion: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing changes until the next key is due to be published
+	now.now = now.now.Add(23 * time.Hour)
+	if err := k.Rotate(); err != nil || len(repo.keys) != 1 {
+		t.Fatalf("got %d keys and error %v, want no rotation yet", len(repo.keys), err)
+	}
+
+	// The next key is published 10 minutes ahead but does not sign yet
+	now.now = now.now.Add(55 * time.Minute)
+	if err := k.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if signing, _, _ := k.SigningKey(); signing != first || len(kids(k)) != 2 {
+		t.Fatalf("got signing key %q and published %v, want %q signing and the next key published", signing, kids(k), first)
+	}
+
+	// Once active it signs, tokens of the first key stay valid during the overlap
+	now.now = now.now.Add(10 * time.Minute)
+	if second, _, _ := k.SigningKey(); second == first {
+		t.Errorf("got signing key %q, want the next key after its activation", second)
+	}
+	if _, err := k.PublicKey(first); err != nil {
+		t.Errorf("unexpected error for the previous key during the overlap: %v", err)
+	}
+
+	// After the overlap the first key is gone
+	now.now = now.now.Add(time.Hour)
+	if err := k.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(kids(k)) != 1 || len(repo.keys) != 1 {
+		t.Errorf("got published keys %v, want the first key dropped after the overlap", kids(k))
+	}
+	if _, err := jwtutil.ParseAccessToken(k, token); !errors.Is(err, jwtutil.ErrUnknownKey) {
+		t.Errorf("got error %v for a token of an expired key, want %v", err, jwtutil.ErrUnknownKey)
+	}
+}
+
+func TestKeysCreatedByAnotherInstanceAreFound(t *testing.T) {
+	k, repo, now := newTestKeyring()
+	if err := k.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another instance shares the database and rotates first
+	other := NewKeyring(repo, Config{RotateEvery: 24 * time.Hour}).(*keyring)
+	other.now = now.Now
+	now.now = now.now.Add(25 * time.Hour)
+	if err := other.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwtutil.GenerateAccessToken(other, jwtutil.CustomClaims{UserID: 1, Email: "budi@example.com", RoleID: 2, TokenVersion: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := jwtutil.ParseAccessToken(k, token)
+	if err != nil || claims.UserID != 1 {
+		t.Errorf("got claims %+v and error %v, want the token of the other instance accepted", claims, err)
+	}
+}
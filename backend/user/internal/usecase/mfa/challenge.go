@@ -0,0 +1,121 @@
+package mfa
+
+import (
+	"errors"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	"github.com/yuhari7/backend_supervision/pkg/randtoken"
+)
+
+// Challenge returns nil when the user can log in with their password alone. Otherwise the login returns the
+// challenge instead of tokens, to be answered with a code, or to set up the two-factor authentication the role requires.
+func (u *mfaUsecase) Challenge(account *entity.User) (*Challenge, error) {
+	enrollment, err := u.findEnrollment(account.ID)
+	if err != nil {
+		return nil, err
+	}
+	enabled := enrollment != nil && enrollment.Enabled()
+	if !enabled {
+		required, err := u.roleRequiresMFA(account.RoleID)
+		if err != nil || !required {
+			return nil, err
+		}
+	}
+
+	token, err := u.newChallenge(account.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &Challenge{Token: token, SetupRequired: !enabled}, nil
+}
+
+// SetupChallenge starts the enrollment a role requires during login.
+// The response carries a new mfa_token to confirm it with a code through VerifyChallenge.
+func (u *mfaUsecase) SetupChallenge(input dto.MFATokenRequest) (*dto.MFASetupResponse, error) {
+	account, err := u.consumeChallenge(input.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+
+	setup, err := u.setup(account)
+	if err != nil {
+		return nil, err
+	}
+	if setup.MFAToken, err = u.newChallenge(account.ID); err != nil {
+		return nil, err
+	}
+	return setup, nil
+}
+
+// VerifyChallenge answers the challenge of a login and returns the user to start a session for.
+// When it confirms an enrollment started during login the first recovery codes are returned as well.
+// Each challenge can be answered once, after a wrong code the login starts over.
+func (u *mfaUsecase) VerifyChallenge(input dto.MFALoginRequest) (*entity.User, []string, error) {
+	account, err := u.consumeChallenge(input.MFAToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	enrollment, err := u.findEnrollment(account.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if enrollment == nil {
+		return nil, nil, ErrMFANotSetUp
+	}
+	if !enrollment.Enabled() {
+		codes, err := u.enable(enrollment, input.Code)
+		if err != nil {
+			return nil, nil, err
+		}
+		return account, codes, nil
+	}
+
+	if err := u.checkSecondFactor(enrollment, input.Code, input.RecoveryCode); err != nil {
+		return nil, nil, err
+	}
+	return account, nil, nil
+}
+
+// newChallenge stores a single-use challenge of a user and returns its token
+func (u *mfaUsecase) newChallenge(userID uint) (string, error) {
+	token, err := randtoken.New()
+	if err != nil {
+		return "", err
+	}
+	err = u.tokenRepo.Create(&entity.UserToken{
+		UserID:    userID,
+		Purpose:   entity.TokenPurposeMFAChallenge,
+		TokenHash: randtoken.Hash(token),
+		ExpiresAt: u.now().Add(u.config.ChallengeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// consumeChallenge uses up an mfa_token and returns its user, who must still be active
+func (u *mfaUsecase) consumeChallenge(mfaToken string) (*entity.User, error) {
+	token, err := u.tokenRepo.Consume(entity.TokenPurposeMFAChallenge, randtoken.Hash(mfaToken), u.now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrInvalidMFAToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := u.userRepo.FindByID(token.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrInvalidMFAToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !account.IsActive {
+		return nil, ErrInvalidMFAToken
+	}
+	return account, nil
+}
@@ -0,0 +1,42 @@
+package mfa
+
+import (
+	"errors"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	"github.com/yuhari7/backend_supervision/internal/usecase/user"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Disable turns two-factor authentication off after checking the password and a second factor.
+// Users of a role requiring it cannot turn it off.
+func (u *mfaUsecase) Disable(userID uint, input dto.DisableMFARequest) error {
+	account, err := u.userRepo.FindByID(userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return user.ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(account.Password), []byte(input.Password)); err != nil {
+		return user.ErrInvalidCurrentPassword
+	}
+
+	enrollment, err := u.findEnabled(userID)
+	if err != nil {
+		return err
+	}
+	required, err := u.roleRequiresMFA(account.RoleID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrMFARequiredByRole
+	}
+	if err := u.checkSecondFactor(enrollment, input.Code, input.RecoveryCode); err != nil {
+		return err
+	}
+
+	return u.mfaRepo.Delete(userID)
+}
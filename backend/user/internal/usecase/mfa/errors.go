@@ -0,0 +1,14 @@
+package mfa
+
+import "github.com/yuhari7/backend_supervision/shared/apperror"
+
+// Domain errors returned by the MFA usecases
+var (
+	ErrInvalidMFAToken     = apperror.Unauthorized("invalid_mfa_token", "invalid, used or expired MFA token, sign in again")
+	ErrInvalidMFACode      = apperror.Validation("invalid_mfa_code", "invalid or already used authentication code", nil)
+	ErrInvalidRecoveryCode = apperror.Validation("invalid_recovery_code", "invalid or already used recovery code", nil)
+	ErrMFAAlreadyEnabled   = apperror.Conflict("mfa_already_enabled", "two-factor authentication is already enabled")
+	ErrMFANotSetUp         = apperror.Unprocessable("mfa_not_set_up", "two-factor authentication has not been set up")
+	ErrMFANotEnabled       = apperror.Unprocessable("mfa_not_enabled", "two-factor authentication is not enabled")
+	ErrMFARequiredByRole   = apperror.Forbidden("mfa_required_by_role", "the role requires two-factor authentication")
+)
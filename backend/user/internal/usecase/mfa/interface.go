@@ -0,0 +1,26 @@
+package mfa
+
+import (
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+)
+
+// Challenge is returned by a login that needs a second factor, in place of the session tokens
+type Challenge struct {
+	Token string
+	// SetupRequired is set when the role requires two-factor authentication the user has not set up yet
+	SetupRequired bool
+}
+
+// MFAUsecase manages TOTP two-factor authentication and the second step of logins using it
+type MFAUsecase interface {
+	Enabled(userID uint) (bool, error)
+	Setup(userID uint) (*dto.MFASetupResponse, error)
+	Confirm(userID uint, input dto.MFACodeRequest) ([]string, error)
+	RegenerateRecoveryCodes(userID uint, input dto.MFACodeRequest) ([]string, error)
+	Disable(userID uint, input dto.DisableMFARequest) error
+
+	Challenge(user *entity.User) (*Challenge, error)
+	SetupChallenge(input dto.MFATokenRequest) (*dto.MFASetupResponse, error)
+	VerifyChallenge(input dto.MFALoginRequest) (*entity.User, []string, error)
+}
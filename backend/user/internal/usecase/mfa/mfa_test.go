@@ -0,0 +1,275 @@
+package mfa
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/testutil"
+	"github.com/yuhari7/backend_supervision/internal/usecase/user"
+	"github.com/yuhari7/backend_supervision/pkg/randtoken"
+	"github.com/yuhari7/backend_supervision/pkg/secretbox"
+	"github.com/yuhari7/backend_supervision/pkg/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	budiID uint = 1 // contributor
+	sariID uint = 2 // admin, whose role requires MFA
+)
+
+// testClock is moved forward by the tests, so each code comes from a new time step
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// testSecretKey is the base64 of 32 bytes of 1
+const testSecretKey = "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE="
+
+func newMFA(t *testing.T) (*mfaUsecase, *testutil.MFARepository, *testClock) {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := testutil.NewUserRepository(
+		entity.User{ID: budiID, Email: "budi@example.com", Password: string(hash), RoleID: 2, IsActive: true},
+		entity.User{ID: sariID, Email: "sari@example.com", Password: string(hash), RoleID: 1, IsActive: true},
+	)
+	roles := testutil.NewRoleRepository(
+		entity.Role{ID: 1, Name: "admin", RequireMFA: true},
+		entity.Role{ID: 2, Name: "contributor"},
+	)
+	enrollments := testutil.NewMFARepository()
+	clock := &testClock{now: time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)}
+
+	usecase, err := NewMFAUsecase(users, roles, enrollments, &testutil.UserTokenRepository{}, Config{SecretKey: testSecretKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := usecase.(*mfaUsecase)
+	u.now = func() time.Time { return clock.now }
+	return u, enrollments, clock
+}
+
+// currentCode returns the code the app shows for secret, after moving to the next time step
+func currentCode(t *testing.T, clock *testClock, secret string) string {
+	t.Helper()
+	clock.advance(totp.Period)
+	code, err := totp.Code(secret, totp.Step(clock.now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+// enable sets up and confirms two-factor authentication for a user, returning the secret and recovery codes
+func enable(t *testing.T, u *mfaUsecase, clock *testClock, userID uint) (string, []string) {
+	t.Helper()
+	setup, err := u.Setup(userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	codes, err := u.Confirm(userID, dto.MFACodeRequest{Code: currentCode(t, clock, setup.Secret)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return setup.Secret, codes
+}
+
+func TestSetupAndConfirm(t *testing.T) {
+	u, enrollments, clock := newMFA(t)
+
+	setup, err := u.Setup(budiID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(setup.OTPAuthURL, "otpauth://totp/Supervision:budi@example.com?") || !strings.Contains(setup.OTPAuthURL, "secret="+setup.Secret) {
+		t.Errorf("got URL %q, want an otpauth URL of the secret", setup.OTPAuthURL)
+	}
+	if enabled, _ := u.Enabled(budiID); enabled {
+		t.Error("expected two-factor authentication to wait for a confirmation")
+	}
+
+	if _, err := u.Confirm(budiID, dto.MFACodeRequest{Code: "000000"}); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("got error %v, want ErrInvalidMFACode", err)
+	}
+	codes, err := u.Confirm(budiID, dto.MFACodeRequest{Code: currentCode(t, clock, setup.Secret)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(codes) != recoveryCodeCount || len(enrollments.Codes) != recoveryCodeCount {
+		t.Fatalf("got %d codes and %d stored, want %d", len(codes), len(enrollments.Codes), recoveryCodeCount)
+	}
+	if stored := enrollments.Codes[0].CodeHash; stored != randtoken.Hash(strings.ReplaceAll(codes[0], "-", "")) {
+		t.Errorf("got stored code %q, want the hash of the code", stored)
+	}
+	if enabled, _ := u.Enabled(budiID); !enabled {
+		t.Error("expected two-factor authentication to be enabled")
+	}
+
+	if _, err := u.Setup(budiID); !errors.Is(err, ErrMFAAlreadyEnabled) {
+		t.Errorf("got error %v, want ErrMFAAlreadyEnabled", err)
+	}
+	if _, err := u.Confirm(sariID, dto.MFACodeRequest{Code: "123456"}); !errors.Is(err, ErrMFANotSetUp) {
+		t.Errorf("got error %v, want ErrMFANotSetUp", err)
+	}
+}
+
+func TestSecretsAreStoredEncrypted(t *testing.T) {
+	u, enrollments, clock := newMFA(t)
+
+	setup, err := u.Setup(budiID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored := enrollments.Enrollments[budiID].Secret; strings.Contains(stored, setup.Secret) || !secretbox.IsSealed(stored) {
+		t.Errorf("got stored secret %q, want it encrypted", stored)
+	}
+
+	// A secret stored in clear is rejected rather than trusted
+	enrollments.Enrollments[budiID].Secret = setup.Secret
+	if _, err := u.Confirm(budiID, dto.MFACodeRequest{Code: currentCode(t, clock, setup.Secret)}); err == nil {
+		t.Error("expected an error for a secret stored in clear")
+	}
+
+	if _, err := NewMFAUsecase(nil, nil, nil, nil, Config{}); err == nil {
+		t.Error("expected an error without a secret key")
+	}
+}
+
+func TestLoginChallenge(t *testing.T) {
+	u, _, clock := newMFA(t)
+	budi, _ := u.userRepo.FindByID(budiID)
+
+	if challenge, err := u.Challenge(budi); err != nil || challenge != nil {
+		t.Fatalf("got challenge %+v and error %v, want none without two-factor authentication", challenge, err)
+	}
+
+	secret, _ := enable(t, u, clock, budiID)
+	challenge, err := u.Challenge(budi)
+	if err != nil || challenge == nil || challenge.SetupRequired {
+		t.Fatalf("got challenge %+v and error %v, want one asking for a code", challenge, err)
+	}
+
+	code := currentCode(t, clock, secret)
+	account, codes, err := u.VerifyChallenge(dto.MFALoginRequest{MFAToken: challenge.Token, Code: code})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.ID != budiID || codes != nil {
+		t.Errorf("got user %d and codes %v, want budi without codes", account.ID, codes)
+	}
+
+	// Neither the challenge nor the code can be used again
+	if _, _, err := u.VerifyChallenge(dto.MFALoginRequest{MFAToken: challenge.Token, Code: code}); !errors.Is(err, ErrInvalidMFAToken) {
+		t.Errorf("got error %v reusing the challenge, want ErrInvalidMFAToken", err)
+	}
+	second, _ := u.Challenge(budi)
+	if _, _, err := u.VerifyChallenge(dto.MFALoginRequest{MFAToken: second.Token, Code: code}); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("got error %v reusing the code, want ErrInvalidMFACode", err)
+	}
+
+	// Challenges expire
+	expired, _ := u.Challenge(budi)
+	clock.advance(6 * time.Minute)
+	if _, _, err := u.VerifyChallenge(dto.MFALoginRequest{MFAToken: expired.Token, Code: currentCode(t, clock, secret)}); !errors.Is(err, ErrInvalidMFAToken) {
+		t.Errorf("got error %v, want ErrInvalidMFAToken for an expired challenge", err)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	u, _, clock := newMFA(t)
+	budi, _ := u.userRepo.FindByID(budiID)
+	secret, codes := enable(t, u, clock, budiID)
+
+	// Recovery codes are accepted regardless of case and dashes, once
+	typed := strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))
+	for i, want := range []error{nil, ErrInvalidRecoveryCode} {
+		challenge, _ := u.Challenge(budi)
+		_, _, err := u.VerifyChallenge(dto.MFALoginRequest{MFAToken: challenge.Token, RecoveryCode: typed})
+		if !errors.Is(err, want) {
+			t.Errorf("got error %v on use %d, want %v", err, i+1, want)
+		}
+	}
+
+	fresh, err := u.RegenerateRecoveryCodes(budiID, dto.MFACodeRequest{Code: currentCode(t, clock, secret)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	challenge, _ := u.Challenge(budi)
+	if _, _, err := u.VerifyChallenge(dto.MFALoginRequest{MFAToken: challenge.Token, RecoveryCode: codes[1]}); !errors.Is(err, ErrInvalidRecoveryCode) {
+		t.Errorf("got error %v for a replaced code, want ErrInvalidRecoveryCode", err)
+	}
+	challenge, _ = u.Challenge(budi)
+	if _, _, err := u.VerifyChallenge(dto.MFALoginRequest{MFAToken: challenge.Token, RecoveryCode: fresh[0]}); err != nil {
+		t.Errorf("unexpected error for a new code: %v", err)
+	}
+}
+
+func TestRoleRequiresSetupDuringLogin(t *testing.T) {
+	u, _, clock := newMFA(t)
+	sari, _ := u.userRepo.FindByID(sariID)
+
+	challenge, err := u.Challenge(sari)
+	if err != nil || challenge == nil || !challenge.SetupRequired {
+		t.Fatalf("got challenge %+v and error %v, want one asking to set up", challenge, err)
+	}
+
+	setup, err := u.SetupChallenge(dto.MFATokenRequest{MFAToken: challenge.Token})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if setup.MFAToken == "" || setup.MFAToken == challenge.Token {
+		t.Fatalf("got token %q, want a new challenge to confirm with", setup.MFAToken)
+	}
+
+	account, codes, err := u.VerifyChallenge(dto.MFALoginRequest{MFAToken: setup.MFAToken, Code: currentCode(t, clock, setup.Secret)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.ID != sariID || len(codes) != recoveryCodeCount {
+		t.Errorf("got user %d and %d codes, want sari with the recovery codes", account.ID, len(codes))
+	}
+	if enabled, _ := u.Enabled(sariID); !enabled {
+		t.Error("expected two-factor authentication to be enabled")
+	}
+}
+
+func TestDisable(t *testing.T) {
+	u, enrollments, clock := newMFA(t)
+	budiSecret, _ := enable(t, u, clock, budiID)
+	sariSecret, _ := enable(t, u, clock, sariID)
+
+	if err := u.Disable(budiID, dto.DisableMFARequest{Password: "wrong", Code: currentCode(t, clock, budiSecret)}); !errors.Is(err, user.ErrInvalidCurrentPassword) {
+		t.Errorf("got error %v, want ErrInvalidCurrentPassword", err)
+	}
+	if err := u.Disable(sariID, dto.DisableMFARequest{Password: "secret", Code: currentCode(t, clock, sariSecret)}); !errors.Is(err, ErrMFARequiredByRole) {
+		t.Errorf("got error %v, want ErrMFARequiredByRole", err)
+	}
+	if err := u.Disable(budiID, dto.DisableMFARequest{Password: "secret", Code: "000000"}); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("got error %v, want ErrInvalidMFACode", err)
+	}
+
+	if err := u.Disable(budiID, dto.DisableMFARequest{Password: "secret", Code: currentCode(t, clock, budiSecret)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := enrollments.Enrollments[budiID]; ok {
+		t.Error("expected the enrollment to be removed")
+	}
+	for _, code := range enrollments.Codes {
+		if code.UserID == budiID {
+			t.Fatal("expected the recovery codes to be removed")
+		}
+	}
+	if err := u.Disable(budiID, dto.DisableMFARequest{Password: "secret"}); !errors.Is(err, ErrMFANotEnabled) {
+		t.Errorf("got error %v, want ErrMFANotEnabled", err)
+	}
+}
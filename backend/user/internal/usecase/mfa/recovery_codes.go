@@ -0,0 +1,56 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/pkg/randtoken"
+)
+
+// recoveryCodeCount is how many recovery codes a user gets at a time
+const recoveryCodeCount = 10
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// RegenerateRecoveryCodes replaces every recovery code of a user, used or not, after checking a code of the app
+func (u *mfaUsecase) RegenerateRecoveryCodes(userID uint, input dto.MFACodeRequest) ([]string, error) {
+	enrollment, err := u.findEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkCode(enrollment, input.Code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.mfaRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// newRecoveryCodes returns random codes such as "k3m9q-x7p2w" with the hashes to store
+func newRecoveryCodes() (codes, hashes []string, err error) {
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, 7)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		encoded := strings.ToLower(recoveryEncoding.EncodeToString(raw))
+		code := encoded[:5] + "-" + encoded[5:10]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes a code regardless of case, dashes and spaces, as users may type it differently
+func hashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+	return randtoken.Hash(normalized)
+}
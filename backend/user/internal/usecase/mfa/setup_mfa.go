@@ -0,0 +1,209 @@
+package mfa
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	"github.com/yuhari7/backend_supervision/internal/usecase/user"
+	"github.com/yuhari7/backend_supervision/pkg/secretbox"
+	"github.com/yuhari7/backend_supervision/pkg/totp"
+)
+
+// Config configures two-factor authentication
+type Config struct {
+	// Issuer names the account in authenticator apps next to the user's email, Supervision by default
+	Issuer string
+	// ChallengeTTL is how long the mfa_token of a login can be used, 5 minutes by default
+	ChallengeTTL time.Duration
+	// SecretKey is the base64 encoded 32 byte key the TOTP secrets are encrypted with, required
+	SecretKey string
+}
+
+type mfaUsecase struct {
+	userRepo  repository.UserRepository
+	roleRepo  repository.RoleRepository
+	mfaRepo   repository.MFARepository
+	tokenRepo repository.UserTokenRepository
+	secrets   *secretbox.Box
+	config    Config
+
+	now func() time.Time
+}
+
+// NewMFAUsecase creates a new instance of MFAUsecase, failing without a valid SecretKey
+func NewMFAUsecase(users repository.UserRepository, roles repository.RoleRepository, mfa repository.MFARepository, tokens repository.UserTokenRepository, config Config) (MFAUsecase, error) {
+	secrets, err := secretbox.FromBase64(config.SecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("MFA secret key: %w", err)
+	}
+	if config.Issuer == "" {
+		config.Issuer = "Supervision"
+	}
+	if config.ChallengeTTL <= 0 {
+		config.ChallengeTTL = 5 * time.Minute
+	}
+	return &mfaUsecase{
+		userRepo:  users,
+		roleRepo:  roles,
+		mfaRepo:   mfa,
+		tokenRepo: tokens,
+		secrets:   secrets,
+		config:    config,
+		now:       func() time.Time { return time.Now().UTC() },
+	}, nil
+}
+
+// Enabled reports whether a user confirmed two-factor authentication
+func (u *mfaUsecase) Enabled(userID uint) (bool, error) {
+	enrollment, err := u.findEnrollment(userID)
+	if err != nil || enrollment == nil {
+		return false, err
+	}
+	return enrollment.Enabled(), nil
+}
+
+// Setup starts an enrollment with a new secret, replacing one that was not confirmed
+func (u *mfaUsecase) Setup(userID uint) (*dto.MFASetupResponse, error) {
+	account, err := u.userRepo.FindByID(userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, user.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u.setup(account)
+}
+
+func (u *mfaUsecase) setup(account *entity.User) (*dto.MFASetupResponse, error) {
+	existing, err := u.findEnrollment(account.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Enabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := u.secrets.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.mfaRepo.Save(&entity.UserMFA{UserID: account.ID, Secret: sealed}); err != nil {
+		return nil, err
+	}
+
+	return &dto.MFASetupResponse{
+		Secret:     secret,
+		OTPAuthURL: totp.URI(u.config.Issuer, account.Email, secret),
+	}, nil
+}
+
+// Confirm enables two-factor authentication with a code of the app and returns the first recovery codes
+func (u *mfaUsecase) Confirm(userID uint, input dto.MFACodeRequest) ([]string, error) {
+	enrollment, err := u.findEnrollment(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, ErrMFANotSetUp
+	}
+	if enrollment.Enabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	return u.enable(enrollment, input.Code)
+}
+
+func (u *mfaUsecase) enable(enrollment *entity.UserMFA, code string) ([]string, error) {
+	if err := u.checkCode(enrollment, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	// Another request confirmed it in the meantime
+	err = u.mfaRepo.Enable(enrollment.UserID, u.now(), hashes)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// findEnrollment returns nil without an error when the user never set up two-factor authentication
+func (u *mfaUsecase) findEnrollment(userID uint) (*entity.UserMFA, error) {
+	enrollment, err := u.mfaRepo.Find(userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	return enrollment, err
+}
+
+// findEnabled returns a confirmed enrollment, ErrMFANotEnabled otherwise
+func (u *mfaUsecase) findEnabled(userID uint) (*entity.UserMFA, error) {
+	enrollment, err := u.findEnrollment(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil || !enrollment.Enabled() {
+		return nil, ErrMFANotEnabled
+	}
+	return enrollment, nil
+}
+
+// checkCode accepts each code of the app once
+func (u *mfaUsecase) checkCode(enrollment *entity.UserMFA, code string) error {
+	secret, err := u.secret(enrollment)
+	if err != nil {
+		return err
+	}
+	step, ok := totp.Validate(secret, code, u.now(), enrollment.LastStep)
+	if !ok {
+		return ErrInvalidMFACode
+	}
+	// Losing a race against another use of the same code
+	err = u.mfaRepo.ClaimStep(enrollment.UserID, step)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrInvalidMFACode
+	}
+	return err
+}
+
+// secret decrypts the TOTP secret of an enrollment
+func (u *mfaUsecase) secret(enrollment *entity.UserMFA) (string, error) {
+	return u.secrets.Open(enrollment.Secret)
+}
+
+// checkSecondFactor accepts a code of the app, or an unused recovery code when one is given
+func (u *mfaUsecase) checkSecondFactor(enrollment *entity.UserMFA, code, recoveryCode string) error {
+	if recoveryCode == "" {
+		return u.checkCode(enrollment, code)
+	}
+	err := u.mfaRepo.UseRecoveryCode(enrollment.UserID, hashRecoveryCode(recoveryCode), u.now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrInvalidRecoveryCode
+	}
+	return err
+}
+
+// roleRequiresMFA reports whether a role makes two-factor authentication mandatory, a missing role does not
+func (u *mfaUsecase) roleRequiresMFA(roleID uint) (bool, error) {
+	role, err := u.roleRepo.FindByID(roleID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return role.RequireMFA, nil
+}
@@ -0,0 +1,9 @@
+package password
+
+import "github.com/yuhari7/backend_supervision/shared/apperror"
+
+// Domain errors returned by the password usecases
+var (
+	ErrInvalidResetToken = apperror.Validation("invalid_reset_token", "invalid, used or expired password reset token", nil)
+	ErrForgotLimited     = apperror.TooManyRequests("password_reset_limited", "a password reset email was sent recently, try again later")
+)
@@ -0,0 +1,124 @@
+package password
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	"github.com/yuhari7/backend_supervision/pkg/mailer"
+	"github.com/yuhari7/backend_supervision/pkg/randtoken"
+	"github.com/yuhari7/backend_supervision/pkg/ratelimit"
+)
+
+// Config configures the password reset links
+type Config struct {
+	// ResetURL is the frontend page the link opens, the token is added as the token query parameter
+	ResetURL string
+	// TokenTTL is how long a link can be used, 1 hour by default
+	TokenTTL time.Duration
+	// ForgotCooldown is the least time between two reset mails to the same email, 1 minute by default
+	ForgotCooldown time.Duration
+	// ForgotLimit is how many reset mails an email gets per hour, 5 by default
+	ForgotLimit int
+}
+
+type passwordUsecase struct {
+	userRepo   repository.UserRepository
+	tokenRepo  repository.UserTokenRepository
+	transactor repository.Transactor
+	mailer     mailer.Mailer
+	config     Config
+	forgot     *ratelimit.Limiter
+
+	now      func() time.Time
+	dispatch func(send func()) // runs mail delivery, in the background outside tests
+}
+
+// NewPasswordUsecase creates a new instance of PasswordUsecase
+func NewPasswordUsecase(users repository.UserRepository, tokens repository.UserTokenRepository, transactor repository.Transactor, m mailer.Mailer, config Config) PasswordUsecase {
+	if config.ResetURL == "" {
+		config.ResetURL = "http://localhost:3000/reset-password"
+	}
+	if config.TokenTTL <= 0 {
+		config.TokenTTL = time.Hour
+	}
+	if config.ForgotCooldown <= 0 {
+		config.ForgotCooldown = time.Minute
+	}
+	if config.ForgotLimit <= 0 {
+		config.ForgotLimit = 5
+	}
+	return &passwordUsecase{
+		userRepo:   users,
+		tokenRepo:  tokens,
+		transactor: transactor,
+		mailer:     m,
+		config:     config,
+		forgot:     ratelimit.New(config.ForgotLimit, time.Hour, config.ForgotCooldown),
+		now:        func() time.Time { return time.Now().UTC() },
+		dispatch:   func(send func()) { go send() },
+	}
+}
+
+// Forgot mails a password reset link to the active user with the given email.
+// It succeeds whether or not the email is registered, and mail is sent in the background,
+// so neither the response nor its timing tells who has an account.
+// Every email is rate limited the same way, registered or not.
+func (u *passwordUsecase) Forgot(input dto.ForgotPasswordRequest, locale string) error {
+	if !u.forgot.Allow(strings.ToLower(strings.TrimSpace(input.Email)), u.now()) {
+		return ErrForgotLimited.With("retry_after", int(u.config.ForgotCooldown.Seconds()))
+	}
+
+	user, err := u.userRepo.FindByEmail(input.Email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.IsActive {
+		return nil
+	}
+
+	token, err := randtoken.New()
+	if err != nil {
+		return err
+	}
+	err = u.tokenRepo.Create(&entity.UserToken{
+		UserID:    user.ID,
+		Purpose:   entity.TokenPurposePasswordReset,
+		TokenHash: randtoken.Hash(token),
+		ExpiresAt: u.now().Add(u.config.TokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	link, err := url.Parse(u.config.ResetURL)
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	msg, err := mailer.Render("password_reset", locale, user.Email, map[string]interface{}{
+		"Name":         user.Name,
+		"URL":          link.String(),
+		"ValidMinutes": int(u.config.TokenTTL.Minutes()),
+	})
+	if err != nil {
+		return err
+	}
+	u.dispatch(func() {
+		if err := u.mailer.Send(msg); err != nil {
+			log.Printf("Failed to send the password reset mail of user %d: %v", user.ID, err)
+		}
+	})
+	return nil
+}
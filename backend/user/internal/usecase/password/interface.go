@@ -0,0 +1,11 @@
+package password
+
+import (
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+)
+
+type PasswordUsecase interface {
+	Forgot(input dto.ForgotPasswordRequest, locale string) error
+	Reset(input dto.ResetPasswordRequest) (*entity.User, error)
+}
@@ -0,0 +1,151 @@
+package password
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/testutil"
+	"github.com/yuhari7/backend_supervision/pkg/randtoken"
+	"github.com/yuhari7/backend_supervision/shared/apperror"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var linkPattern = regexp.MustCompile(`http\S+`)
+
+func newPasswords() (*passwordUsecase, *testutil.UserRepository, *testutil.UserTokenRepository, *testutil.Mailer) {
+	users := testutil.NewUserRepository(
+		entity.User{ID: 1, Name: "Budi", Email: "budi@example.com", IsActive: true, TokenVersion: 1},
+		entity.User{ID: 2, Name: "Sari", Email: "sari@example.com", IsActive: false, TokenVersion: 1},
+	)
+	tokens := &testutil.UserTokenRepository{}
+	mail := &testutil.Mailer{}
+	u := NewPasswordUsecase(users, tokens, &testutil.Transactor{Users: users, Tokens: tokens}, mail, Config{ResetURL: "https://app.example.com/reset-password"}).(*passwordUsecase)
+	u.dispatch = func(send func()) { send() }
+	return u, users, tokens, mail
+}
+
+// resetToken returns the token of the link in the last mail
+func resetToken(t *testing.T, mail *testutil.Mailer) string {
+	t.Helper()
+	if len(mail.Sent) == 0 {
+		t.Fatal("no mail was sent")
+	}
+	link, err := url.Parse(linkPattern.FindString(mail.Sent[len(mail.Sent)-1].Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return link.Query().Get("token")
+}
+
+func TestForgotMailsResetLink(t *testing.T) {
+	u, _, tokens, mail := newPasswords()
+
+	if err := u.Forgot(dto.ForgotPasswordRequest{Email: "budi@example.com"}, "en"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mail.Sent) != 1 || mail.Sent[0].To != "budi@example.com" || mail.Sent[0].Subject != "Reset your password" {
+		t.Fatalf("got mails %+v, want one English reset mail to budi", mail.Sent)
+	}
+	if !strings.Contains(mail.Sent[0].Body, "https://app.example.com/reset-password?token=") || !strings.Contains(mail.Sent[0].Body, "60 minutes") {
+		t.Errorf("got body %q, want the reset link valid for 60 minutes", mail.Sent[0].Body)
+	}
+
+	token := resetToken(t, mail)
+	if stored := tokens.Tokens[0]; stored.TokenHash != randtoken.Hash(token) || stored.Purpose != entity.TokenPurposePasswordReset {
+		t.Errorf("got stored token %+v, want the SHA-256 of the mailed token", stored)
+	}
+}
+
+func TestForgotDoesNotRevealAccounts(t *testing.T) {
+	u, _, tokens, mail := newPasswords()
+
+	for _, email := range []string{"nobody@example.com", "sari@example.com"} {
+		if err := u.Forgot(dto.ForgotPasswordRequest{Email: email}, "id"); err != nil {
+			t.Errorf("got error %v for %s, want the same success as a registered email", err, email)
+		}
+	}
+	if len(mail.Sent) != 0 || len(tokens.Tokens) != 0 {
+		t.Errorf("got %d mails and %d tokens, want none for unknown or inactive users", len(mail.Sent), len(tokens.Tokens))
+	}
+}
+
+func TestResetIsSingleUse(t *testing.T) {
+	u, users, _, mail := newPasswords()
+	u.Forgot(dto.ForgotPasswordRequest{Email: "budi@example.com"}, "id")
+	first := resetToken(t, mail)
+	// Ask again once the cooldown has passed
+	u.now = func() time.Time { return time.Now().UTC().Add(2 * time.Minute) }
+	u.Forgot(dto.ForgotPasswordRequest{Email: "budi@example.com"}, "id")
+	second := resetToken(t, mail)
+
+	user, err := u.Reset(dto.ResetPasswordRequest{Token: first, NewPassword: "newsecret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(users.Users[1].Password), []byte("newsecret")) != nil {
+		t.Error("password was not changed")
+	}
+	if user.TokenVersion != 2 {
+		t.Errorf("got token version %d, want 2 so every session ends", user.TokenVersion)
+	}
+
+	// The used link and the other links of the user stop working
+	for _, token := range []string{first, second, "made-up"} {
+		if _, err := u.Reset(dto.ResetPasswordRequest{Token: token, NewPassword: "another"}); !errors.Is(err, ErrInvalidResetToken) {
+			t.Errorf("got error %v, want ErrInvalidResetToken", err)
+		}
+	}
+}
+
+func TestResetTokenExpires(t *testing.T) {
+	u, _, _, mail := newPasswords()
+	u.Forgot(dto.ForgotPasswordRequest{Email: "budi@example.com"}, "id")
+	token := resetToken(t, mail)
+
+	u.now = func() time.Time { return time.Now().UTC().Add(61 * time.Minute) }
+	if _, err := u.Reset(dto.ResetPasswordRequest{Token: token, NewPassword: "newsecret"}); !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("got error %v, want ErrInvalidResetToken", err)
+	}
+}
+
+func TestForgotIsRateLimited(t *testing.T) {
+	u, _, _, mail := newPasswords()
+
+	for _, email := range []string{"budi@example.com", "nobody@example.com"} {
+		if err := u.Forgot(dto.ForgotPasswordRequest{Email: email}, "id"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err := u.Forgot(dto.ForgotPasswordRequest{Email: strings.ToUpper(email)}, "id")
+		if !errors.Is(err, ErrForgotLimited) || apperror.KindOf(err) != apperror.KindTooManyRequests {
+			t.Errorf("got error %v asking again for %s within the cooldown, want ErrForgotLimited", err, email)
+		}
+	}
+	if len(mail.Sent) != 1 {
+		t.Errorf("got %d mails, want 1", len(mail.Sent))
+	}
+}
+
+func TestFailedResetKeepsLink(t *testing.T) {
+	u, users, tokens, mail := newPasswords()
+	u.Forgot(dto.ForgotPasswordRequest{Email: "budi@example.com"}, "id")
+	token := resetToken(t, mail)
+
+	users.UpdateErr = errors.New("connection reset")
+	if _, err := u.Reset(dto.ResetPasswordRequest{Token: token, NewPassword: "newsecret"}); err == nil {
+		t.Fatal("expected the failed update to be reported")
+	}
+	if tokens.Tokens[0].UsedAt != nil {
+		t.Fatal("the link was used up although the password did not change")
+	}
+
+	users.UpdateErr = nil
+	if _, err := u.Reset(dto.ResetPasswordRequest{Token: token, NewPassword: "newsecret"}); err != nil {
+		t.Errorf("unexpected error retrying the reset: %v", err)
+	}
+}
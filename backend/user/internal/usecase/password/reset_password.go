@@ -0,0 +1,51 @@
+package password
+
+import (
+	"errors"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	"github.com/yuhari7/backend_supervision/pkg/randtoken"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Reset sets a new password with a token from a reset link. The token can only be used once,
+// the other links of the user stop working and every session of the user ends.
+// Using the token and changing the password are committed together, a failed reset leaves the link usable.
+func (u *passwordUsecase) Reset(input dto.ResetPasswordRequest) (*entity.User, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+
+	var user *entity.User
+	err = u.transactor.Transaction(func(tx repository.Repositories) error {
+		token, err := tx.Tokens.Consume(entity.TokenPurposePasswordReset, randtoken.Hash(input.Token), u.now())
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidResetToken
+		}
+		if err != nil {
+			return err
+		}
+
+		user, err = tx.Users.FindByID(token.UserID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidResetToken
+		}
+		if err != nil {
+			return err
+		}
+
+		user.Password = string(hashed)
+		user.TokenVersion++
+		if err := tx.Users.Update(user); err != nil {
+			return err
+		}
+		return tx.Tokens.RevokeUser(user.ID, entity.TokenPurposePasswordReset)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
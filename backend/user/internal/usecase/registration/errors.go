@@ -0,0 +1,12 @@
+package registration
+
+import "github.com/yuhari7/backend_supervision/shared/apperror"
+
+// Domain errors returned by the registration usecases
+var (
+	ErrRegistrationClosed = apperror.Forbidden("registration_closed", "registration is closed")
+	ErrInviteRequired     = apperror.Forbidden("invite_required", "registration requires an invite")
+	ErrInvalidInvite      = apperror.Validation("invalid_invite", "invalid, used or expired invite", nil)
+	ErrInviteNotFound     = apperror.NotFound("invite_not_found", "invite not found")
+	ErrUnknownRole        = apperror.Validation("unknown_role", "unknown role", nil)
+)
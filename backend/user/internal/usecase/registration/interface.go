@@ -0,0 +1,24 @@
+package registration
+
+import (
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+)
+
+type RegistrationUsecase interface {
+	Register(input dto.RegisterRequest) (*entity.User, error)
+	CreateInvite(input dto.CreateInviteRequest, createdBy uint) (*dto.InviteCreatedResponse, error)
+	ListInvites() ([]dto.InviteResponse, error)
+	RevokeInvite(id uint) error
+}
+
+// toResponse leaves out the token hash
+func toResponse(invite entity.Invite) dto.InviteResponse {
+	return dto.InviteResponse{
+		ID:        invite.ID,
+		Email:     invite.Email,
+		RoleID:    invite.RoleID,
+		ExpiresAt: invite.ExpiresAt,
+		CreatedAt: invite.CreatedAt,
+	}
+}
@@ -0,0 +1,70 @@
+package registration
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	"github.com/yuhari7/backend_supervision/pkg/randtoken"
+)
+
+// CreateInvite issues an invite for a role, the token is only returned here
+func (u *registrationUsecase) CreateInvite(input dto.CreateInviteRequest, createdBy uint) (*dto.InviteCreatedResponse, error) {
+	_, err := u.roleRepo.FindByID(input.RoleID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrUnknownRole
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := randtoken.New()
+	if err != nil {
+		return nil, err
+	}
+	invite := &entity.Invite{
+		TokenHash: randtoken.Hash(token),
+		Email:     input.Email,
+		RoleID:    input.RoleID,
+		CreatedBy: &createdBy,
+		ExpiresAt: u.now().Add(u.config.InviteTTL),
+	}
+	if err := u.inviteRepo.Create(invite); err != nil {
+		return nil, err
+	}
+
+	link, err := url.Parse(u.config.InviteURL)
+	if err != nil {
+		return nil, err
+	}
+	query := link.Query()
+	query.Set("invite", token)
+	link.RawQuery = query.Encode()
+
+	return &dto.InviteCreatedResponse{Invite: toResponse(*invite), Token: token, URL: link.String()}, nil
+}
+
+// ListInvites lists the invites that can still be used
+func (u *registrationUsecase) ListInvites() ([]dto.InviteResponse, error) {
+	invites, err := u.inviteRepo.FindPending(u.now())
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.InviteResponse, 0, len(invites))
+	for _, invite := range invites {
+		responses = append(responses, toResponse(invite))
+	}
+	return responses, nil
+}
+
+// RevokeInvite deletes an invite so it can no longer be used
+func (u *registrationUsecase) RevokeInvite(id uint) error {
+	err := u.inviteRepo.Delete(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrInviteNotFound
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package registration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	"github.com/yuhari7/backend_supervision/internal/usecase/user"
+	"github.com/yuhari7/backend_supervision/pkg/randtoken"
+)
+
+// Registration modes
+const (
+	ModeOpen   = "open"   // anyone can register, with the default role unless they have an invite
+	ModeInvite = "invite" // only invited people can register
+	ModeOff    = "off"    // nobody can register, admins create users
+)
+
+// Config configures who can register and with which role
+type Config struct {
+	// Mode is ModeOpen, ModeInvite or ModeOff, ModeOpen by default
+	Mode string
+	// DefaultRole is the name of the role given to registrations without an invite, contributor by default
+	DefaultRole string
+	// InviteURL is the frontend page invites open, the token is added as the invite query parameter
+	InviteURL string
+	// InviteTTL is how long an invite can be used, 7 days by default
+	InviteTTL time.Duration
+}
+
+type registrationUsecase struct {
+	roleRepo   repository.RoleRepository
+	inviteRepo repository.InviteRepository
+	transactor repository.Transactor
+	config     Config
+
+	defaultRoleID uint // ID of config.DefaultRole, only resolved when registration is open
+
+	now func() time.Time
+}
+
+// NewRegistrationUsecase creates a new instance of RegistrationUsecase.
+// It fails on an unknown mode, and on an unknown default role when registration is open.
+func NewRegistrationUsecase(roles repository.RoleRepository, invites repository.InviteRepository, transactor repository.Transactor, config Config) (RegistrationUsecase, error) {
+	switch config.Mode {
+	case "":
+		config.Mode = ModeOpen
+	case ModeOpen, ModeInvite, ModeOff:
+	default:
+		return nil, fmt.Errorf("unknown registration mode %q", config.Mode)
+	}
+	if config.DefaultRole == "" {
+		config.DefaultRole = "contributor"
+	}
+	if config.InviteURL == "" {
+		config.InviteURL = "http://localhost:3000/register"
+	}
+	if config.InviteTTL <= 0 {
+		config.InviteTTL = 7 * 24 * time.Hour
+	}
+
+	u := &registrationUsecase{
+		roleRepo:   roles,
+		inviteRepo: invites,
+		transactor: transactor,
+		config:     config,
+		now:        func() time.Time { return time.Now().UTC() },
+	}
+	if config.Mode == ModeOpen {
+		role, err := roles.FindByName(config.DefaultRole)
+		if err != nil {
+			return nil, fmt.Errorf("default role %q: %w", config.DefaultRole, err)
+		}
+		u.defaultRoleID = role.ID
+	}
+	return u, nil
+}
+
+// Register creates a self-registered account. The role is never taken from the request:
+// it is the role of the invite, or the default role when registration is open.
+// Claiming the invite, creating the user and marking the invite used happen in one transaction,
+// so a failed registration leaves the invite pending.
+func (u *registrationUsecase) Register(input dto.RegisterRequest) (*entity.User, error) {
+	if u.config.Mode == ModeOff {
+		return nil, ErrRegistrationClosed
+	}
+	if input.InviteToken == "" && u.config.Mode == ModeInvite {
+		return nil, ErrInviteRequired
+	}
+
+	var created *entity.User
+	err := u.transactor.Transaction(func(tx repository.Repositories) error {
+		if input.InviteToken == "" {
+			var err error
+			created, err = user.NewUserUsecase(tx.Users, repository.Joined(tx)).Register(dto.CreateUserRequest{Name: input.Name, Email: input.Email, Password: input.Password, RoleID: u.defaultRoleID})
+			return err
+		}
+
+		invite, err := tx.Invites.Claim(randtoken.Hash(input.InviteToken), u.now())
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidInvite
+		}
+		if err != nil {
+			return err
+		}
+		if invite.Email != "" && !strings.EqualFold(invite.Email, input.Email) {
+			return ErrInvalidInvite
+		}
+
+		created, err = user.NewUserUsecase(tx.Users, repository.Joined(tx)).Register(dto.CreateUserRequest{Name: input.Name, Email: input.Email, Password: input.Password, RoleID: invite.RoleID})
+		if err != nil {
+			return err
+		}
+		return tx.Invites.MarkUsedBy(invite.ID, created.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
@@ -0,0 +1,169 @@
+package registration
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/testutil"
+	"github.com/yuhari7/backend_supervision/internal/usecase/user"
+)
+
+const adminID uint = 1
+
+func newRegistrations(t *testing.T, mode string) (*registrationUsecase, *testutil.UserRepository, *testutil.InviteRepository) {
+	t.Helper()
+	users := testutil.NewUserRepository()
+	invites := &testutil.InviteRepository{}
+	roles := testutil.NewRoleRepository(entity.Role{ID: 1, Name: "admin"}, entity.Role{ID: 2, Name: "contributor"}, entity.Role{ID: 3, Name: "editor"})
+	u, err := NewRegistrationUsecase(roles, invites, &testutil.Transactor{Users: users, Invites: invites}, Config{Mode: mode, InviteURL: "https://app.example.com/register"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.(*registrationUsecase), users, invites
+}
+
+func newInvite(t *testing.T, u *registrationUsecase, input dto.CreateInviteRequest) string {
+	t.Helper()
+	invite, err := u.CreateInvite(input, adminID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	link, err := url.Parse(invite.URL)
+	if err != nil || link.Query().Get("invite") != invite.Token {
+		t.Fatalf("got URL %q, want the token in the invite parameter", invite.URL)
+	}
+	return invite.Token
+}
+
+func TestOpenRegistrationGivesDefaultRole(t *testing.T) {
+	u, users, _ := newRegistrations(t, "")
+
+	created, err := u.Register(dto.RegisterRequest{Name: "Budi", Email: "budi@example.com", Password: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.RoleID != 2 || users.Users[created.ID].RoleID != 2 {
+		t.Errorf("got role %d, want the contributor role", created.RoleID)
+	}
+}
+
+func TestInviteAssignsRole(t *testing.T) {
+	u, _, invites := newRegistrations(t, ModeInvite)
+
+	if _, err := u.Register(dto.RegisterRequest{Name: "Budi", Email: "budi@example.com", Password: "secret"}); !errors.Is(err, ErrInviteRequired) {
+		t.Fatalf("got error %v, want ErrInviteRequired", err)
+	}
+
+	token := newInvite(t, u, dto.CreateInviteRequest{RoleID: 3})
+	created, err := u.Register(dto.RegisterRequest{Name: "Budi", Email: "budi@example.com", Password: "secret", InviteToken: token})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.RoleID != 3 {
+		t.Errorf("got role %d, want the editor role of the invite", created.RoleID)
+	}
+	if stored := invites.Invites[0]; stored.UsedBy == nil || *stored.UsedBy != created.ID || *stored.CreatedBy != adminID {
+		t.Errorf("got invite %+v, want it issued by the admin and used by the new user", stored)
+	}
+
+	if _, err := u.Register(dto.RegisterRequest{Name: "Sari", Email: "sari@example.com", Password: "secret", InviteToken: token}); !errors.Is(err, ErrInvalidInvite) {
+		t.Errorf("got error %v reusing the invite, want ErrInvalidInvite", err)
+	}
+}
+
+func TestFailedRegistrationKeepsInvite(t *testing.T) {
+	u, users, invites := newRegistrations(t, ModeInvite)
+	if err := users.Create(&entity.User{Email: "taken@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	token := newInvite(t, u, dto.CreateInviteRequest{Email: "sari@example.com", RoleID: 3})
+
+	tests := []struct {
+		email string
+		want  error
+	}{
+		{"budi@example.com", ErrInvalidInvite},
+		{"taken@example.com", ErrInvalidInvite},
+		{"Sari@example.com", nil},
+	}
+	for _, tt := range tests {
+		_, err := u.Register(dto.RegisterRequest{Name: "Sari", Email: tt.email, Password: "secret", InviteToken: token})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("got error %v for %s, want %v", err, tt.email, tt.want)
+		}
+	}
+
+	// An invite without an email only fails on the registration itself
+	open := newInvite(t, u, dto.CreateInviteRequest{RoleID: 3})
+	if _, err := u.Register(dto.RegisterRequest{Name: "Budi", Email: "taken@example.com", Password: "secret", InviteToken: open}); !errors.Is(err, user.ErrEmailAlreadyRegistered) {
+		t.Errorf("got error %v, want ErrEmailAlreadyRegistered", err)
+	}
+	if len(users.Users) != 2 || invites.Invites[1].UsedAt != nil {
+		t.Errorf("got %d users and invite %+v, want the failed registration rolled back", len(users.Users), invites.Invites[1])
+	}
+	if _, err := u.Register(dto.RegisterRequest{Name: "Budi", Email: "budi@example.com", Password: "secret", InviteToken: open}); err != nil {
+		t.Errorf("unexpected error after the failed registration: %v", err)
+	}
+}
+
+func TestClosedRegistration(t *testing.T) {
+	u, users, _ := newRegistrations(t, ModeOff)
+	token := newInvite(t, u, dto.CreateInviteRequest{RoleID: 3})
+
+	for _, invite := range []string{"", token} {
+		if _, err := u.Register(dto.RegisterRequest{Name: "Budi", Email: "budi@example.com", Password: "secret", InviteToken: invite}); !errors.Is(err, ErrRegistrationClosed) {
+			t.Errorf("got error %v, want ErrRegistrationClosed", err)
+		}
+	}
+	if len(users.Users) != 0 {
+		t.Errorf("got %d registrations, want none", len(users.Users))
+	}
+
+	if _, err := NewRegistrationUsecase(nil, nil, nil, Config{Mode: "closed"}); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestUnknownDefaultRoleFailsAtStartup(t *testing.T) {
+	roles := testutil.NewRoleRepository(entity.Role{ID: 1, Name: "admin"})
+
+	if _, err := NewRegistrationUsecase(roles, nil, nil, Config{Mode: ModeOpen, DefaultRole: "member"}); err == nil {
+		t.Error("expected an error for an unknown default role")
+	}
+	// Without open registration the default role is never given
+	if _, err := NewRegistrationUsecase(roles, nil, nil, Config{Mode: ModeInvite, DefaultRole: "member"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestInvitesExpireAndCanBeRevoked(t *testing.T) {
+	u, _, _ := newRegistrations(t, ModeInvite)
+
+	if _, err := u.CreateInvite(dto.CreateInviteRequest{RoleID: 99}, adminID); !errors.Is(err, ErrUnknownRole) {
+		t.Errorf("got error %v, want ErrUnknownRole", err)
+	}
+
+	expiring := newInvite(t, u, dto.CreateInviteRequest{RoleID: 2})
+	newInvite(t, u, dto.CreateInviteRequest{RoleID: 3})
+	if err := u.RevokeInvite(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := u.RevokeInvite(2); !errors.Is(err, ErrInviteNotFound) {
+		t.Errorf("got error %v, want ErrInviteNotFound", err)
+	}
+	if invites, _ := u.ListInvites(); len(invites) != 1 || invites[0].RoleID != 2 {
+		t.Errorf("got invites %+v, want only the contributor invite", invites)
+	}
+
+	u.now = func() time.Time { return time.Now().UTC().Add(8 * 24 * time.Hour) }
+	if invites, _ := u.ListInvites(); len(invites) != 0 {
+		t.Errorf("got invites %+v, want none after they expired", invites)
+	}
+	if _, err := u.Register(dto.RegisterRequest{Name: "Budi", Email: "budi@example.com", Password: "secret", InviteToken: expiring}); !errors.Is(err, ErrInvalidInvite) {
+		t.Errorf("got error %v, want ErrInvalidInvite", err)
+	}
+}
@@ -0,0 +1,77 @@
+package role
+
+import (
+	"errors"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
+// AdminRole is the role seeded with every permission, it cannot be changed so admins cannot lock themselves out
+const AdminRole = "admin"
+
+type roleUsecase struct {
+	roleRepo   repository.RoleRepository
+	transactor repository.Transactor
+}
+
+func NewRoleUsecase(repo repository.RoleRepository, transactor repository.Transactor) RoleUsecase {
+	return &roleUsecase{roleRepo: repo, transactor: transactor}
+}
+
+func (u *roleUsecase) CreateRole(input dto.RoleRequest) (*dto.RoleResponse, error) {
+	if err := u.checkName(input.Name, 0); err != nil {
+		return nil, err
+	}
+	permissions, err := u.resolvePermissions(input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &entity.Role{Name: input.Name, Permissions: permissions}
+	if err := u.roleRepo.Create(role); err != nil {
+		return nil, err
+	}
+
+	response := toResponse(*role)
+	return &response, nil
+}
+
+// checkName fails when another role than the one with the given ID already has the name
+func (u *roleUsecase) checkName(name string, id uint) error {
+	existing, err := u.roleRepo.FindByName(name)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != id {
+		return ErrRoleNameTaken
+	}
+	return nil
+}
+
+// resolvePermissions loads the named permissions, listing the unknown ones in the error
+func (u *roleUsecase) resolvePermissions(names []string) ([]entity.Permission, error) {
+	permissions, err := u.roleRepo.FindPermissionsByName(names)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		found[permission.Name] = true
+	}
+	var unknown []string
+	for _, name := range names {
+		if !found[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, ErrUnknownPermission.With("permissions", unknown)
+	}
+	return permissions, nil
+}
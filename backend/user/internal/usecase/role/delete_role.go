@@ -0,0 +1,32 @@
+package role
+
+import (
+	"errors"
+
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
+// DeleteRole deletes a role no user has, users must be moved to another role first
+func (u *roleUsecase) DeleteRole(id uint) error {
+	role, err := u.findRole(id)
+	if err != nil {
+		return err
+	}
+	if role.Name == AdminRole {
+		return ErrRoleProtected
+	}
+
+	users, err := u.roleRepo.CountUsers(id)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return ErrRoleInUse
+	}
+
+	err = u.roleRepo.Delete(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrRoleNotFound
+	}
+	return err
+}
@@ -0,0 +1,12 @@
+package role
+
+import "github.com/yuhari7/backend_supervision/shared/apperror"
+
+// Domain errors returned by the role usecases
+var (
+	ErrRoleNotFound      = apperror.NotFound("role_not_found", "role not found")
+	ErrRoleNameTaken     = apperror.Conflict("role_name_taken", "a role with this name already exists")
+	ErrRoleInUse         = apperror.Conflict("role_in_use", "role is still assigned to users")
+	ErrRoleProtected     = apperror.Unprocessable("role_protected", "the admin role cannot be changed or deleted")
+	ErrUnknownPermission = apperror.Validation("unknown_permission", "unknown permission", nil)
+)
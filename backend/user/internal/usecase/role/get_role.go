@@ -0,0 +1,30 @@
+package role
+
+import (
+	"errors"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
+func (u *roleUsecase) GetRole(id uint) (*dto.RoleResponse, error) {
+	role, err := u.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+	response := toResponse(*role)
+	return &response, nil
+}
+
+// findRole loads a role, reporting a missing row as ErrRoleNotFound
+func (u *roleUsecase) findRole(id uint) (*entity.Role, error) {
+	role, err := u.roleRepo.FindByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
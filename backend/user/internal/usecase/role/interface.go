@@ -0,0 +1,21 @@
+package role
+
+import (
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+)
+
+type RoleUsecase interface {
+	ListRoles() ([]dto.RoleResponse, error)
+	GetRole(id uint) (*dto.RoleResponse, error)
+	CreateRole(input dto.RoleRequest) (*dto.RoleResponse, error)
+	UpdateRole(id uint, input dto.RoleRequest) (*dto.RoleResponse, error)
+	DeleteRole(id uint) error
+	SetRequireMFA(id uint, input dto.RoleMFARequest) (*dto.RoleResponse, error)
+	ListPermissions() ([]dto.PermissionResponse, error)
+}
+
+// toResponse lists the permission names of a role
+func toResponse(role entity.Role) dto.RoleResponse {
+	return dto.RoleResponse{ID: role.ID, Name: role.Name, Permissions: role.PermissionNames(), RequireMFA: role.RequireMFA}
+}
@@ -0,0 +1,30 @@
+package role
+
+import "github.com/yuhari7/backend_supervision/internal/common/dto"
+
+func (u *roleUsecase) ListRoles() ([]dto.RoleResponse, error) {
+	roles, err := u.roleRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.RoleResponse, 0, len(roles))
+	for _, role := range roles {
+		response = append(response, toResponse(role))
+	}
+	return response, nil
+}
+
+// ListPermissions returns every permission roles can be granted
+func (u *roleUsecase) ListPermissions() ([]dto.PermissionResponse, error) {
+	permissions, err := u.roleRepo.FindPermissions()
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.PermissionResponse, 0, len(permissions))
+	for _, permission := range permissions {
+		response = append(response, dto.PermissionResponse{Name: permission.Name, Description: permission.Description})
+	}
+	return response, nil
+}
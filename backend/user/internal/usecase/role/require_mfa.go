@@ -0,0 +1,33 @@
+package role
+
+import (
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
+// SetRequireMFA turns the two-factor authentication requirement of a role on or off, the admin role included.
+// Turning it on ends the sessions of the role's users, who are asked to set it up on their next login.
+func (u *roleUsecase) SetRequireMFA(id uint, input dto.RoleMFARequest) (*dto.RoleResponse, error) {
+	role, err := u.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+
+	turnedOn := input.RequireMFA && !role.RequireMFA
+	role.RequireMFA = input.RequireMFA
+	err = u.transactor.Transaction(func(tx repository.Repositories) error {
+		if err := tx.Roles.Update(role); err != nil {
+			return err
+		}
+		if turnedOn {
+			return tx.Users.IncrementTokenVersionByRole(role.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := toResponse(*role)
+	return &response, nil
+}
@@ -0,0 +1,166 @@
+package role
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/testutil"
+)
+
+var (
+	admin       = entity.Role{ID: 1, Name: "admin", Permissions: []entity.Permission{{Name: "users:manage"}, {Name: "roles:manage"}}}
+	contributor = entity.Role{ID: 2, Name: "contributor", Permissions: []entity.Permission{{Name: "articles:create"}}}
+)
+
+func TestCreateRole(t *testing.T) {
+	repo := testutil.NewRoleRepository(admin, contributor)
+	usecase := NewRoleUsecase(repo, &testutil.Transactor{Roles: repo})
+
+	role, err := usecase.CreateRole(dto.RoleRequest{Name: "editor", Permissions: []string{"articles:create", "articles:update"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if role.ID != 3 || role.Name != "editor" || !reflect.DeepEqual(role.Permissions, []string{"articles:create", "articles:update"}) {
+		t.Errorf("got role %+v, want editor with both article permissions", role)
+	}
+
+	if _, err := usecase.CreateRole(dto.RoleRequest{Name: "contributor"}); !errors.Is(err, ErrRoleNameTaken) {
+		t.Errorf("got error %v, want ErrRoleNameTaken", err)
+	}
+	if _, err := usecase.CreateRole(dto.RoleRequest{Name: "reviewer", Permissions: []string{"articles:create", "articles:approve"}}); !errors.Is(err, ErrUnknownPermission) {
+		t.Errorf("got error %v, want ErrUnknownPermission", err)
+	}
+	if _, err := repo.FindByName("reviewer"); err == nil {
+		t.Error("role with an unknown permission was created")
+	}
+}
+
+func TestUpdateRoleReplacesPermissions(t *testing.T) {
+	repo := testutil.NewRoleRepository(admin, contributor)
+	usecase := NewRoleUsecase(repo, &testutil.Transactor{Users: testutil.NewUserRepository(), Roles: repo})
+
+	role, err := usecase.UpdateRole(contributor.ID, dto.RoleRequest{Name: "writer", Permissions: []string{"articles:update"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if role.Name != "writer" || !reflect.DeepEqual(role.Permissions, []string{"articles:update"}) {
+		t.Errorf("got role %+v, want writer with articles:update only", role)
+	}
+	if stored := repo.Roles[contributor.ID]; !reflect.DeepEqual(stored.PermissionNames(), []string{"articles:update"}) {
+		t.Errorf("got stored permissions %v, want articles:update only", stored.PermissionNames())
+	}
+
+	// Keeping its own name is not a conflict
+	if _, err := usecase.UpdateRole(contributor.ID, dto.RoleRequest{Name: "writer"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := usecase.UpdateRole(99, dto.RoleRequest{Name: "ghost"}); !errors.Is(err, ErrRoleNotFound) {
+		t.Errorf("got error %v, want ErrRoleNotFound", err)
+	}
+}
+
+func TestAdminRoleIsProtected(t *testing.T) {
+	repo := testutil.NewRoleRepository(admin, contributor)
+	usecase := NewRoleUsecase(repo, &testutil.Transactor{Roles: repo})
+
+	if _, err := usecase.UpdateRole(admin.ID, dto.RoleRequest{Name: "admin"}); !errors.Is(err, ErrRoleProtected) {
+		t.Errorf("got error %v updating admin, want ErrRoleProtected", err)
+	}
+	if err := usecase.DeleteRole(admin.ID); !errors.Is(err, ErrRoleProtected) {
+		t.Errorf("got error %v deleting admin, want ErrRoleProtected", err)
+	}
+}
+
+func TestSetRequireMFA(t *testing.T) {
+	repo := testutil.NewRoleRepository(admin, contributor)
+	users := testutil.NewUserRepository(
+		entity.User{ID: 1, Email: "budi@example.com", RoleID: admin.ID, TokenVersion: 1},
+		entity.User{ID: 2, Email: "sari@example.com", RoleID: contributor.ID, TokenVersion: 1},
+	)
+	usecase := NewRoleUsecase(repo, &testutil.Transactor{Users: users, Roles: repo})
+
+	// Unlike its name and permissions, the requirement of the admin role can change
+	role, err := usecase.SetRequireMFA(admin.ID, dto.RoleMFARequest{RequireMFA: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !role.RequireMFA || !repo.Roles[admin.ID].RequireMFA {
+		t.Errorf("got role %+v, want admin to require MFA", role)
+	}
+	if !reflect.DeepEqual(repo.Roles[admin.ID].PermissionNames(), admin.PermissionNames()) {
+		t.Errorf("got permissions %v, want them unchanged", repo.Roles[admin.ID].PermissionNames())
+	}
+	if users.Users[1].TokenVersion != 2 || users.Users[2].TokenVersion != 1 {
+		t.Errorf("got token versions %d and %d, want only the sessions of the admin ended", users.Users[1].TokenVersion, users.Users[2].TokenVersion)
+	}
+
+	// Sessions are only ended when the requirement turns on
+	if _, err := usecase.SetRequireMFA(admin.ID, dto.RoleMFARequest{RequireMFA: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := usecase.SetRequireMFA(admin.ID, dto.RoleMFARequest{RequireMFA: false}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if users.Users[1].TokenVersion != 2 || repo.Roles[admin.ID].RequireMFA {
+		t.Errorf("got token version %d and role %+v, want the requirement off and no other session ended", users.Users[1].TokenVersion, repo.Roles[admin.ID])
+	}
+
+	if _, err := usecase.SetRequireMFA(99, dto.RoleMFARequest{RequireMFA: true}); !errors.Is(err, ErrRoleNotFound) {
+		t.Errorf("got error %v, want ErrRoleNotFound", err)
+	}
+}
+
+func TestRemovedPermissionsEndSessions(t *testing.T) {
+	editor := entity.Role{ID: 3, Name: "editor", Permissions: []entity.Permission{{Name: "articles:create"}}}
+	repo := testutil.NewRoleRepository(admin, contributor, editor)
+	users := testutil.NewUserRepository(
+		entity.User{ID: 1, Email: "budi@example.com", RoleID: editor.ID, TokenVersion: 1},
+		entity.User{ID: 2, Email: "sari@example.com", RoleID: contributor.ID, TokenVersion: 1},
+	)
+	usecase := NewRoleUsecase(repo, &testutil.Transactor{Users: users, Roles: repo})
+
+	// Renaming and adding permissions keep the sessions
+	if _, err := usecase.UpdateRole(editor.ID, dto.RoleRequest{Name: "senior editor", Permissions: []string{"articles:create", "articles:update"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if users.Users[1].TokenVersion != 1 {
+		t.Errorf("got token version %d, want the sessions kept when no permission was removed", users.Users[1].TokenVersion)
+	}
+
+	if _, err := usecase.UpdateRole(editor.ID, dto.RoleRequest{Name: "senior editor", Permissions: []string{"articles:update"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if users.Users[1].TokenVersion != 2 || users.Users[2].TokenVersion != 1 {
+		t.Errorf("got token versions %d and %d, want only the sessions of the editor ended", users.Users[1].TokenVersion, users.Users[2].TokenVersion)
+	}
+
+	// The role keeps its permissions when the sessions cannot be ended
+	users.Err = errors.New("connection reset")
+	if _, err := usecase.UpdateRole(editor.ID, dto.RoleRequest{Name: "senior editor"}); err == nil {
+		t.Fatal("expected the failed update to be reported")
+	}
+	if got := repo.Roles[editor.ID].PermissionNames(); !reflect.DeepEqual(got, []string{"articles:update"}) {
+		t.Errorf("got permissions %v, want the update rolled back", got)
+	}
+}
+
+func TestDeleteRoleInUse(t *testing.T) {
+	repo := testutil.NewRoleRepository(admin, contributor)
+	repo.UserCounts[contributor.ID] = 3
+	usecase := NewRoleUsecase(repo, &testutil.Transactor{Roles: repo})
+
+	if err := usecase.DeleteRole(contributor.ID); !errors.Is(err, ErrRoleInUse) {
+		t.Errorf("got error %v, want ErrRoleInUse", err)
+	}
+
+	repo.UserCounts[contributor.ID] = 0
+	if err := usecase.DeleteRole(contributor.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := usecase.GetRole(contributor.ID); !errors.Is(err, ErrRoleNotFound) {
+		t.Errorf("got error %v, want ErrRoleNotFound after deleting", err)
+	}
+}
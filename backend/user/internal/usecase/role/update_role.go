@@ -0,0 +1,54 @@
+package role
+
+import (
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
+// UpdateRole renames a role and replaces its permissions.
+// Removing a permission ends the sessions of the role's users, so no access token keeps it.
+func (u *roleUsecase) UpdateRole(id uint, input dto.RoleRequest) (*dto.RoleResponse, error) {
+	role, err := u.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+	if role.Name == AdminRole {
+		return nil, ErrRoleProtected
+	}
+	if err := u.checkName(input.Name, role.ID); err != nil {
+		return nil, err
+	}
+	permissions, err := u.resolvePermissions(input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		kept[permission.Name] = true
+	}
+	removed := false
+	for _, permission := range role.Permissions {
+		if !kept[permission.Name] {
+			removed = true
+		}
+	}
+
+	role.Name = input.Name
+	role.Permissions = permissions
+	err = u.transactor.Transaction(func(tx repository.Repositories) error {
+		if err := tx.Roles.Update(role); err != nil {
+			return err
+		}
+		if removed {
+			return tx.Users.IncrementTokenVersionByRole(role.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := toResponse(*role)
+	return &response, nil
+}
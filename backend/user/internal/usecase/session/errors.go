@@ -0,0 +1,11 @@
+package session
+
+import "github.com/yuhari7/backend_supervision/shared/apperror"
+
+// Domain errors returned by the session usecases
+var (
+	ErrInvalidRefreshToken = apperror.Unauthorized("invalid_refresh_token", "invalid or expired refresh token")
+	ErrRefreshTokenReused  = apperror.Unauthorized("refresh_token_reused", "refresh token was already used, the session has been ended")
+	ErrSessionRevoked      = apperror.Unauthorized("session_revoked", "session was ended, sign in again")
+	ErrMFASetupRequired    = apperror.Unauthorized("mfa_setup_required", "the role requires two-factor authentication, sign in again to set it up")
+)
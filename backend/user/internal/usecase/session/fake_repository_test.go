@@ -0,0 +1,29 @@
+package session
+
+import (
+	"crypto/ed25519"
+
+	jwtutil "github.com/yuhari7/backend_supervision/pkg/jwt"
+)
+
+// staticKeys signs with a single key, standing in for the keyring
+type staticKeys struct {
+	kid string
+	key ed25519.PrivateKey
+}
+
+func newStaticKeys() staticKeys {
+	_, key, _ := ed25519.GenerateKey(nil)
+	return staticKeys{kid: "test", key: key}
+}
+
+func (k staticKeys) SigningKey() (string, ed25519.PrivateKey, error) {
+	return k.kid, k.key, nil
+}
+
+func (k staticKeys) PublicKey(kid string) (ed25519.PublicKey, error) {
+	if kid != k.kid {
+		return nil, jwtutil.ErrUnknownKey
+	}
+	return k.key.Public().(ed25519.PublicKey), nil
+}
@@ -0,0 +1,23 @@
+package session
+
+import (
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	jwtutil "github.com/yuhari7/backend_supervision/pkg/jwt"
+)
+
+// Tokens are handed out on login and on every refresh
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// SessionUsecase issues, rotates and revokes refresh tokens, and checks that access tokens were not revoked since
+type SessionUsecase interface {
+	Start(user *entity.User) (Tokens, error)
+	Refresh(refreshToken string) (Tokens, error)
+	Logout(refreshToken string) error
+	LogoutAll(userID uint) error
+	Authenticate(accessToken string) (*jwtutil.CustomClaims, error)
+	Verify(userID uint, tokenVersion uint) error
+	Forget(userID uint)
+}
@@ -0,0 +1,41 @@
+package session
+
+import (
+	"errors"
+
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
+// Logout revokes the family of a refresh token, ending the session it belongs to.
+// Unknown tokens are ignored so logging out twice is not an error.
+func (s *sessionUsecase) Logout(refreshToken string) error {
+	token, err := s.tokenRepo.FindByHash(hashToken(refreshToken))
+	if errors.Is(err, repository.ErrTokenNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.tokenRepo.RevokeFamily(token.FamilyID)
+}
+
+// LogoutAll revokes every refresh token of a user and raises their token version in one transaction,
+// so the access tokens issued so far are refused too
+func (s *sessionUsecase) LogoutAll(userID uint) error {
+	err := s.transactor.Transaction(func(tx repository.Repositories) error {
+		user, err := tx.Users.FindByID(userID)
+		if err != nil {
+			return err
+		}
+		user.TokenVersion++
+		if err := tx.Users.Update(user); err != nil {
+			return err
+		}
+		return tx.RefreshTokens.RevokeUser(userID)
+	})
+	if err != nil {
+		return err
+	}
+	s.Forget(userID)
+	return nil
+}
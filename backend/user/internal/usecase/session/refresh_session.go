@@ -0,0 +1,90 @@
+package session
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
+// Refresh exchanges a refresh token for new tokens, the presented token cannot be used again.
+// Presenting a token that was already exchanged means it leaked, so the whole family is revoked.
+func (s *sessionUsecase) Refresh(refreshToken string) (Tokens, error) {
+	token, err := s.tokenRepo.FindByHash(hashToken(refreshToken))
+	if errors.Is(err, repository.ErrTokenNotFound) {
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	if token.RotatedAt != nil {
+		return Tokens{}, s.endFamily(token.FamilyID)
+	}
+	if !token.Usable(time.Now()) {
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+	// Rotations extend a session for at most sessionMaxAge after the login, a stolen family cannot live forever
+	if !time.Now().Before(token.FamilyStartedAt.Add(sessionMaxAge)) {
+		if err := s.tokenRepo.RevokeFamily(token.FamilyID); err != nil {
+			return Tokens{}, err
+		}
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return Tokens{}, err
+	}
+	// The user was deactivated or their role changed since the session started
+	if !user.IsActive || user.TokenVersion != token.TokenVersion {
+		if err := s.tokenRepo.RevokeFamily(token.FamilyID); err != nil {
+			return Tokens{}, err
+		}
+		return Tokens{}, ErrSessionRevoked
+	}
+
+	role, err := s.findRole(user)
+	if err != nil {
+		return Tokens{}, err
+	}
+	// The role started requiring two-factor authentication, the user sets it up on their next login
+	if role != nil && role.RequireMFA {
+		enrollment, err := s.mfaRepo.Find(user.ID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return Tokens{}, err
+		}
+		if enrollment == nil || !enrollment.Enabled() {
+			if err := s.tokenRepo.RevokeFamily(token.FamilyID); err != nil {
+				return Tokens{}, err
+			}
+			return Tokens{}, ErrMFASetupRequired
+		}
+	}
+
+	next, record, err := newRefreshToken(user, token.FamilyID, token.FamilyStartedAt)
+	if err != nil {
+		return Tokens{}, err
+	}
+	// Losing a race against another use of the same token is reuse as well
+	err = s.tokenRepo.Rotate(token, record)
+	if errors.Is(err, repository.ErrTokenNotUsable) {
+		return Tokens{}, s.endFamily(token.FamilyID)
+	}
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	return s.issue(user, role, next)
+}
+
+// endFamily revokes every token of a family after reuse was detected
+func (s *sessionUsecase) endFamily(familyID string) error {
+	if err := s.tokenRepo.RevokeFamily(familyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
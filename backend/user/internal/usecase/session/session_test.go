@@ -0,0 +1,282 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/testutil"
+)
+
+var (
+	budi = entity.User{ID: 1, Name: "Budi", Email: "budi@example.com", RoleID: 2, IsActive: true, TokenVersion: 1}
+	sari = entity.User{ID: 2, Name: "Sari", Email: "sari@example.com", RoleID: 2, IsActive: true, TokenVersion: 1}
+
+	contributor = entity.Role{ID: 2, Name: "contributor", Permissions: []entity.Permission{{Name: "articles:create"}, {Name: "articles:update"}}}
+)
+
+func newSessions() (SessionUsecase, *testutil.RefreshTokenRepository) {
+	sessions, tokens, _ := newSessionsWithUsers()
+	return sessions, tokens
+}
+
+func newSessionsWithUsers() (SessionUsecase, *testutil.RefreshTokenRepository, *testutil.UserRepository) {
+	tokens := &testutil.RefreshTokenRepository{}
+	users := testutil.NewUserRepository(budi, sari)
+	roles := testutil.NewRoleRepository(contributor)
+	return NewSessionUsecase(users, roles, testutil.NewMFARepository(), tokens, &testutil.Transactor{Users: users, RefreshTokens: tokens}, newStaticKeys(), time.Minute), tokens, users
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	sessions, tokens := newSessions()
+
+	login, err := sessions.Start(&budi)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tokens.Tokens[0].TokenHash == login.RefreshToken || tokens.Tokens[0].TokenHash != hashToken(login.RefreshToken) {
+		t.Errorf("got stored hash %q, want the SHA-256 of the token rather than the token", tokens.Tokens[0].TokenHash)
+	}
+
+	refreshed, err := sessions.Refresh(login.RefreshToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refreshed.AccessToken == "" || refreshed.RefreshToken == login.RefreshToken {
+		t.Fatalf("got tokens %+v, want a new access and refresh token", refreshed)
+	}
+	if tokens.Tokens[1].FamilyID != tokens.Tokens[0].FamilyID || tokens.Tokens[0].RotatedAt == nil {
+		t.Errorf("got tokens %+v and %+v, want the first rotated into the same family", tokens.Tokens[0], tokens.Tokens[1])
+	}
+
+	// The rotated token keeps working
+	if _, err := sessions.Refresh(refreshed.RefreshToken); err != nil {
+		t.Errorf("unexpected error refreshing the rotated token: %v", err)
+	}
+}
+
+func TestSessionMaxAge(t *testing.T) {
+	sessions, tokens := newSessions()
+
+	login, err := sessions.Start(&budi)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	started := time.Now().UTC().Add(-sessionMaxAge + 24*time.Hour)
+	tokens.Tokens[0].FamilyStartedAt = started
+
+	// Near the end of the family a rotation only lasts until the end
+	refreshed, err := sessions.Refresh(login.RefreshToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next := tokens.Tokens[1]; !next.FamilyStartedAt.Equal(started) || !next.ExpiresAt.Equal(started.Add(sessionMaxAge)) {
+		t.Errorf("got token %+v, want it to keep the family start and expire at the maximum age", next)
+	}
+
+	// Past the maximum age the family ends, even with a token that did not expire
+	tokens.Tokens[1].FamilyStartedAt = started.Add(-48 * time.Hour)
+	tokens.Tokens[1].ExpiresAt = time.Now().Add(time.Hour)
+	if _, err := sessions.Refresh(refreshed.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("got error %v, want ErrInvalidRefreshToken", err)
+	}
+	if tokens.Tokens[1].RevokedAt == nil {
+		t.Error("expected the family to be revoked")
+	}
+}
+
+func TestAccessTokenCarriesRolePermissions(t *testing.T) {
+	sessions, _ := newSessions()
+
+	login, err := sessions.Start(&budi)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claims, err := sessions.Authenticate(login.AccessToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if principal := claims.Principal(); principal.Role != "contributor" || !principal.Can("articles:create") || principal.Can("users:manage") {
+		t.Errorf("got principal %+v, want the contributor role with its permissions only", principal)
+	}
+}
+
+func TestReusedTokenRevokesFamily(t *testing.T) {
+	sessions, tokens := newSessions()
+	login, _ := sessions.Start(&budi)
+	other, _ := sessions.Start(&budi)
+
+	refreshed, err := sessions.Refresh(login.RefreshToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := sessions.Refresh(login.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("got error %v reusing a token, want %v", err, ErrRefreshTokenReused)
+	}
+
+	// The token handed out by the rotation is revoked as well, the other login is not affected
+	if _, err := sessions.Refresh(refreshed.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("got error %v for a token of the revoked family, want %v", err, ErrInvalidRefreshToken)
+	}
+	if _, err := sessions.Refresh(other.RefreshToken); err != nil {
+		t.Errorf("unexpected error refreshing another session: %v", err)
+	}
+	for _, token := range tokens.Tokens[:3] {
+		if token.FamilyID == tokens.Tokens[0].FamilyID && token.RevokedAt == nil {
+			t.Errorf("got token %+v not revoked, want the whole family revoked", token)
+		}
+	}
+}
+
+func TestInvalidRefreshTokens(t *testing.T) {
+	sessions, tokens := newSessions()
+	login, _ := sessions.Start(&budi)
+	tokens.Tokens[0].ExpiresAt = time.Now().Add(-time.Minute)
+
+	for name, token := range map[string]string{"unknown": "not-a-token", "expired": login.RefreshToken} {
+		if _, err := sessions.Refresh(token); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("got error %v for an %s token, want %v", err, name, ErrInvalidRefreshToken)
+		}
+	}
+}
+
+func TestLogout(t *testing.T) {
+	sessions, _ := newSessions()
+	login, _ := sessions.Start(&budi)
+	other, _ := sessions.Start(&budi)
+
+	// Logging out twice or with an unknown token is not an error
+	for _, token := range []string{login.RefreshToken, login.RefreshToken, "not-a-token"} {
+		if err := sessions.Logout(token); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := sessions.Refresh(login.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("got error %v after logout, want %v", err, ErrInvalidRefreshToken)
+	}
+	if _, err := sessions.Refresh(other.RefreshToken); err != nil {
+		t.Errorf("unexpected error refreshing another session: %v", err)
+	}
+}
+
+func TestLogoutAll(t *testing.T) {
+	sessions, _, users := newSessionsWithUsers()
+	first, _ := sessions.Start(&budi)
+	second, _ := sessions.Start(&budi)
+	others, _ := sessions.Start(&sari)
+
+	if err := sessions.LogoutAll(budi.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, token := range []string{first.RefreshToken, second.RefreshToken} {
+		if _, err := sessions.Refresh(token); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("got error %v after logging out everywhere, want %v", err, ErrInvalidRefreshToken)
+		}
+	}
+	if _, err := sessions.Authenticate(first.AccessToken); !errors.Is(err, ErrSessionRevoked) {
+		t.Errorf("got error %v for an access token issued before logging out everywhere, want %v", err, ErrSessionRevoked)
+	}
+	if users.Users[budi.ID].TokenVersion != budi.TokenVersion+1 {
+		t.Errorf("got token version %d, want %d", users.Users[budi.ID].TokenVersion, budi.TokenVersion+1)
+	}
+	if _, err := sessions.Refresh(others.RefreshToken); err != nil {
+		t.Errorf("unexpected error refreshing a session of another user: %v", err)
+	}
+}
+
+func TestFailedLogoutAllKeepsTokenVersion(t *testing.T) {
+	sessions, tokens, users := newSessionsWithUsers()
+	login, _ := sessions.Start(&budi)
+
+	tokens.RevokeErr = errors.New("database is down")
+	if err := sessions.LogoutAll(budi.ID); !errors.Is(err, tokens.RevokeErr) {
+		t.Fatalf("got error %v, want %v", err, tokens.RevokeErr)
+	}
+	if users.Users[budi.ID].TokenVersion != budi.TokenVersion {
+		t.Errorf("got token version %d, want %d kept when revoking the refresh tokens failed", users.Users[budi.ID].TokenVersion, budi.TokenVersion)
+	}
+	if _, err := sessions.Authenticate(login.AccessToken); err != nil {
+		t.Errorf("unexpected error for the access token after a failed logout: %v", err)
+	}
+}
+
+func TestRaisedTokenVersionEndsSessions(t *testing.T) {
+	sessions, _, users := newSessionsWithUsers()
+	login, _ := sessions.Start(&budi)
+	if claims, err := sessions.Authenticate(login.AccessToken); err != nil || claims.UserID != budi.ID || claims.TokenVersion != budi.TokenVersion {
+		t.Fatalf("got claims %+v and error %v, want the access token of user %d", claims, err, budi.ID)
+	}
+
+	// The role change is only noticed once the cached version expires or is forgotten
+	changed := budi
+	changed.RoleID = 1
+	changed.TokenVersion++
+	users.Users[budi.ID] = changed
+	if err := sessions.Verify(budi.ID, budi.TokenVersion); err != nil {
+		t.Errorf("got error %v within the cache TTL, want the cached version used", err)
+	}
+	sessions.Forget(budi.ID)
+	if _, err := sessions.Authenticate(login.AccessToken); !errors.Is(err, ErrSessionRevoked) {
+		t.Errorf("got error %v for an old token version, want %v", err, ErrSessionRevoked)
+	}
+	if err := sessions.Verify(budi.ID, changed.TokenVersion); err != nil {
+		t.Errorf("unexpected error for the current token version: %v", err)
+	}
+
+	// Refreshing does not hand out tokens with the old role
+	if _, err := sessions.Refresh(login.RefreshToken); !errors.Is(err, ErrSessionRevoked) {
+		t.Errorf("got error %v refreshing after a role change, want %v", err, ErrSessionRevoked)
+	}
+}
+
+func TestInactiveAndDeletedUsersAreRefused(t *testing.T) {
+	sessions, _, users := newSessionsWithUsers()
+	login, _ := sessions.Start(&budi)
+
+	deactivated := budi
+	deactivated.IsActive = false
+	users.Users[budi.ID] = deactivated
+	delete(users.Users, sari.ID)
+
+	if _, err := sessions.Refresh(login.RefreshToken); !errors.Is(err, ErrSessionRevoked) {
+		t.Errorf("got error %v refreshing for an inactive user, want %v", err, ErrSessionRevoked)
+	}
+	for _, user := range []entity.User{budi, sari} {
+		if err := sessions.Verify(user.ID, user.TokenVersion); !errors.Is(err, ErrSessionRevoked) {
+			t.Errorf("got error %v for user %d, want %v", err, user.ID, ErrSessionRevoked)
+		}
+	}
+
+	// A revoked session stays ended after the user is activated again
+	users.Users[budi.ID] = budi
+	if _, err := sessions.Refresh(login.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("got error %v after activating again, want %v", err, ErrInvalidRefreshToken)
+	}
+}
+
+func TestRefreshRequiresMFAOfRole(t *testing.T) {
+	tokens := &testutil.RefreshTokenRepository{}
+	users := testutil.NewUserRepository(budi, sari)
+	roles := testutil.NewRoleRepository(contributor)
+	now := time.Now()
+	mfa := testutil.NewMFARepository(entity.UserMFA{UserID: sari.ID, EnabledAt: &now})
+	sessions := NewSessionUsecase(users, roles, mfa, tokens, &testutil.Transactor{Users: users, RefreshTokens: tokens}, newStaticKeys(), time.Minute)
+
+	budiLogin, _ := sessions.Start(&budi)
+	sariLogin, _ := sessions.Start(&sari)
+
+	required := contributor
+	required.RequireMFA = true
+	roles.Roles[contributor.ID] = required
+
+	if _, err := sessions.Refresh(budiLogin.RefreshToken); !errors.Is(err, ErrMFASetupRequired) {
+		t.Fatalf("got error %v, want ErrMFASetupRequired", err)
+	}
+	if tokens.Tokens[0].RevokedAt == nil {
+		t.Error("expected the session without two-factor authentication to be revoked")
+	}
+	if _, err := sessions.Refresh(sariLogin.RefreshToken); err != nil {
+		t.Errorf("unexpected error for the user with two-factor authentication: %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	jwtutil "github.com/yuhari7/backend_supervision/pkg/jwt"
+)
+
+const (
+	// refreshTokenTTL is how long a refresh token can be exchanged, every rotation starts a new period
+	refreshTokenTTL = 7 * 24 * time.Hour
+	// sessionMaxAge is how long a family can be rotated at all, after it the user has to log in again
+	sessionMaxAge = 30 * 24 * time.Hour
+)
+
+type sessionUsecase struct {
+	userRepo   repository.UserRepository
+	roleRepo   repository.RoleRepository
+	mfaRepo    repository.MFARepository
+	tokenRepo  repository.RefreshTokenRepository
+	transactor repository.Transactor
+	keys       jwtutil.Keys
+	cacheTTL   time.Duration
+
+	mu        sync.RWMutex
+	versions  map[uint]versionEntry
+	forgotten uint64 // counts Forget calls, so a lookup racing one does not cache a stale version
+}
+
+// NewSessionUsecase creates a new instance of SessionUsecase.
+// Token versions are cached for cacheTTL, which bounds how long another instance keeps accepting a revoked token.
+func NewSessionUsecase(users repository.UserRepository, roles repository.RoleRepository, mfa repository.MFARepository, tokens repository.RefreshTokenRepository, transactor repository.Transactor, keys jwtutil.Keys, cacheTTL time.Duration) SessionUsecase {
+	return &sessionUsecase{
+		userRepo:   users,
+		roleRepo:   roles,
+		mfaRepo:    mfa,
+		tokenRepo:  tokens,
+		transactor: transactor,
+		keys:       keys,
+		cacheTTL:   cacheTTL,
+		versions:   make(map[uint]versionEntry),
+	}
+}
+
+// Start issues the tokens of a new login, its refresh token starts a new family
+func (s *sessionUsecase) Start(user *entity.User) (Tokens, error) {
+	refreshToken, record, err := newRefreshToken(user, uuid.NewString(), time.Now().UTC())
+	if err != nil {
+		return Tokens{}, err
+	}
+	if err := s.tokenRepo.Create(record); err != nil {
+		return Tokens{}, err
+	}
+	role, err := s.findRole(user)
+	if err != nil {
+		return Tokens{}, err
+	}
+	return s.issue(user, role, refreshToken)
+}
+
+// issue pairs a refresh token with a new access token for user, carrying the name and permissions of their role
+// so other services can check them without asking this one
+func (s *sessionUsecase) issue(user *entity.User, role *entity.Role, refreshToken string) (Tokens, error) {
+	claims := jwtutil.CustomClaims{
+		UserID:       user.ID,
+		Email:        user.Email,
+		RoleID:       user.RoleID,
+		TokenVersion: user.TokenVersion,
+	}
+	if role != nil {
+		claims.Role = role.Name
+		claims.Permissions = role.PermissionNames()
+	}
+
+	accessToken, err := jwtutil.GenerateAccessToken(s.keys, claims)
+	if err != nil {
+		return Tokens{}, fmt.Errorf("failed to generate access token: %w", err)
+	}
+	return Tokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// findRole loads the role of user, nil when it does not exist anymore
+func (s *sessionUsecase) findRole(user *entity.User) (*entity.Role, error) {
+	role, err := s.roleRepo.FindByID(user.RoleID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	return role, err
+}
+
+// newRefreshToken creates a random opaque token and the record storing its hash.
+// The token expires refreshTokenTTL from now, or earlier when the family reaches sessionMaxAge.
+func newRefreshToken(user *entity.User, familyID string, familyStartedAt time.Time) (string, *entity.RefreshToken, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(key)
+
+	expiresAt := time.Now().UTC().Add(refreshTokenTTL)
+	if familyEnd := familyStartedAt.Add(sessionMaxAge); familyEnd.Before(expiresAt) {
+		expiresAt = familyEnd
+	}
+	return token, &entity.RefreshToken{
+		UserID:          user.ID,
+		FamilyID:        familyID,
+		FamilyStartedAt: familyStartedAt,
+		TokenHash:       hashToken(token),
+		TokenVersion:    user.TokenVersion,
+		ExpiresAt:       expiresAt,
+	}, nil
+}
+
+// hashToken returns the hex SHA-256 of a token, the tokens are random so a fast hash is enough
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
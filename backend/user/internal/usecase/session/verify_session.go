@@ -0,0 +1,80 @@
+package session
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	jwtutil "github.com/yuhari7/backend_supervision/pkg/jwt"
+	"github.com/yuhari7/backend_supervision/shared/auth"
+)
+
+// versionEntry caches what Verify needs to know about a user
+type versionEntry struct {
+	tokenVersion uint
+	active       bool // false for deactivated and deleted users
+	expiresAt    time.Time
+}
+
+// Authenticate checks the signature and expiry of an access token and that its session was not revoked since
+func (s *sessionUsecase) Authenticate(accessToken string) (*jwtutil.CustomClaims, error) {
+	claims, err := jwtutil.ParseAccessToken(s.keys, accessToken)
+	if err != nil {
+		return nil, auth.ErrInvalidToken.Wrap(err)
+	}
+	if err := s.Verify(claims.UserID, claims.TokenVersion); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Verify checks that an access token issued with tokenVersion was not revoked since,
+// by deactivating or deleting the user or by changing their role
+func (s *sessionUsecase) Verify(userID uint, tokenVersion uint) error {
+	entry, err := s.lookup(userID)
+	if err != nil {
+		return err
+	}
+	if !entry.active || entry.tokenVersion != tokenVersion {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+// Forget drops the cached token version of a user, the next Verify reads it again.
+// Call it after changing a user so the change takes effect at once on this instance.
+func (s *sessionUsecase) Forget(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.versions, userID)
+	s.forgotten++
+}
+
+// lookup returns the cached entry of a user, loading it when missing or expired
+func (s *sessionUsecase) lookup(userID uint) (versionEntry, error) {
+	s.mu.RLock()
+	entry, ok := s.versions[userID]
+	forgotten := s.forgotten
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry, nil
+	}
+
+	entry = versionEntry{expiresAt: time.Now().Add(s.cacheTTL)}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return versionEntry{}, err
+	}
+	if err == nil {
+		entry.tokenVersion = user.TokenVersion
+		entry.active = user.IsActive
+	}
+
+	// A user forgotten while loading may have been read before the change, it is not cached
+	s.mu.Lock()
+	if s.forgotten == forgotten {
+		s.versions[userID] = entry
+	}
+	s.mu.Unlock()
+	return entry, nil
+}
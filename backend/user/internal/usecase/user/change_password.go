@@ -0,0 +1,33 @@
+package user
+
+import (
+	"errors"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ChangePassword replaces the password of a user who knows the current one.
+// Every session issued so far ends, the caller starts a new one for the user.
+func (u *userUsecase) ChangePassword(id uint, input dto.ChangePasswordRequest) (*entity.User, error) {
+	user, err := u.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPassword(user, input.CurrentPassword); err != nil {
+		return nil, err
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+	user.Password = string(hashed)
+	user.TokenVersion++
+
+	if err := u.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -1,5 +1,22 @@
 package user
 
+import (
+	"errors"
+
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
 func (u *userUsecase) DeleteUser(id uint) error {
-	return u.userRepo.Delete(id)
+	user, err := u.findUser(id)
+	if err != nil {
+		return err
+	}
+
+	err = u.saveWithEvent(user, EventUserDeleted, 0, func(tx repository.UserRepository) error {
+		return tx.Delete(id)
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrUserNotFound
+	}
+	return err
 }
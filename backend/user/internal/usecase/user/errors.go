@@ -0,0 +1,15 @@
+package user
+
+import "github.com/yuhari7/backend_supervision/shared/apperror"
+
+// Domain errors returned by the user usecases
+var (
+	ErrUserNotFound           = apperror.NotFound("user_not_found", "user not found")
+	ErrEmailAlreadyRegistered = apperror.Conflict("email_already_registered", "email already registered")
+	ErrInvalidCredentials     = apperror.Unauthorized("invalid_credentials", "invalid credentials")
+	ErrUserInactive           = apperror.Forbidden("user_inactive", "user is inactive")
+	ErrEmailNotVerified       = apperror.Forbidden("email_not_verified", "email is not verified yet")
+	ErrBatchTooLarge          = apperror.Validation("batch_too_large", "too many users requested at once", nil)
+	ErrInvalidCurrentPassword = apperror.Validation("invalid_current_password", "current password is incorrect", nil)
+	ErrInvalidAvatarURL       = apperror.Validation("invalid_avatar_url", "avatar URL must be an absolute http or https URL", nil)
+)
@@ -0,0 +1,43 @@
+package user
+
+import (
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	"github.com/yuhari7/backend_supervision/shared/outbox"
+)
+
+// User domain events, written to the outbox together with the change
+const (
+	EventUserRegistered  = "user.registered"
+	EventUserActivated   = "user.activated"
+	EventUserDeactivated = "user.deactivated"
+	EventUserRoleChanged = "user.role_changed"
+	EventUserDeleted     = "user.deleted"
+)
+
+// newUserEvent builds an event about user, previousRoleID is only set for role changes
+func newUserEvent(eventType string, user entity.User, previousRoleID uint) (outbox.Event, error) {
+	return outbox.NewEvent(eventType, "user", user.ID, dto.UserEvent{
+		ID:             user.ID,
+		Name:           user.Name,
+		Email:          user.Email,
+		RoleID:         user.RoleID,
+		IsActive:       user.IsActive,
+		PreviousRoleID: previousRoleID,
+	})
+}
+
+// saveWithEvent runs save and writes the event about the saved user in one transaction, an empty eventType writes none
+func (u *userUsecase) saveWithEvent(user *entity.User, eventType string, previousRoleID uint, save func(repository.UserRepository) error) error {
+	return u.transactor.Transaction(func(tx repository.Repositories) error {
+		if err := save(tx.Users); err != nil || eventType == "" {
+			return err
+		}
+		event, err := newUserEvent(eventType, *user, previousRoleID)
+		if err != nil {
+			return err
+		}
+		return tx.Users.AddEvents(event)
+	})
+}
@@ -0,0 +1,101 @@
+package user
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/testutil"
+)
+
+func TestUserEvents(t *testing.T) {
+	repo := testutil.NewUserRepository(entity.User{ID: 1, Name: "Budi", Email: "budi@example.com", RoleID: 2, IsActive: true})
+	u := newUserUsecase(repo)
+
+	// Saving the same role or active state again is not announced
+	steps := []func() error{
+		func() error {
+			_, err := u.UpdateUser(1, dto.UpdateUserRequest{Name: "Budi", Email: "budi@example.com", RoleID: 2})
+			return err
+		},
+		func() error {
+			_, err := u.UpdateUser(1, dto.UpdateUserRequest{Name: "Budi", Email: "budi@example.com", RoleID: 1})
+			return err
+		},
+		func() error { return u.ToggleUserActive(1, true) },
+		func() error { return u.ToggleUserActive(1, false) },
+		func() error { return u.ToggleUserActive(1, true) },
+		func() error { return u.DeleteUser(1) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var types []string
+	for _, event := range repo.Events {
+		types = append(types, event.Type)
+	}
+	want := []string{EventUserRoleChanged, EventUserDeactivated, EventUserActivated, EventUserDeleted}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("got events %v, want %v", types, want)
+	}
+
+	var payload dto.UserEvent
+	if err := json.Unmarshal(repo.Events[0].Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.RoleID != 1 || payload.PreviousRoleID != 2 || repo.Events[0].AggregateID != "1" {
+		t.Errorf("got payload %+v for aggregate %s, want user 1 moved from role 2 to 1", payload, repo.Events[0].AggregateID)
+	}
+}
+
+func TestFailedEventRollsBackUpdate(t *testing.T) {
+	repo := testutil.NewUserRepository(entity.User{ID: 1, Name: "Budi", Email: "budi@example.com", RoleID: 2, IsActive: true})
+	repo.EventsErr = errDatabase
+
+	if err := newUserUsecase(repo).ToggleUserActive(1, false); err == nil {
+		t.Fatal("expected an error when the event cannot be written")
+	}
+	if !repo.Users[1].IsActive || len(repo.Events) != 0 {
+		t.Errorf("got active %v with %d events, want the update rolled back", repo.Users[1].IsActive, len(repo.Events))
+	}
+}
+
+func TestRevokingChangesRaiseTokenVersion(t *testing.T) {
+	repo := testutil.NewUserRepository(entity.User{ID: 1, Name: "Budi", Email: "budi@example.com", RoleID: 2, IsActive: true, TokenVersion: 1})
+	u := newUserUsecase(repo)
+
+	// Renaming and activating keep the sessions, a role change, a new password and a deactivation end them
+	steps := []struct {
+		run  func() error
+		want uint
+	}{
+		{func() error {
+			_, err := u.UpdateUser(1, dto.UpdateUserRequest{Name: "Budi S", Email: "budi@example.com", RoleID: 2})
+			return err
+		}, 1},
+		{func() error {
+			_, err := u.UpdateUser(1, dto.UpdateUserRequest{Name: "Budi S", Email: "budi@example.com", RoleID: 1})
+			return err
+		}, 2},
+		{func() error {
+			_, err := u.UpdateUser(1, dto.UpdateUserRequest{Name: "Budi S", Email: "budi@example.com", RoleID: 1, Password: "newsecret"})
+			return err
+		}, 3},
+		{func() error { return u.ToggleUserActive(1, false) }, 4},
+		{func() error { return u.ToggleUserActive(1, false) }, 4},
+		{func() error { return u.ToggleUserActive(1, true) }, 4},
+	}
+	for i, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := repo.Users[1].TokenVersion; got != step.want {
+			t.Errorf("step %d: got token version %d, want %d", i, got, step.want)
+		}
+	}
+}
@@ -1,7 +1,24 @@
 package user
 
-import "github.com/yuhari7/backend_supervision/internal/entity"
+import (
+	"errors"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
 
 func (u *userUsecase) GetUserByID(id uint) (*entity.User, error) {
-	return u.userRepo.FindByID(id)
+	return u.findUser(id)
+}
+
+// findUser loads a user, reporting a missing row as ErrUserNotFound
+func (u *userUsecase) findUser(id uint) (*entity.User, error) {
+	user, err := u.userRepo.FindByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
 }
@@ -0,0 +1,25 @@
+package user
+
+import "github.com/yuhari7/backend_supervision/internal/entity"
+
+// maxBatchSize limits how many users can be looked up at once
+const maxBatchSize = 500
+
+// GetUsersByIDs returns the users with the given IDs, unknown IDs are left out
+func (u *userUsecase) GetUsersByIDs(ids []uint) ([]entity.User, error) {
+	if len(ids) > maxBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+
+	// Drop duplicate IDs so each user is only returned once
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	return u.userRepo.FindByIDs(unique)
+}
@@ -7,10 +7,15 @@ import (
 
 type UserUsecase interface {
 	Register(input dto.CreateUserRequest) (*entity.User, error)
+	CreateUser(input dto.CreateUserRequest) (*entity.User, error)
 	Login(input dto.LoginRequest) (*entity.User, error)
 	GetUserByID(id uint) (*entity.User, error)
+	GetUsersByIDs(ids []uint) ([]entity.User, error)
+	ListUsersByRole(roleID uint, limit, offset int) ([]entity.User, error)
 	GetAllUsers(pagination dto.PaginationQuery) (*dto.PaginatedResponse[dto.UserResponse], error)
 	UpdateUser(id uint, input dto.UpdateUserRequest) (*entity.User, error)
 	ToggleUserActive(id uint, active bool) error
 	DeleteUser(id uint) error
+	UpdateProfile(id uint, input dto.UpdateProfileRequest) (*entity.User, error)
+	ChangePassword(id uint, input dto.ChangePasswordRequest) (*entity.User, error)
 }
@@ -0,0 +1,25 @@
+package user
+
+import "github.com/yuhari7/backend_supervision/internal/entity"
+
+// defaultPageSize is used when a page size is missing
+const defaultPageSize = 50
+
+// PageSize normalizes a requested page size to the range accepted by ListUsersByRole
+func PageSize(size int) int {
+	if size <= 0 {
+		return defaultPageSize
+	}
+	if size > maxBatchSize {
+		return maxBatchSize
+	}
+	return size
+}
+
+// ListUsersByRole returns a page of the users with the given role, ordered by ID
+func (u *userUsecase) ListUsersByRole(roleID uint, limit, offset int) ([]entity.User, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	return u.userRepo.FindByRole(roleID, PageSize(limit), offset)
+}
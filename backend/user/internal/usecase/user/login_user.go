@@ -5,6 +5,7 @@ import (
 
 	"github.com/yuhari7/backend_supervision/internal/common/dto"
 	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -15,20 +16,24 @@ type LoginInput struct {
 
 func (u *userUsecase) Login(input dto.LoginRequest) (*entity.User, error) {
 	user, err := u.userRepo.FindByEmail(input.Email)
+	// Unknown email and wrong password share the same error so accounts cannot be enumerated
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrInvalidCredentials
+	}
 	if err != nil {
-		return nil, errors.New("failed to find user")
+		return nil, err
 	}
-	if user == nil {
-		return nil, errors.New("user not found")
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password))
+	if err != nil {
+		return nil, ErrInvalidCredentials
 	}
 
 	if !user.IsActive {
-		return nil, errors.New("user is inactive")
+		return nil, ErrUserInactive
 	}
-
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password))
-	if err != nil {
-		return nil, errors.New("invalid credentials")
+	if user.EmailVerifiedAt == nil {
+		return nil, ErrEmailNotVerified
 	}
 
 	return user, nil
@@ -0,0 +1,105 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/testutil"
+)
+
+const missingID uint = 404
+
+var errDatabase = errors.New("connection refused")
+
+func TestMissingUser(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(u UserUsecase) error
+	}{
+		{"GetUserByID", func(u UserUsecase) error {
+			_, err := u.GetUserByID(missingID)
+			return err
+		}},
+		{"UpdateUser", func(u UserUsecase) error {
+			_, err := u.UpdateUser(missingID, dto.UpdateUserRequest{Name: "Budi", Email: "budi@example.com", RoleID: 2})
+			return err
+		}},
+		{"ToggleUserActive", func(u UserUsecase) error {
+			return u.ToggleUserActive(missingID, false)
+		}},
+		{"DeleteUser", func(u UserUsecase) error {
+			return u.DeleteUser(missingID)
+		}},
+		{"UpdateProfile", func(u UserUsecase) error {
+			_, err := u.UpdateProfile(missingID, dto.UpdateProfileRequest{})
+			return err
+		}},
+		{"ChangePassword", func(u UserUsecase) error {
+			_, err := u.ChangePassword(missingID, dto.ChangePasswordRequest{CurrentPassword: "secret", NewPassword: "newsecret"})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(newUserUsecase(testutil.NewUserRepository()))
+			if !errors.Is(err, ErrUserNotFound) {
+				t.Fatalf("got error %v, want %v", err, ErrUserNotFound)
+			}
+		})
+
+		t.Run(tt.name+"/database error", func(t *testing.T) {
+			repo := testutil.NewUserRepository()
+			repo.Err = errDatabase
+
+			err := tt.call(newUserUsecase(repo))
+			if !errors.Is(err, errDatabase) {
+				t.Fatalf("got error %v, want %v", err, errDatabase)
+			}
+		})
+	}
+}
+
+func TestMissingEmail(t *testing.T) {
+	existing := entity.User{ID: 1, Name: "Siti", Email: "siti@example.com", RoleID: 2, IsActive: true}
+
+	tests := []struct {
+		name    string
+		call    func(u UserUsecase) error
+		wantErr error
+	}{
+		{"Login with unknown email", func(u UserUsecase) error {
+			_, err := u.Login(dto.LoginRequest{Email: "unknown@example.com", Password: "secret"})
+			return err
+		}, ErrInvalidCredentials},
+		{"Register with new email", func(u UserUsecase) error {
+			_, err := u.Register(dto.CreateUserRequest{Name: "Budi", Email: "budi@example.com", Password: "secret", RoleID: 2})
+			return err
+		}, nil},
+		{"Register with taken email", func(u UserUsecase) error {
+			_, err := u.Register(dto.CreateUserRequest{Name: "Siti", Email: existing.Email, Password: "secret", RoleID: 2})
+			return err
+		}, ErrEmailAlreadyRegistered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(newUserUsecase(testutil.NewUserRepository(existing)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+
+		t.Run(tt.name+"/database error", func(t *testing.T) {
+			repo := testutil.NewUserRepository(existing)
+			repo.Err = errDatabase
+
+			err := tt.call(newUserUsecase(repo))
+			if !errors.Is(err, errDatabase) {
+				t.Fatalf("got error %v, want %v", err, errDatabase)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/testutil"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newProfileUsers(t *testing.T) *testutil.UserRepository {
+	hashed, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifiedAt := time.Now()
+	return testutil.NewUserRepository(
+		entity.User{ID: 1, Name: "Budi", Email: "budi@example.com", Password: string(hashed), RoleID: 2, IsActive: true, TokenVersion: 1, EmailVerifiedAt: &verifiedAt},
+		entity.User{ID: 2, Name: "Sari", Email: "sari@example.com", Password: string(hashed), RoleID: 2, IsActive: true, TokenVersion: 1, EmailVerifiedAt: &verifiedAt},
+	)
+}
+
+func stringPtr(s string) *string { return &s }
+
+func TestUpdateProfile(t *testing.T) {
+	repo := newProfileUsers(t)
+	u := newUserUsecase(repo)
+
+	user, err := u.UpdateProfile(1, dto.UpdateProfileRequest{Name: stringPtr("Budi Santoso"), AvatarURL: stringPtr("https://cdn.example.com/budi.png")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.Name != "Budi Santoso" || user.AvatarURL() != "https://cdn.example.com/budi.png" || user.Email != "budi@example.com" {
+		t.Errorf("got user %+v, want a new name and avatar with the email unchanged", user)
+	}
+	if stored := repo.Users[1]; stored.RoleID != 2 || !stored.IsActive || stored.TokenVersion != 1 {
+		t.Errorf("got stored user %+v, want role, active state and sessions untouched", stored)
+	}
+
+	// An empty avatar goes back to Gravatar
+	user, err = u.UpdateProfile(1, dto.UpdateProfileRequest{AvatarURL: stringPtr("")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.Avatar != "" || user.AvatarURL() == "" {
+		t.Errorf("got avatar %q, want the Gravatar fallback", user.AvatarURL())
+	}
+
+	if _, err := u.UpdateProfile(1, dto.UpdateProfileRequest{AvatarURL: stringPtr("javascript:alert(1)")}); !errors.Is(err, ErrInvalidAvatarURL) {
+		t.Errorf("got error %v, want ErrInvalidAvatarURL", err)
+	}
+}
+
+func TestUpdateProfileEmail(t *testing.T) {
+	repo := newProfileUsers(t)
+	u := newUserUsecase(repo)
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		want     error
+	}{
+		{"without the current password", "budi@example.org", "", ErrInvalidCurrentPassword},
+		{"with a wrong password", "budi@example.org", "guess", ErrInvalidCurrentPassword},
+		{"taken by another user", "sari@example.com", "secret", ErrEmailAlreadyRegistered},
+		{"with the current password", "budi@example.org", "secret", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := u.UpdateProfile(1, dto.UpdateProfileRequest{Email: stringPtr(tt.email), CurrentPassword: tt.password})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+		})
+	}
+	stored := repo.Users[1]
+	if stored.Email != "budi@example.com" || stored.EmailVerifiedAt == nil || stored.PendingEmail == nil || *stored.PendingEmail != "budi@example.org" {
+		t.Errorf("got user %+v, want the verified email kept and budi@example.org pending", stored)
+	}
+
+	// Saving the current email again needs no password and cancels the pending one
+	if _, err := u.UpdateProfile(1, dto.UpdateProfileRequest{Email: stringPtr("budi@example.com")}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if stored := repo.Users[1]; stored.PendingEmail != nil {
+		t.Errorf("got pending email %q, want none", *stored.PendingEmail)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	repo := newProfileUsers(t)
+	u := newUserUsecase(repo)
+
+	if _, err := u.ChangePassword(1, dto.ChangePasswordRequest{CurrentPassword: "guess", NewPassword: "newsecret"}); !errors.Is(err, ErrInvalidCurrentPassword) {
+		t.Fatalf("got error %v, want ErrInvalidCurrentPassword", err)
+	}
+
+	user, err := u.ChangePassword(1, dto.ChangePasswordRequest{CurrentPassword: "secret", NewPassword: "newsecret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.TokenVersion != 2 {
+		t.Errorf("got token version %d, want 2 so earlier sessions end", user.TokenVersion)
+	}
+	if _, err := u.Login(dto.LoginRequest{Email: "budi@example.com", Password: "newsecret"}); err != nil {
+		t.Errorf("unexpected error logging in with the new password: %v", err)
+	}
+	if _, err := u.Login(dto.LoginRequest{Email: "budi@example.com", Password: "secret"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("got error %v logging in with the old password, want ErrInvalidCredentials", err)
+	}
+}
@@ -0,0 +1,45 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/testutil"
+)
+
+// newUserUsecase runs the transactions of the usecase on repo
+func newUserUsecase(repo *testutil.UserRepository) UserUsecase {
+	return NewUserUsecase(repo, &testutil.Transactor{Users: repo})
+}
+
+func TestSelfRegisteredUsersVerifyBeforeLogin(t *testing.T) {
+	u := newUserUsecase(testutil.NewUserRepository())
+
+	registered, err := u.Register(dto.CreateUserRequest{Name: "Budi", Email: "budi@example.com", Password: "secret", RoleID: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if registered.EmailVerifiedAt != nil {
+		t.Errorf("got verified at %v, want a self-registered email unverified", registered.EmailVerifiedAt)
+	}
+	if _, err := u.Login(dto.LoginRequest{Email: "budi@example.com", Password: "secret"}); !errors.Is(err, ErrEmailNotVerified) {
+		t.Errorf("got error %v, want ErrEmailNotVerified", err)
+	}
+
+	// A wrong password is reported as such, so the verification state is not revealed to strangers
+	if _, err := u.Login(dto.LoginRequest{Email: "budi@example.com", Password: "guess"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("got error %v, want ErrInvalidCredentials", err)
+	}
+
+	created, err := u.CreateUser(dto.CreateUserRequest{Name: "Sari", Email: "sari@example.com", Password: "secret", RoleID: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.EmailVerifiedAt == nil {
+		t.Error("got an unverified email, want accounts created for someone else verified")
+	}
+	if _, err := u.Login(dto.LoginRequest{Email: "sari@example.com", Password: "secret"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
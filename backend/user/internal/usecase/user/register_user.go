@@ -2,6 +2,7 @@ package user
 
 import (
 	"errors"
+	"time"
 
 	"github.com/yuhari7/backend_supervision/internal/common/dto"
 	"github.com/yuhari7/backend_supervision/internal/entity"
@@ -17,18 +18,33 @@ type RegisterInput struct {
 }
 
 type userUsecase struct {
-	userRepo repository.UserRepository
+	userRepo   repository.UserRepository
+	transactor repository.Transactor
 }
 
-func NewUserUsecase(repo repository.UserRepository) UserUsecase {
-	return &userUsecase{userRepo: repo}
+func NewUserUsecase(repo repository.UserRepository, transactor repository.Transactor) UserUsecase {
+	return &userUsecase{userRepo: repo, transactor: transactor}
 }
 
+// Register creates a self-registered account, it cannot log in until its email is verified
 func (u *userUsecase) Register(input dto.CreateUserRequest) (*entity.User, error) {
+	return u.create(input, nil)
+}
+
+// CreateUser creates an account for someone else, its email counts as verified
+func (u *userUsecase) CreateUser(input dto.CreateUserRequest) (*entity.User, error) {
+	now := time.Now().UTC()
+	return u.create(input, &now)
+}
+
+func (u *userUsecase) create(input dto.CreateUserRequest, emailVerifiedAt *time.Time) (*entity.User, error) {
 	// Cek email
-	existing, _ := u.userRepo.FindByEmail(input.Email)
-	if existing != nil {
-		return nil, errors.New("email already registered")
+	_, err := u.userRepo.FindByEmail(input.Email)
+	if err == nil {
+		return nil, ErrEmailAlreadyRegistered
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
 	}
 
 	hashed, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
@@ -37,13 +53,16 @@ func (u *userUsecase) Register(input dto.CreateUserRequest) (*entity.User, error
 	}
 
 	user := &entity.User{
-		Name:     input.Name,
-		Email:    input.Email,
-		Password: string(hashed),
-		RoleID:   input.RoleID,
+		Name:            input.Name,
+		Email:           input.Email,
+		Password:        string(hashed),
+		RoleID:          input.RoleID,
+		EmailVerifiedAt: emailVerifiedAt,
 	}
 
-	err = u.userRepo.Create(user)
+	err = u.saveWithEvent(user, EventUserRegistered, 0, func(tx repository.UserRepository) error {
+		return tx.Create(user)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -1,16 +1,26 @@
 package user
 
-import "errors"
+import "github.com/yuhari7/backend_supervision/internal/repository"
 
 func (u *userUsecase) ToggleUserActive(id uint, active bool) error {
-	user, err := u.userRepo.FindByID(id)
+	user, err := u.findUser(id)
 	if err != nil {
 		return err
 	}
-	if user == nil {
-		return errors.New("user not found")
+
+	// Only an actual change is announced, deactivating also ends every session of the user
+	eventType := ""
+	if active != user.IsActive {
+		eventType = EventUserDeactivated
+		if active {
+			eventType = EventUserActivated
+		} else {
+			user.TokenVersion++
+		}
 	}
 
 	user.IsActive = active
-	return u.userRepo.Update(user)
+	return u.saveWithEvent(user, eventType, 0, func(tx repository.UserRepository) error {
+		return tx.Update(user)
+	})
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/yuhari7/backend_supervision/internal/common/dto"
 	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -17,24 +18,37 @@ type UpdateInput struct {
 }
 
 func (u *userUsecase) UpdateUser(id uint, input dto.UpdateUserRequest) (*entity.User, error) {
-	user, err := u.userRepo.FindByID(id)
+	user, err := u.findUser(id)
 	if err != nil {
 		return nil, err
 	}
 
+	// A new role is announced so other services can update what the user may do,
+	// the tokens carrying the old role stop working
+	previousRoleID := user.RoleID
+	eventType := ""
+	if input.RoleID != previousRoleID {
+		eventType = EventUserRoleChanged
+		user.TokenVersion++
+	}
+
 	user.Name = input.Name
 	user.Email = input.Email
 	user.RoleID = input.RoleID
 
+	// A password set by an admin ends every session, like changing or resetting it
 	if strings.TrimSpace(input.Password) != "" {
 		hashed, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 		if err != nil {
 			return nil, errors.New("failed to hash password")
 		}
 		user.Password = string(hashed)
+		user.TokenVersion++
 	}
 
-	err = u.userRepo.Update(user)
+	err = u.saveWithEvent(user, eventType, previousRoleID, func(tx repository.UserRepository) error {
+		return tx.Update(user)
+	})
 	if err != nil {
 		return nil, err
 	}
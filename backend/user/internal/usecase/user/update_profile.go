@@ -0,0 +1,76 @@
+package user
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UpdateProfile lets a user change their own name, email and avatar.
+// A new email is kept pending and only replaces the current one once it is verified.
+// The role and active state are left alone, they are only managed through UpdateUser and ToggleUserActive.
+func (u *userUsecase) UpdateProfile(id uint, input dto.UpdateProfileRequest) (*entity.User, error) {
+	user, err := u.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		user.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.AvatarURL != nil {
+		avatar := strings.TrimSpace(*input.AvatarURL)
+		if avatar != "" {
+			if err := checkAvatarURL(avatar); err != nil {
+				return nil, err
+			}
+		}
+		user.Avatar = avatar
+	}
+	if input.Email != nil && strings.EqualFold(*input.Email, user.Email) {
+		// Going back to the current email cancels a change that was not verified yet
+		user.PendingEmail = nil
+	}
+	if input.Email != nil && !strings.EqualFold(*input.Email, user.Email) {
+		// Whoever holds a stolen access token must not be able to take over the account through its email
+		if err := checkPassword(user, input.CurrentPassword); err != nil {
+			return nil, err
+		}
+		existing, err := u.userRepo.FindByEmail(*input.Email)
+		if err == nil && existing.ID != user.ID {
+			return nil, ErrEmailAlreadyRegistered
+		}
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		// The current email stays until the new one is verified, so a typo cannot lock the user out
+		email := *input.Email
+		user.PendingEmail = &email
+	}
+
+	if err := u.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// checkPassword compares password with the one of user
+func checkPassword(user *entity.User, password string) error {
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return ErrInvalidCurrentPassword
+	}
+	return nil
+}
+
+func checkAvatarURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidAvatarURL
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package verification
+
+import "github.com/yuhari7/backend_supervision/shared/apperror"
+
+// Domain errors returned by the verification usecases
+var (
+	ErrInvalidVerificationToken = apperror.Validation("invalid_verification_token", "invalid, used or expired email verification token", nil)
+	ErrEmailAlreadyRegistered   = apperror.Conflict("email_already_registered", "email already registered")
+	ErrResendLimited            = apperror.TooManyRequests("verification_resend_limited", "verification email was sent recently, try again later")
+)
@@ -0,0 +1,12 @@
+package verification
+
+import (
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+)
+
+type VerificationUsecase interface {
+	Send(user *entity.User, locale string) error
+	Verify(input dto.VerifyEmailRequest) (*entity.User, error)
+	Resend(input dto.ResendVerificationRequest, locale string) error
+}
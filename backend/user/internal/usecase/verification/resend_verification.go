@@ -0,0 +1,34 @@
+package verification
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+)
+
+// resendWindow is the period ResendLimit applies to
+const resendWindow = time.Hour
+
+// Resend mails a new verification link to an unverified active user.
+// Every email is rate limited the same way and unknown or verified emails succeed without a mail,
+// so the response does not tell who has an account.
+func (u *verificationUsecase) Resend(input dto.ResendVerificationRequest, locale string) error {
+	if !u.resends.Allow(strings.ToLower(strings.TrimSpace(input.Email)), u.now()) {
+		return ErrResendLimited.With("retry_after", int(u.resends.Cooldown().Seconds()))
+	}
+
+	user, err := u.userRepo.FindByEmail(input.Email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.IsActive || user.EmailVerifiedAt != nil {
+		return nil
+	}
+	return u.Send(user, locale)
+}
@@ -0,0 +1,114 @@
+package verification
+
+import (
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	"github.com/yuhari7/backend_supervision/pkg/mailer"
+	"github.com/yuhari7/backend_supervision/pkg/randtoken"
+	"github.com/yuhari7/backend_supervision/pkg/ratelimit"
+)
+
+// Config configures the verification links and how often they can be resent
+type Config struct {
+	// VerifyURL is the frontend page the link opens, the token is added as the token query parameter
+	VerifyURL string
+	// TokenTTL is how long a link can be used, 24 hours by default
+	TokenTTL time.Duration
+	// ResendCooldown is the least time between two resends to the same email, 1 minute by default
+	ResendCooldown time.Duration
+	// ResendLimit is how many resends an email gets per hour, 5 by default
+	ResendLimit int
+}
+
+type verificationUsecase struct {
+	userRepo  repository.UserRepository
+	tokenRepo repository.UserTokenRepository
+	mailer    mailer.Mailer
+	config    Config
+
+	now      func() time.Time
+	dispatch func(send func()) // runs mail delivery, in the background outside tests
+
+	resends *ratelimit.Limiter // per email
+}
+
+// NewVerificationUsecase creates a new instance of VerificationUsecase
+func NewVerificationUsecase(users repository.UserRepository, tokens repository.UserTokenRepository, m mailer.Mailer, config Config) VerificationUsecase {
+	if config.VerifyURL == "" {
+		config.VerifyURL = "http://localhost:3000/verify-email"
+	}
+	if config.TokenTTL <= 0 {
+		config.TokenTTL = 24 * time.Hour
+	}
+	if config.ResendCooldown <= 0 {
+		config.ResendCooldown = time.Minute
+	}
+	if config.ResendLimit <= 0 {
+		config.ResendLimit = 5
+	}
+	return &verificationUsecase{
+		userRepo:  users,
+		tokenRepo: tokens,
+		mailer:    m,
+		config:    config,
+		now:       func() time.Time { return time.Now().UTC() },
+		dispatch:  func(send func()) { go send() },
+		resends:   ratelimit.New(config.ResendLimit, resendWindow, config.ResendCooldown),
+	}
+}
+
+// Send mails a verification link for the pending email of user, or the current one when none is pending.
+// Earlier links stop working.
+func (u *verificationUsecase) Send(user *entity.User, locale string) error {
+	if err := u.tokenRepo.RevokeUser(user.ID, entity.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+
+	token, err := randtoken.New()
+	if err != nil {
+		return err
+	}
+	err = u.tokenRepo.Create(&entity.UserToken{
+		UserID:    user.ID,
+		Purpose:   entity.TokenPurposeEmailVerification,
+		TokenHash: randtoken.Hash(token),
+		ExpiresAt: u.now().Add(u.config.TokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	link, err := url.Parse(u.config.VerifyURL)
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	// A pending email is the one to verify, the link would prove nothing if it went to the current one
+	email := user.Email
+	if user.PendingEmail != nil {
+		email = *user.PendingEmail
+	}
+	msg, err := mailer.Render("email_verification", locale, email, map[string]interface{}{
+		"Name":       user.Name,
+		"Email":      email,
+		"URL":        link.String(),
+		"ValidHours": int(u.config.TokenTTL.Hours()),
+	})
+	if err != nil {
+		return err
+	}
+	userID := user.ID
+	u.dispatch(func() {
+		if err := u.mailer.Send(msg); err != nil {
+			log.Printf("Failed to send the verification mail of user %d: %v", userID, err)
+		}
+	})
+	return nil
+}
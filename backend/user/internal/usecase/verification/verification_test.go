@@ -0,0 +1,178 @@
+package verification
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/testutil"
+	"github.com/yuhari7/backend_supervision/shared/apperror"
+)
+
+var linkPattern = regexp.MustCompile(`http\S+`)
+
+func newVerifications() (*verificationUsecase, *testutil.UserRepository, *testutil.Mailer) {
+	verifiedAt := time.Now()
+	users := testutil.NewUserRepository(
+		entity.User{ID: 1, Name: "Budi", Email: "budi@example.com", IsActive: true},
+		entity.User{ID: 2, Name: "Sari", Email: "sari@example.com", IsActive: true, EmailVerifiedAt: &verifiedAt},
+	)
+	mail := &testutil.Mailer{}
+	u := NewVerificationUsecase(users, &testutil.UserTokenRepository{}, mail, Config{VerifyURL: "https://app.example.com/verify-email"}).(*verificationUsecase)
+	u.dispatch = func(send func()) { send() }
+	return u, users, mail
+}
+
+// verificationToken returns the token of the link in the last mail
+func verificationToken(t *testing.T, mail *testutil.Mailer) string {
+	t.Helper()
+	if len(mail.Sent) == 0 {
+		t.Fatal("no mail was sent")
+	}
+	link, err := url.Parse(linkPattern.FindString(mail.Sent[len(mail.Sent)-1].Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return link.Query().Get("token")
+}
+
+func TestVerify(t *testing.T) {
+	u, users, mail := newVerifications()
+	budi := users.Users[1]
+
+	if err := u.Send(&budi, "en"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mail.Sent[0].To != "budi@example.com" || mail.Sent[0].Subject != "Verify your email" || !strings.Contains(mail.Sent[0].Body, "24 hours") {
+		t.Fatalf("got mail %+v, want an English verification mail to budi valid for 24 hours", mail.Sent[0])
+	}
+	stale := verificationToken(t, mail)
+
+	// A new link replaces the earlier one
+	u.Send(&budi, "id")
+	token := verificationToken(t, mail)
+	if _, err := u.Verify(dto.VerifyEmailRequest{Token: stale}); !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Errorf("got error %v for the replaced link, want ErrInvalidVerificationToken", err)
+	}
+
+	user, err := u.Verify(dto.VerifyEmailRequest{Token: token})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.EmailVerifiedAt == nil || users.Users[1].EmailVerifiedAt == nil {
+		t.Error("email was not marked verified")
+	}
+	if _, err := u.Verify(dto.VerifyEmailRequest{Token: token}); !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Errorf("got error %v reusing the link, want ErrInvalidVerificationToken", err)
+	}
+}
+
+func TestVerifyPendingEmail(t *testing.T) {
+	u, users, mail := newVerifications()
+	pending := "sari@example.org"
+	sari := users.Users[2]
+	sari.PendingEmail = &pending
+	users.Users[2] = sari
+
+	if err := u.Send(&sari, "en"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mail.Sent[0].To != "sari@example.org" {
+		t.Errorf("got mail to %s, want the pending email", mail.Sent[0].To)
+	}
+	if stored := users.Users[2]; stored.Email != "sari@example.com" {
+		t.Errorf("got email %s before verification, want the current one kept", stored.Email)
+	}
+
+	user, err := u.Verify(dto.VerifyEmailRequest{Token: verificationToken(t, mail)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.Email != "sari@example.org" || user.PendingEmail != nil || user.EmailVerifiedAt == nil {
+		t.Errorf("got user %+v, want the pending email verified in place of the current one", user)
+	}
+}
+
+func TestVerifyPendingEmailTakenMeanwhile(t *testing.T) {
+	u, users, mail := newVerifications()
+	taken := "budi@example.com"
+	sari := users.Users[2]
+	sari.PendingEmail = &taken
+	users.Users[2] = sari
+
+	if err := u.Send(&sari, "en"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := u.Verify(dto.VerifyEmailRequest{Token: verificationToken(t, mail)}); !errors.Is(err, ErrEmailAlreadyRegistered) {
+		t.Errorf("got error %v, want ErrEmailAlreadyRegistered", err)
+	}
+	if stored := users.Users[2]; stored.Email != "sari@example.com" {
+		t.Errorf("got email %s, want the current one kept", stored.Email)
+	}
+}
+
+func TestVerificationTokenExpires(t *testing.T) {
+	u, users, mail := newVerifications()
+	budi := users.Users[1]
+	u.Send(&budi, "id")
+
+	u.now = func() time.Time { return time.Now().UTC().Add(25 * time.Hour) }
+	if _, err := u.Verify(dto.VerifyEmailRequest{Token: verificationToken(t, mail)}); !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Errorf("got error %v, want ErrInvalidVerificationToken", err)
+	}
+}
+
+func TestResendIsRateLimited(t *testing.T) {
+	u, _, mail := newVerifications()
+	now := time.Now().UTC()
+	u.now = func() time.Time { return now }
+
+	if err := u.Resend(dto.ResendVerificationRequest{Email: "budi@example.com"}, "id"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := u.Resend(dto.ResendVerificationRequest{Email: "Budi@example.com"}, "id")
+	if !errors.Is(err, ErrResendLimited) || apperror.KindOf(err) != apperror.KindTooManyRequests {
+		t.Fatalf("got error %v within the cooldown, want ErrResendLimited", err)
+	}
+
+	// After the cooldown it works again, until the hourly limit is reached
+	for i := 1; i < 5; i++ {
+		now = now.Add(2 * time.Minute)
+		if err := u.Resend(dto.ResendVerificationRequest{Email: "budi@example.com"}, "id"); err != nil {
+			t.Fatalf("unexpected error on resend %d: %v", i+1, err)
+		}
+	}
+	now = now.Add(2 * time.Minute)
+	if err := u.Resend(dto.ResendVerificationRequest{Email: "budi@example.com"}, "id"); !errors.Is(err, ErrResendLimited) {
+		t.Errorf("got error %v after 5 resends, want ErrResendLimited", err)
+	}
+	if len(mail.Sent) != 5 {
+		t.Errorf("got %d mails, want 5", len(mail.Sent))
+	}
+
+	now = now.Add(time.Hour)
+	if err := u.Resend(dto.ResendVerificationRequest{Email: "budi@example.com"}, "id"); err != nil {
+		t.Errorf("unexpected error an hour later: %v", err)
+	}
+}
+
+func TestResendDoesNotRevealAccounts(t *testing.T) {
+	u, _, mail := newVerifications()
+
+	for _, email := range []string{"nobody@example.com", "sari@example.com"} {
+		if err := u.Resend(dto.ResendVerificationRequest{Email: email}, "id"); err != nil {
+			t.Errorf("got error %v for %s, want the same success as an unverified email", err, email)
+		}
+		if err := u.Resend(dto.ResendVerificationRequest{Email: email}, "id"); !errors.Is(err, ErrResendLimited) {
+			t.Errorf("got error %v resending to %s, want the same limit as an unverified email", err, email)
+		}
+	}
+	if len(mail.Sent) != 0 {
+		t.Errorf("got %d mails, want none for unknown or verified emails", len(mail.Sent))
+	}
+}
@@ -0,0 +1,53 @@
+package verification
+
+import (
+	"errors"
+
+	"github.com/yuhari7/backend_supervision/internal/common/dto"
+	"github.com/yuhari7/backend_supervision/internal/entity"
+	"github.com/yuhari7/backend_supervision/internal/repository"
+	"github.com/yuhari7/backend_supervision/pkg/randtoken"
+)
+
+// Verify marks the email of a user verified with the token of a verification link, the token can only be used once.
+// A pending email replaces the current one.
+func (u *verificationUsecase) Verify(input dto.VerifyEmailRequest) (*entity.User, error) {
+	token, err := u.tokenRepo.Consume(entity.TokenPurposeEmailVerification, randtoken.Hash(input.Token), u.now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.userRepo.FindByID(token.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if user.PendingEmail != nil {
+		// Someone may have registered the email since it was asked for
+		existing, err := u.userRepo.FindByEmail(*user.PendingEmail)
+		if err == nil && existing.ID != user.ID {
+			return nil, ErrEmailAlreadyRegistered
+		}
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		user.Email = *user.PendingEmail
+		user.PendingEmail = nil
+		user.EmailVerifiedAt = nil
+	}
+
+	if user.EmailVerifiedAt == nil {
+		now := u.now()
+		user.EmailVerifiedAt = &now
+		if err := u.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
@@ -0,0 +1,3 @@
+ALTER TABLE refresh_tokens DROP COLUMN token_version;
+
+ALTER TABLE users DROP COLUMN token_version;
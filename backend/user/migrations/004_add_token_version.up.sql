@@ -0,0 +1,4 @@
+-- Tokens carry the version of their user, raising it ends every session issued before
+ALTER TABLE users ADD COLUMN token_version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE refresh_tokens ADD COLUMN token_version INT NOT NULL DEFAULT 1;
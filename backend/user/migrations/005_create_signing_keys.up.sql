@@ -0,0 +1,9 @@
+-- Ed25519 keys access tokens are signed with, published at /.well-known/jwks.json until they expire
+CREATE TABLE signing_keys (
+    id SERIAL PRIMARY KEY,
+    kid VARCHAR(64) NOT NULL UNIQUE,
+    private_key TEXT NOT NULL,
+    activates_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
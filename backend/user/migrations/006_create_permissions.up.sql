@@ -0,0 +1,29 @@
+-- Permissions granted to roles, embedded in access tokens and checked by both services
+CREATE TABLE permissions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+    role_id INT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id INT NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:manage', 'List, create, update, deactivate and delete users'),
+    ('roles:manage', 'Manage roles and the permissions granted to them'),
+    ('articles:create', 'Write new articles'),
+    ('articles:update', 'Edit articles'),
+    ('articles:publish', 'Publish articles'),
+    ('articles:delete', 'Trash and delete articles'),
+    ('webhooks:manage', 'Manage article webhooks and their deliveries');
+
+-- Admins get every permission, contributors write and edit articles
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p WHERE r.name = 'admin';
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON p.name IN ('articles:create', 'articles:update')
+WHERE r.name = 'contributor';
@@ -0,0 +1,2 @@
+-- Avatar chosen by the user, Gravatar is shown when empty
+ALTER TABLE users ADD COLUMN avatar_url VARCHAR(500) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Self-registered users have to verify their email before logging in, existing accounts count as verified
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP NULL;
+
+UPDATE users SET email_verified_at = created_at;
@@ -0,0 +1,12 @@
+-- Invites issued by admins, registering with one assigns its role. Tokens are stored as SHA-256 hashes.
+CREATE TABLE invites (
+    id SERIAL PRIMARY KEY,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    role_id INT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    created_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    used_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+ALTER TABLE roles DROP COLUMN require_mfa;
+DROP TABLE mfa_recovery_codes;
+DROP TABLE user_mfa;
@@ -0,0 +1,24 @@
+-- TOTP two-factor authentication. A row exists from the start of an enrollment, enabled_at is set once a code confirmed it.
+-- The secret is encrypted with MFA_SECRET_KEY since codes are computed from it.
+CREATE TABLE user_mfa (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(128) NOT NULL,
+    enabled_at TIMESTAMP NULL,
+    last_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One-time recovery codes for a lost authenticator, stored as SHA-256 hashes
+CREATE TABLE mfa_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_mfa_recovery_codes_user ON mfa_recovery_codes (user_id) WHERE used_at IS NULL;
+
+-- Users of a role requiring it have to set up two-factor authentication before they can log in
+ALTER TABLE roles ADD COLUMN require_mfa BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+-- A new email asked for in the profile, it replaces email once verified
+ALTER TABLE users ADD COLUMN pending_email VARCHAR(100) NULL;
@@ -1,12 +1,6 @@
 package jwt
 
-import (
-	"github.com/golang-jwt/jwt/v5"
-)
+import "github.com/yuhari7/backend_supervision/shared/auth"
 
-type CustomClaims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	RoleID uint   `json:"role_id"`
-	jwt.RegisteredClaims
-}
+// CustomClaims are the claims of the access tokens, shared with the services verifying them
+type CustomClaims = auth.Claims
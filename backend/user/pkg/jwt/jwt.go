@@ -1,69 +1,48 @@
 package jwt
 
 import (
-	"os"
+	"crypto/ed25519"
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var (
-	secretKey     = []byte(os.Getenv("ACCESS_SECRET"))
-	refreshSecret = []byte(os.Getenv("REFRESH_SECRET"))
+var accessDuration = 15 * time.Minute // access token valid 15 menit
 
-	accessDuration  = 15 * time.Minute   // access token valid 15 menit
-	refreshDuration = 7 * 24 * time.Hour // refresh token valid 7 hari
-)
-
-func GenerateAccessToken(userID uint, email string, roleID uint) (string, error) {
-	claims := CustomClaims{
-		UserID: userID,
-		Email:  email,
-		RoleID: roleID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(secretKey)
-}
-
-func GenerateRefreshToken(userID uint, email string, roleID uint) (string, error) {
-	claims := CustomClaims{
-		UserID: userID,
-		Email:  email,
-		RoleID: roleID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(refreshDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-	}
+// ErrUnknownKey is returned for tokens signed with a key the service does not know
+var ErrUnknownKey = errors.New("token signed with an unknown key")
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(refreshSecret)
+// Keys provides the Ed25519 keys access tokens are signed and verified with
+type Keys interface {
+	// SigningKey returns the key new tokens are signed with and its ID
+	SigningKey() (kid string, key ed25519.PrivateKey, err error)
+	// PublicKey returns the public key with the given ID, ErrUnknownKey when there is none
+	PublicKey(kid string) (ed25519.PublicKey, error)
 }
 
-func ParseRefreshToken(tokenStr string) (*CustomClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return refreshSecret, nil
-	})
+// GenerateAccessToken signs an access token with the claims of a user, valid for 15 minutes from now
+func GenerateAccessToken(keys Keys, claims CustomClaims) (string, error) {
+	kid, key, err := keys.SigningKey()
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	if claims, ok := token.Claims.(*CustomClaims); ok && token.Valid {
-		return claims, nil
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessDuration)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
 	}
 
-	return nil, jwt.ErrTokenInvalidClaims
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = kid
+	return token.SignedString(key)
 }
 
-func ParseAccessToken(tokenStr string) (*CustomClaims, error) {
+func ParseAccessToken(keys Keys, tokenStr string) (*CustomClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenStr, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return secretKey, nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		return keys.PublicKey(kid)
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,66 @@
+package mailer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderFallsBackToDefaultLocale(t *testing.T) {
+	data := map[string]interface{}{"Name": "Budi", "URL": "https://app.example.com/reset-password?token=abc", "ValidMinutes": 60}
+
+	tests := []struct {
+		locale  string
+		subject string
+	}{
+		{"en", "Reset your password"},
+		{"id", "Atur ulang kata sandi"},
+		{"fr", "Atur ulang kata sandi"},
+	}
+	for _, tt := range tests {
+		msg, err := Render("password_reset", tt.locale, "budi@example.com", data)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", tt.locale, err)
+		}
+		if msg.Subject != tt.subject || !strings.Contains(msg.Body, "Budi") || !strings.Contains(msg.Body, "token=abc") {
+			t.Errorf("got %+v for %s, want subject %q with the name and link", msg, tt.locale, tt.subject)
+		}
+	}
+
+	if _, err := Render("missing", "en", "budi@example.com", data); err == nil {
+		t.Error("expected an error for an unknown template")
+	}
+}
+
+func TestFileMailerRefusesHeaderInjection(t *testing.T) {
+	m := &FileMailer{Dir: t.TempDir(), From: "no-reply@example.com"}
+
+	if err := m.Send(Message{To: "budi@example.com", Subject: "Reset your password", Body: "Hi Budi,\n"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files, _ := filepath.Glob(filepath.Join(m.Dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("got %d files, want 1", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	if !strings.Contains(string(data), "To: budi@example.com\r\n") || !strings.HasSuffix(string(data), "\r\n\r\nHi Budi,\r\n") {
+		t.Errorf("got message %q, want headers followed by the body", data)
+	}
+
+	if err := m.Send(Message{To: "budi@example.com\r\nBcc: eve@example.com", Subject: "Hi"}); err == nil {
+		t.Error("expected an error for a recipient with a line break")
+	}
+}
+
+func TestFromEnvRequiresDriver(t *testing.T) {
+	t.Setenv("MAIL_DRIVER", "")
+	if _, err := FromEnv(); err == nil {
+		t.Error("expected an error without MAIL_DRIVER")
+	}
+
+	t.Setenv("MAIL_DRIVER", "log")
+	if m, err := FromEnv(); err != nil || m != (LogMailer{}) {
+		t.Errorf("got mailer %v and error %v, want the log mailer", m, err)
+	}
+}
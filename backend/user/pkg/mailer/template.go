@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// DefaultLocale is used when a template has no translation for the requested locale
+const DefaultLocale = "id"
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// templates holds one template set per file since every file defines its own "subject" and "body"
+var templates = mustParseTemplates(templateFS)
+
+func mustParseTemplates(fsys fs.FS) map[string]*template.Template {
+	files, err := fs.Glob(fsys, "templates/*.tmpl")
+	if err != nil {
+		panic(err)
+	}
+	parsed := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		parsed[strings.TrimSuffix(path.Base(file), ".tmpl")] = template.Must(template.ParseFS(fsys, file))
+	}
+	return parsed
+}
+
+// Render builds the message of the named template in locale, falling back to DefaultLocale.
+// Each template file <name>.<locale>.tmpl defines a "subject" and a "body" template.
+func Render(name, locale, to string, data interface{}) (Message, error) {
+	tmpl, ok := templates[name+"."+locale]
+	if !ok {
+		tmpl, ok = templates[name+"."+DefaultLocale]
+	}
+	if !ok {
+		return Message{}, fmt.Errorf("mail template %q not found", name)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return Message{}, err
+	}
+	return Message{To: to, Subject: strings.TrimSpace(subject.String()), Body: strings.TrimSpace(body.String()) + "\n"}, nil
+}
@@ -0,0 +1,24 @@
+// Package randtoken creates the random tokens mailed to users and the hashes they are stored as.
+package randtoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// New returns 32 random bytes encoded for use in a URL
+func New() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Hash is the form tokens are stored and looked up in, the SHA-256 of the token in hex
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
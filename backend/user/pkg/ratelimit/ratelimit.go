@@ -0,0 +1,56 @@
+// Package ratelimit limits how often something may happen for the same key, such as mails sent to one address.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter allows Limit events per key within Window, at least Cooldown apart.
+// Events are kept in memory, so each instance counts on its own.
+type Limiter struct {
+	limit    int
+	window   time.Duration
+	cooldown time.Duration
+
+	mu     sync.Mutex
+	events map[string][]time.Time // recent event times per key
+}
+
+// New creates a limiter allowing limit events per window, at least cooldown apart
+func New(limit int, window, cooldown time.Duration) *Limiter {
+	return &Limiter{limit: limit, window: window, cooldown: cooldown, events: make(map[string][]time.Time)}
+}
+
+// Allow records an event for key at now unless it came too soon after the last one or the limit was reached
+func (l *Limiter) Allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Drop what fell out of the window, for every key so the map does not grow without bound
+	for k, times := range l.events {
+		recent := times[:0]
+		for _, at := range times {
+			if now.Sub(at) < l.window {
+				recent = append(recent, at)
+			}
+		}
+		if len(recent) == 0 {
+			delete(l.events, k)
+		} else {
+			l.events[k] = recent
+		}
+	}
+
+	times := l.events[key]
+	if len(times) >= l.limit || (len(times) > 0 && now.Sub(times[len(times)-1]) < l.cooldown) {
+		return false
+	}
+	l.events[key] = append(times, now)
+	return true
+}
+
+// Cooldown is the least time between two events of the same key
+func (l *Limiter) Cooldown() time.Duration {
+	return l.cooldown
+}
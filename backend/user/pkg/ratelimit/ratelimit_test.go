@@ -0,0 +1,36 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	limiter := New(3, time.Hour, time.Minute)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if !limiter.Allow("budi", start) {
+		t.Fatal("first event refused")
+	}
+	if limiter.Allow("budi", start.Add(30*time.Second)) {
+		t.Error("event within the cooldown allowed")
+	}
+	if !limiter.Allow("sari", start.Add(30*time.Second)) {
+		t.Error("event of another key refused")
+	}
+
+	// After the cooldown events are allowed until the limit of the window is reached
+	for i := 1; i <= 2; i++ {
+		if !limiter.Allow("budi", start.Add(time.Duration(i)*2*time.Minute)) {
+			t.Fatalf("event %d refused after the cooldown", i+1)
+		}
+	}
+	if limiter.Allow("budi", start.Add(10*time.Minute)) {
+		t.Error("event over the limit allowed")
+	}
+
+	// Events falling out of the window free the limit again
+	if !limiter.Allow("budi", start.Add(time.Hour+time.Minute)) {
+		t.Error("event refused once the first ones left the window")
+	}
+}
@@ -0,0 +1,80 @@
+// Package secretbox encrypts the secrets stored in the database that have to be read back, such as TOTP secrets.
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks sealed values, so they can be told apart from values stored before encryption
+const prefix = "v1:"
+
+// ErrInvalid is returned for values that were not sealed with the key of the box or were altered
+var ErrInvalid = errors.New("secretbox: invalid sealed value")
+
+// Box seals values with AES-256-GCM
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New creates a box from a 32 byte key
+func New(key []byte) (*Box, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("secretbox: key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Box{aead: aead}, nil
+}
+
+// FromBase64 creates a box from a base64 encoded key, as generated by `openssl rand -base64 32`
+func FromBase64(encoded string) (*Box, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: key is not base64: %w", err)
+	}
+	return New(key)
+}
+
+// Seal encrypts value with a random nonce
+func (b *Box) Seal(value string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(value), nil)
+	return prefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value returned by Seal
+func (b *Box) Open(sealed string) (string, error) {
+	if !IsSealed(sealed) {
+		return "", ErrInvalid
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(sealed, prefix))
+	if err != nil || len(raw) < b.aead.NonceSize() {
+		return "", ErrInvalid
+	}
+	nonce, ciphertext := raw[:b.aead.NonceSize()], raw[b.aead.NonceSize():]
+	value, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	return string(value), nil
+}
+
+// IsSealed reports whether value was returned by Seal rather than stored in clear
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
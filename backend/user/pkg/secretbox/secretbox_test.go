@@ -0,0 +1,50 @@
+package secretbox
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	box, err := New(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := box.Seal("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealed(sealed) || sealed == "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("got %q, want a sealed value", sealed)
+	}
+	if again, _ := box.Seal("JBSWY3DPEHPK3PXP"); again == sealed {
+		t.Error("got the same sealed value twice, want a random nonce")
+	}
+	if value, err := box.Open(sealed); err != nil || value != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("got %q and error %v, want the original value", value, err)
+	}
+
+	other, _ := New(bytes.Repeat([]byte{2}, 32))
+	for _, value := range []string{"JBSWY3DPEHPK3PXP", sealed[:len(sealed)-2] + "AA"} {
+		if _, err := box.Open(value); !errors.Is(err, ErrInvalid) {
+			t.Errorf("got error %v opening %q, want ErrInvalid", err, value)
+		}
+	}
+	if _, err := other.Open(sealed); !errors.Is(err, ErrInvalid) {
+		t.Errorf("got error %v with another key, want ErrInvalid", err)
+	}
+}
+
+func TestFromBase64(t *testing.T) {
+	if _, err := FromBase64(""); err == nil {
+		t.Error("expected an error for a missing key")
+	}
+	if _, err := FromBase64("c2hvcnQ="); err == nil {
+		t.Error("expected an error for a short key")
+	}
+	if _, err := FromBase64("AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE="); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+// Package servicetoken checks the static bearer tokens other services authenticate with.
+package servicetoken
+
+import (
+	"crypto/subtle"
+	"os"
+	"strings"
+)
+
+// FromEnv returns the comma separated tokens of SERVICE_TOKENS
+func FromEnv() []string {
+	var tokens []string
+	for _, token := range strings.Split(os.Getenv("SERVICE_TOKENS"), ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// Valid compares in constant time so tokens cannot be guessed from response times
+func Valid(tokens []string, token string) bool {
+	valid := false
+	for _, candidate := range tokens {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
@@ -0,0 +1,26 @@
+package servicetoken
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{"several", "first, second,", []string{"first", "second"}},
+		{"one", "first", []string{"first"}},
+		{"none", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SERVICE_TOKENS", tt.value)
+			if got := FromEnv(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got tokens %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCodeMatchesRFC6238(t *testing.T) {
+	// The RFC lists eight digits, a code is their last six
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("got code %s at %d, want %s", got, tt.unix, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+	previous, _ := Code(rfcSecret, current-1)
+	old, _ := Code(rfcSecret, current-2)
+
+	if step, ok := Validate(rfcSecret, "050471", now, 0); !ok || step != current {
+		t.Errorf("got step %d and %v, want the current step", step, ok)
+	}
+	if _, ok := Validate(rfcSecret, previous, now, 0); !ok {
+		t.Error("expected the code of the previous period to be accepted")
+	}
+	if _, ok := Validate(rfcSecret, old, now, 0); ok {
+		t.Error("expected a code two periods old to be refused")
+	}
+	if _, ok := Validate(rfcSecret, "050471", now, current); ok {
+		t.Error("expected a code of an already accepted step to be refused")
+	}
+	if _, ok := Validate(rfcSecret, "50471", now, 0); ok {
+		t.Error("expected a code of the wrong length to be refused")
+	}
+	if _, ok := Validate("not base32!", "050471", now, 0); ok {
+		t.Error("expected an invalid secret to refuse every code")
+	}
+}
+
+func TestNewSecretAndURI(t *testing.T) {
+	secret, err := NewSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("got secret %q, want 32 base32 characters", secret)
+	}
+	if _, err := Code(secret, 1); err != nil {
+		t.Errorf("generated secret is not usable: %v", err)
+	}
+
+	uri, err := url.Parse(URI("Supervision", "budi@example.com", secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Supervision:budi@example.com" {
+		t.Errorf("got URI %s, want an otpauth totp URI labelled with the issuer and account", uri)
+	}
+	if query := uri.Query(); query.Get("secret") != secret || query.Get("issuer") != "Supervision" || query.Get("digits") != "6" {
+		t.Errorf("got parameters %v", query)
+	}
+}
@@ -63,7 +63,7 @@ export default function AddNew() {
         method: "POST",
         headers: {
           "Content-Type": "application/json",
-          // Tambahkan otentikasi jika diperlukan
+          Authorization: `Bearer ${localStorage.getItem("access_token")}`,
         },
         body: JSON.stringify({ ...article, Status: status }),
       });
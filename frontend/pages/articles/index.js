@@ -80,7 +80,7 @@ export default function AllPosts() {
             method: "DELETE",
             headers: {
               "Content-Type": "application/json",
-              // Tambahkan otentikasi jika diperlukan
+              Authorization: `Bearer ${localStorage.getItem("access_token")}`,
             },
           });
 
@@ -110,7 +110,7 @@ export default function AllPosts() {
               method: "PUT",
               headers: {
                 "Content-Type": "application/json",
-                // Tambahkan otentikasi jika diperlukan
+                Authorization: `Bearer ${localStorage.getItem("access_token")}`,
               },
             }
           );